@@ -0,0 +1,160 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"multi-timer/config"
+	"multi-timer/manager"
+	"multi-timer/ui"
+)
+
+// Subcommands do one thing and exit, so the timers can be driven from scripts
+// and shell aliases:
+//
+//	multi-timer start "Writing" --work 25m --break 5m --cycles 4
+//	multi-timer start Tea --preset pomodoro
+//	multi-timer list
+//	multi-timer pause 2
+//	multi-timer reset 2
+//	multi-timer export --format json --from 2024-03-01
+//
+// With a daemon running they act on its timers. Without one start and list
+// work on the saved timers, which run the next time multi-timer starts, and
+// pause and reset have nothing to act on. Export only reads the history.
+var subcommands = map[string]func(args []string, configPath string, out io.Writer) error{
+	"start":  startCommand,
+	"list":   listCommand,
+	"pause":  timerCommand("pause"),
+	"reset":  timerCommand("reset"),
+	"export": manager.ExportCommand,
+}
+
+const subcommandUsage = `Usage: multi-timer [flags] [command]
+
+Without a command the timers run in the terminal. Commands:
+  start <name> [--work 25m] [--break 5m] [--cycles 4] [--preset name]
+                   add a timer, cycles 0 runs until deleted
+  list             list the timers
+  pause <number>   pause or resume a timer
+  reset <number>   reset a timer
+  export [--format csv|json] [--from YYYY-MM-DD] [--to YYYY-MM-DD] [--output file]
+                   write the work and break sessions of the history
+  daemon           run the timers in the background for the commands above
+  client <command> send any daemon command, see "client help"
+
+Flags:
+`
+
+func startCommand(args []string, configPath string, out io.Writer) error {
+	set := flag.NewFlagSet("start", flag.ContinueOnError)
+	set.SetOutput(out)
+	work := set.String("work", "25m", "work duration, e.g. 25m or 25:00")
+	rest := set.String("break", "5m", "break duration")
+	cycles := set.Int("cycles", 0, "number of cycles, 0 runs until deleted")
+	preset := set.String("preset", "", "start a preset instead, see the presets command")
+
+	// The name may come before the flags, which the flag package stops at
+	var name string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	if err := set.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if name == "" && set.NArg() > 0 {
+		name = strings.Join(set.Args(), " ")
+	} else if set.NArg() > 0 {
+		return fmt.Errorf("unexpected %q after the flags", strings.Join(set.Args(), " "))
+	}
+
+	var cfg config.TimerConfig
+	var command []string
+	if *preset != "" {
+		var err error
+		if cfg, err = config.FindPreset(*preset); err != nil {
+			return err
+		}
+		if name != "" {
+			cfg.Name = name
+		}
+		command = []string{"start", *preset, cfg.Name}
+	} else {
+		if name == "" {
+			name = "Timer"
+		}
+		var err error
+		cfg, err = manager.NewTimer{Name: name, Work: *work, Break: *rest, Cycles: *cycles}.Config()
+		if err != nil {
+			return err
+		}
+		phase := cfg.Phases[0]
+		command = []string{"add", cfg.Name, phase.WorkDuration.String(), phase.BreakDuration.String()}
+		if cfg.MaxCycles > 0 {
+			command = append(command, strconv.Itoa(cfg.MaxCycles))
+		}
+	}
+
+	err := manager.RunClient(config.DataFile(manager.SocketFile), command, out)
+	if !errors.Is(err, manager.ErrNoDaemon) {
+		return err
+	}
+
+	configs, err := config.LoadTimerConfigs(configPath)
+	if err != nil {
+		return err
+	}
+	if err := config.SaveTimerConfigs(configPath, append(configs, cfg)); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Saved %s, no daemon is running so it starts with the next multi-timer.\n", cfg.Name)
+	return nil
+}
+
+func listCommand(args []string, configPath string, out io.Writer) error {
+	if len(args) > 0 {
+		return fmt.Errorf("list takes no arguments")
+	}
+	err := manager.RunClient(config.DataFile(manager.SocketFile), []string{"list"}, out)
+	if !errors.Is(err, manager.ErrNoDaemon) {
+		return err
+	}
+
+	configs, err := config.LoadTimerConfigs(configPath)
+	if err != nil {
+		return err
+	}
+	if len(configs) == 0 {
+		fmt.Fprintln(out, "No saved timers.")
+		return nil
+	}
+	fmt.Fprintln(out, "No daemon running, the saved timers are:")
+	for i, config := range configs {
+		fmt.Fprintf(out, "%d. %s\n", i+1, ui.PresetLine(config))
+	}
+	return nil
+}
+
+// timerCommand acts on one of the daemon's timers by number
+func timerCommand(name string) func(args []string, configPath string, out io.Writer) error {
+	return func(args []string, configPath string, out io.Writer) error {
+		if len(args) != 1 {
+			return fmt.Errorf("usage: multi-timer %s <number>", name)
+		}
+		if _, err := strconv.Atoi(args[0]); err != nil {
+			return fmt.Errorf("invalid timer number %q", args[0])
+		}
+		err := manager.RunClient(config.DataFile(manager.SocketFile), []string{name, args[0]}, out)
+		if errors.Is(err, manager.ErrNoDaemon) {
+			return fmt.Errorf("%s needs the timers running in the background, start them with multi-timer daemon", name)
+		}
+		return err
+	}
+}
@@ -0,0 +1,18 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+// Phases saved before they had a color and sound load without them
+func TestPhaseWithoutColorLoads(t *testing.T) {
+	var configs []TimerConfig
+	data := `[{"Name": "Tea", "Phases": [{"WorkDuration": "25m", "BreakDuration": "5m"}], "MaxCycles": 4}]`
+	if err := (jsonFormat{}).unmarshal([]byte(data), &configs); err != nil {
+		t.Fatal(err)
+	}
+	if phase := configs[0].Phases[0]; phase.PhaseColor != "" || phase.PhaseSound != "" || phase.WorkDuration != 25*time.Minute {
+		t.Errorf("phase = %+v, want 25m of work without a color or sound", phase)
+	}
+}
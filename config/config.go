@@ -0,0 +1,301 @@
+// Package config holds the saved settings: the timer configs and the formats
+// they are read and written in, the preferences, presets and the data
+// directory they all live in.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimerPhase is one phase of a timer's cycles: how long its work and
+// break run and what it shows and plays meanwhile
+type TimerPhase struct {
+	WorkDuration  time.Duration
+	BreakDuration time.Duration // UntilResumed for a break that waits for "resume"
+	Outcome       string        // what the phase should achieve, reviewed when it completes
+	PhaseColor    string        // see colors, shown while the phase runs
+	PhaseSound    string        // see sounds, played when its work or break starts
+
+	// Multiples of the config's BaseDuration, 0 keeps the duration as is
+	WorkScale  float64
+	BreakScale float64
+
+	// HH:MM-HH:MM the phase runs in, outside it the phase is skipped
+	ActiveHours string
+}
+
+// UntilResumed as a break duration keeps the timer on break until resumed
+const UntilResumed time.Duration = -1
+
+// TimerConfig is a saved timer, what the config file holds a list of
+type TimerConfig struct {
+	Name      string
+	NotifText string
+	Type      string `json:",omitempty"` // see TypeCountdown and TypeAlarm, empty for work and break cycles
+	AlarmAt   string `json:",omitempty"` // HH:MM an alarm goes off at
+	Phases    []TimerPhase
+	MaxCycles int
+	StartAt   string   `json:",omitempty"` // HH:MM to start automatically, empty starts on launch
+	Days      []string `json:",omitempty"` // weekdays StartAt applies to, empty for every day
+
+	// Run once before the first and after the last cycle, not counted as cycles
+	WarmupDuration   time.Duration `json:",omitempty"`
+	CooldownDuration time.Duration `json:",omitempty"`
+
+	// Only one timer of a group runs at a time, starting one pauses the rest
+	ExclusiveGroup string `json:",omitempty"`
+
+	// The timers of a linked group pause and resume together
+	LinkedGroup string `json:",omitempty"`
+
+	// Name of the timer to start when this one completes
+	NextTimer string `json:",omitempty"`
+
+	// Names of the timers to pause whenever this one starts work
+	PausesOnWork []string `json:",omitempty"`
+
+	// What the WorkScale and BreakScale of the phases are multiples of
+	BaseDuration time.Duration `json:",omitempty"`
+
+	// Replace the work and break of every phase's last cycle, e.g. to finish strong
+	FinalCycleWorkDuration  time.Duration `json:",omitempty"`
+	FinalCycleBreakDuration time.Duration `json:",omitempty"`
+
+	// Sounds played when the timer starts and when it has completed, see sounds
+	StartSound    string `json:",omitempty"`
+	CompleteSound string `json:",omitempty"`
+
+	// Sounds played whenever a work or a break segment starts, unless the
+	// phase has a PhaseSound
+	WorkSound  string `json:",omitempty"`
+	BreakSound string `json:",omitempty"`
+
+	// Repeat notifications as critical until acknowledged, see escalation
+	EscalateDuration time.Duration `json:",omitempty"`
+	EscalateRepeats  int           `json:",omitempty"`
+
+	// Notify at these percentages of work segments at least MilestoneMinDuration long
+	Milestones           []int         `json:",omitempty"`
+	MilestoneMinDuration time.Duration `json:",omitempty"`
+
+	// Cycles that can't be paused, skipped or have their time changed
+	ProtectedCycles []int `json:",omitempty"`
+
+	// Notify with ReminderText every ReminderDuration the timer runs, whatever the phase
+	ReminderText     string        `json:",omitempty"`
+	ReminderDuration time.Duration `json:",omitempty"`
+
+	// Suggested one after the other in break notifications, e.g. stretch, walk, water
+	BreakActivities []string `json:",omitempty"`
+
+	// Labels for acting on several timers at once, e.g. pause-tag
+	Tags []string `json:",omitempty"`
+
+	// Take TaperWorkDuration off the work and add TaperBreakDuration to the
+	// break with every cycle, within the min and max
+	TaperWorkDuration     time.Duration `json:",omitempty"`
+	TaperBreakDuration    time.Duration `json:",omitempty"`
+	TaperMinWorkDuration  time.Duration `json:",omitempty"`
+	TaperMaxBreakDuration time.Duration `json:",omitempty"`
+
+	// URLs to post work starts, break starts and completion to, besides those in the preferences
+	Webhooks []string `json:",omitempty"`
+
+	// phase-major (the default) runs all cycles of a phase before the next
+	// phase, cycle-major runs one cycle of each phase in turn and repeats
+	Order string `json:",omitempty"`
+
+	// Pause work while the screen is locked or the user is away, see autopause
+	AutoPauseOnIdle bool `json:",omitempty"`
+
+	// When the last cycles completed, oldest first and at most recentLimit
+	RecentCompletions []time.Time `json:",omitempty"`
+}
+
+// MarshalJSON and UnmarshalJSON handle Duration serialization
+func (p TimerPhase) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		WorkDuration  int64
+		BreakDuration int64
+		Outcome       string  `json:",omitempty"`
+		PhaseColor    string  `json:",omitempty"`
+		PhaseSound    string  `json:",omitempty"`
+		WorkScale     float64 `json:",omitempty"`
+		BreakScale    float64 `json:",omitempty"`
+		ActiveHours   string  `json:",omitempty"`
+	}{
+		WorkDuration:  int64(p.WorkDuration),
+		BreakDuration: int64(p.BreakDuration),
+		Outcome:       p.Outcome,
+		PhaseColor:    p.PhaseColor,
+		PhaseSound:    p.PhaseSound,
+		WorkScale:     p.WorkScale,
+		BreakScale:    p.BreakScale,
+		ActiveHours:   p.ActiveHours,
+	})
+}
+
+// UnmarshalJSON reads durations written out like "25m0s" as well as the
+// nanoseconds of files saved before they were
+func (p *TimerPhase) UnmarshalJSON(data []byte) error {
+	aux := &struct {
+		WorkDuration  int64
+		BreakDuration int64
+		Outcome       string
+		PhaseColor    string
+		PhaseSound    string
+		WorkScale     float64
+		BreakScale    float64
+		ActiveHours   string
+	}{}
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+	p.WorkDuration = time.Duration(aux.WorkDuration)
+	p.BreakDuration = time.Duration(aux.BreakDuration)
+	p.Outcome = aux.Outcome
+	p.PhaseColor = aux.PhaseColor
+	p.PhaseSound = aux.PhaseSound
+	p.WorkScale = aux.WorkScale
+	p.BreakScale = aux.BreakScale
+	p.ActiveHours = aux.ActiveHours
+	return nil
+}
+
+// ConfigFile is the name of the timers file in the data directory
+const ConfigFile = "timers.json"
+
+// Values of TimerConfig.Order, empty is phase-major
+const (
+	orderPhaseMajor = "phase-major"
+	OrderCycleMajor = "cycle-major"
+)
+
+// ValidOrder reports whether order is a value of TimerConfig.Order
+func ValidOrder(order string) bool {
+	return order == "" || order == orderPhaseMajor || order == OrderCycleMajor
+}
+
+// SaveTimerConfigs writes the configs to path in the format its extension
+// names
+func SaveTimerConfigs(path string, configs []TimerConfig) error {
+	data, err := MarshalTimerConfigs(path, configs)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// MarshalTimerConfigs is the content SaveTimerConfigs writes to path for the
+// configs
+func MarshalTimerConfigs(path string, configs []TimerConfig) ([]byte, error) {
+	return formatForPath(path).marshal(configs)
+}
+
+// LoadTimerConfigs reads the configs saved at path, none if there is no
+// file yet. Configs out of range are an error.
+func LoadTimerConfigs(path string) ([]TimerConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []TimerConfig{}, nil
+		}
+		return nil, err
+	}
+
+	var configs []TimerConfig
+	if err := formatForPath(path).unmarshal(data, &configs); err != nil {
+		return nil, err
+	}
+	for i, config := range configs {
+		// The base may have been edited without the durations
+		configs[i].Phases = ResolvePhases(config.Phases, config.BaseDuration)
+		if err := CheckLimits(configs[i]); err != nil {
+			return nil, fmt.Errorf("%s: %v", timerLabel(i, config.Name), err)
+		}
+	}
+	return configs, nil
+}
+
+// ParseDuration reads a duration typed in: MM:SS, HH:MM:SS, whole minutes or
+// Go style like 1h30m
+func ParseDuration(input string) (time.Duration, error) {
+	input = strings.TrimSpace(input)
+	if strings.Count(input, ":") == 2 {
+		hours, rest, _ := strings.Cut(input, ":")
+		h, err := strconv.Atoi(hours)
+		if err != nil || h < 0 || strings.HasPrefix(rest, "-") {
+			return 0, fmt.Errorf("invalid numbers")
+		}
+		if h > int(MaxDuration/time.Hour) {
+			return 0, errTooLong
+		}
+		minutes, seconds, _ := strings.Cut(rest, ":")
+		m, err1 := strconv.Atoi(minutes)
+		s, err2 := strconv.Atoi(seconds)
+		if err1 != nil || err2 != nil || m < 0 || m > 59 || s < 0 || s > 59 {
+			return 0, fmt.Errorf("invalid numbers, use HH:MM:SS")
+		}
+		return checkDuration(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second)
+	}
+	if strings.Contains(input, ":") {
+		parts := strings.Split(input, ":")
+		if len(parts) != 2 {
+			return 0, fmt.Errorf("invalid format, use MM:SS or HH:MM:SS")
+		}
+		minutes, err1 := strconv.Atoi(parts[0])
+		seconds, err2 := strconv.Atoi(parts[1])
+		if err1 != nil || err2 != nil {
+			return 0, fmt.Errorf("invalid numbers")
+		}
+		// Checked before multiplying so huge values can't wrap around
+		if minutes > int(MaxDuration/time.Minute) || seconds > int(MaxDuration/time.Second) {
+			return 0, errTooLong
+		}
+		return checkDuration(time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second)
+	}
+	minutes, err := strconv.Atoi(input)
+	if err != nil {
+		// Also accept Go style durations like "30m" or "1h30m"
+		if d, err := time.ParseDuration(input); err == nil {
+			return checkDuration(d)
+		}
+		if errors.Is(err, strconv.ErrRange) {
+			return 0, errTooLong
+		}
+		return 0, err
+	}
+	if minutes > int(MaxDuration/time.Minute) || minutes < -int(MaxDuration/time.Minute) {
+		return 0, errTooLong
+	}
+	return time.Duration(minutes) * time.Minute, nil
+}
+
+func checkDuration(d time.Duration) (time.Duration, error) {
+	if d > MaxDuration || d < -MaxDuration {
+		return 0, errTooLong
+	}
+	return d, nil
+}
+
+// maxSpeed is the fastest the speed command runs timers, an hour a second
+const maxSpeed = 3600
+
+// ParseSpeed reads the factor of the speed command
+func ParseSpeed(input string) (float64, error) {
+	f, err := strconv.ParseFloat(input, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) || f <= 0 {
+		return 0, fmt.Errorf("invalid speed factor, use a positive number like 10")
+	}
+	if f > maxSpeed {
+		return 0, fmt.Errorf("speed factor too large, at most %d", maxSpeed)
+	}
+	return f, nil
+}
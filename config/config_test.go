@@ -0,0 +1,28 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+// pomodoro is a config of cycles of work and break, -1 for unlimited
+func pomodoro(name string, work, rest time.Duration, cycles int) TimerConfig {
+	return TimerConfig{
+		Name:      name,
+		Phases:    []TimerPhase{{WorkDuration: work, BreakDuration: rest}},
+		MaxCycles: cycles,
+	}
+}
+
+func TestParseSpeed(t *testing.T) {
+	for input, want := range map[string]float64{"10": 10, "0.5": 0.5, "1": 1, "3600": maxSpeed} {
+		if got, err := ParseSpeed(input); err != nil || got != want {
+			t.Errorf("ParseSpeed(%q) = %v, %v, want %v", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"0", "-2", "NaN", "Inf", "+Inf", "3601", "1e300", "fast"} {
+		if got, err := ParseSpeed(input); err == nil {
+			t.Errorf("ParseSpeed(%q) = %v, want an error", input, got)
+		}
+	}
+}
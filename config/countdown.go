@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Values of TimerConfig.Type, empty is the usual work and break cycles. A
+// countdown runs the work of its one phase once and completes, an alarm counts
+// down to the next AlarmAt on the clock.
+const (
+	TypeCountdown = "countdown"
+	TypeAlarm     = "alarm"
+)
+
+// ValidType reports whether kind is a value of TimerConfig.Type
+func ValidType(kind string) bool {
+	return kind == "" || kind == TypeCountdown || kind == TypeAlarm
+}
+
+// UntilAlarm is how long from now until the clock next reads at (HH:MM),
+// a whole day when it reads that right now
+func UntilAlarm(at string, now time.Time) (time.Duration, error) {
+	clock, err := time.Parse("15:04", at)
+	if err != nil {
+		return 0, fmt.Errorf("invalid alarm time %q, use HH:MM", at)
+	}
+	year, month, day := now.Date()
+	next := time.Date(year, month, day, clock.Hour(), clock.Minute(), 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next.Sub(now), nil
+}
+
+// CountdownConfig is a one-shot timer that counts d down once
+func CountdownConfig(name string, d time.Duration) TimerConfig {
+	return TimerConfig{
+		Name:      name,
+		NotifText: name,
+		Type:      TypeCountdown,
+		Phases:    []TimerPhase{{WorkDuration: d}},
+		MaxCycles: 1,
+	}
+}
+
+// AlarmConfig is an alarm for the clock time at, the phase is only a
+// placeholder as the time left is worked out when the timer starts
+func AlarmConfig(name, at string) TimerConfig {
+	return TimerConfig{
+		Name:      name,
+		NotifText: name,
+		Type:      TypeAlarm,
+		AlarmAt:   at,
+		Phases:    []TimerPhase{{}},
+		MaxCycles: 1,
+	}
+}
+
+// AlarmPhases stands in for the placeholder phase of an alarm, running until
+// it goes off
+func AlarmPhases(at string, now time.Time) []TimerPhase {
+	d, _ := UntilAlarm(at, now)
+	return []TimerPhase{{WorkDuration: d}}
+}
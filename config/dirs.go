@@ -0,0 +1,26 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// DataDir holds the configs, preferences, history and the rest of what the
+// app saves. It is multi-timer under the user config directory, which is
+// $XDG_CONFIG_HOME or ~/.config on Linux, unless -config-dir says otherwise.
+var DataDir = "."
+
+// DataFile is where the named file lives in the data directory
+func DataFile(name string) string {
+	return filepath.Join(DataDir, name)
+}
+
+// DefaultDataDir is multi-timer under the user config directory, or the
+// working directory when there is no such directory
+func DefaultDataDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "."
+	}
+	return filepath.Join(dir, "multi-timer")
+}
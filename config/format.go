@@ -0,0 +1,191 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// configFormat reads and writes the saved timer configs in one file format
+type configFormat interface {
+	marshal(configs []TimerConfig) ([]byte, error)
+	unmarshal(data []byte, configs *[]TimerConfig) error
+}
+
+var configFormats = map[string]configFormat{
+	".json": jsonFormat{},
+	".yaml": yamlFormat{},
+	".yml":  yamlFormat{},
+	".toml": tomlFormat{},
+}
+
+// formatForPath picks the format from the file extension, defaulting to JSON
+func formatForPath(path string) configFormat {
+	if format, ok := configFormats[strings.ToLower(filepath.Ext(path))]; ok {
+		return format
+	}
+	return jsonFormat{}
+}
+
+// ConfigFileFor returns the config file for the requested format. Without a
+// format the first existing timers.* file is used so the extension decides.
+func ConfigFileFor(format string) (string, error) {
+	base := DataFile(strings.TrimSuffix(ConfigFile, filepath.Ext(ConfigFile)))
+	if format != "" {
+		ext := "." + strings.ToLower(format)
+		if _, ok := configFormats[ext]; !ok {
+			return "", fmt.Errorf("unknown format %q, use json, yaml or toml", format)
+		}
+		return base + ext, nil
+	}
+	for _, ext := range []string{".json", ".yaml", ".yml", ".toml"} {
+		if _, err := os.Stat(base + ext); err == nil {
+			return base + ext, nil
+		}
+	}
+	return DataFile(ConfigFile), nil
+}
+
+type jsonFormat struct{}
+
+func (jsonFormat) marshal(configs []TimerConfig) ([]byte, error) {
+	doc, err := toDocument(configs)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	err = json.NewEncoder(&buf).Encode(doc)
+	return buf.Bytes(), err
+}
+
+// JSON files saved before durations were written out have them in
+// nanoseconds, so numbers are still read as those
+func (jsonFormat) unmarshal(data []byte, configs *[]TimerConfig) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return err
+	}
+	return fromDocument(doc, configs, true)
+}
+
+type yamlFormat struct{}
+
+func (yamlFormat) marshal(configs []TimerConfig) ([]byte, error) {
+	doc, err := toDocument(configs)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(doc)
+}
+
+func (yamlFormat) unmarshal(data []byte, configs *[]TimerConfig) error {
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	return fromDocument(doc, configs, false)
+}
+
+type tomlFormat struct{}
+
+// TOML needs a table at the top level, so the timers live under one key
+type tomlDocument struct {
+	Timers any `toml:"timers"`
+}
+
+func (tomlFormat) marshal(configs []TimerConfig) ([]byte, error) {
+	doc, err := toDocument(configs)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	err = toml.NewEncoder(&buf).Encode(tomlDocument{Timers: doc})
+	return buf.Bytes(), err
+}
+
+func (tomlFormat) unmarshal(data []byte, configs *[]TimerConfig) error {
+	var doc tomlDocument
+	if err := toml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	if doc.Timers == nil {
+		*configs = []TimerConfig{}
+		return nil
+	}
+	return fromDocument(doc.Timers, configs, false)
+}
+
+// toDocument turns the configs into plain maps and lists, going through JSON so
+// every field is covered. Fields ending in "Duration" become strings like "25m0s".
+func toDocument(configs []TimerConfig) (any, error) {
+	data, err := json.Marshal(configs)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return humanizeDurations(doc), nil
+}
+
+// fromDocument decodes the plain maps and lists of a config file into the
+// configs once checkConfigs is happy with them
+func fromDocument(doc any, configs *[]TimerConfig, nanos bool) error {
+	if doc == nil {
+		*configs = []TimerConfig{}
+		return nil
+	}
+	doc, err := checkConfigs(doc, nanos)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, configs)
+}
+
+func humanizeDurations(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		for key, field := range value {
+			if field == nil {
+				// Neither YAML nor TOML needs the empty fields
+				delete(value, key)
+				continue
+			}
+			if n, ok := field.(json.Number); ok && strings.HasSuffix(key, "Duration") {
+				if ns, err := n.Int64(); err == nil {
+					value[key] = time.Duration(ns).String()
+					continue
+				}
+			}
+			value[key] = humanizeDurations(field)
+		}
+	case []any:
+		for i := range value {
+			value[i] = humanizeDurations(value[i])
+		}
+	case json.Number:
+		if n, err := value.Int64(); err == nil {
+			return n
+		}
+		if f, err := value.Float64(); err == nil {
+			return f
+		}
+	}
+	return value
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sampleConfigs() []TimerConfig {
+	return []TimerConfig{
+		{
+			Name:      "Tea",
+			NotifText: "Tea time",
+			Phases: []TimerPhase{
+				{WorkDuration: 25 * time.Minute, BreakDuration: 5 * time.Minute, Outcome: "draft", PhaseColor: "green"},
+				{WorkDuration: 50 * time.Minute, BreakDuration: UntilResumed, WorkScale: 2},
+			},
+			MaxCycles:         4,
+			BaseDuration:      25 * time.Minute,
+			WarmupDuration:    90 * time.Second,
+			Tags:              []string{"study", "deep"},
+			Milestones:        []int{25, 50},
+			AutoPauseOnIdle:   true,
+			RecentCompletions: []time.Time{time.Date(2024, 3, 4, 9, 30, 0, 0, time.UTC)},
+		},
+		{
+			Name:      "Stretch",
+			NotifText: "Stretch",
+			Phases:    []TimerPhase{{WorkDuration: time.Hour + 30*time.Minute, BreakDuration: 10 * time.Minute}},
+			MaxCycles: -1,
+		},
+	}
+}
+
+func TestFormatsRoundTrip(t *testing.T) {
+	for _, ext := range []string{".json", ".yaml", ".toml"} {
+		t.Run(ext, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "timers"+ext)
+			configs := sampleConfigs()
+			if err := SaveTimerConfigs(path, configs); err != nil {
+				t.Fatal(err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range []string{"25m0s", "1h30m0s", "1m30s"} {
+				if !strings.Contains(string(data), want) {
+					t.Errorf("saved file has no %q:\n%s", want, data)
+				}
+			}
+			if strings.Contains(string(data), "1500000000000") {
+				t.Errorf("saved file has durations in nanoseconds:\n%s", data)
+			}
+
+			loaded, err := LoadTimerConfigs(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(loaded, configs) {
+				t.Errorf("loaded %+v\nwant   %+v", loaded, configs)
+			}
+		})
+	}
+}
+
+func TestFormatForPath(t *testing.T) {
+	for path, want := range map[string]configFormat{
+		"timers.json": jsonFormat{},
+		"timers.YAML": yamlFormat{},
+		"timers.yml":  yamlFormat{},
+		"timers.toml": tomlFormat{},
+		"timers":      jsonFormat{},
+	} {
+		if got := formatForPath(path); got != want {
+			t.Errorf("formatForPath(%q) = %T, want %T", path, got, want)
+		}
+	}
+}
+
+// JSON saved before durations were written out has them in nanoseconds
+func TestJSONNanosecondsStillLoad(t *testing.T) {
+	var configs []TimerConfig
+	data := `[{"Name": "Tea", "Phases": [{"WorkDuration": 1500000000000, "BreakDuration": 300000000000}], "MaxCycles": 4}]`
+	if err := (jsonFormat{}).unmarshal([]byte(data), &configs); err != nil {
+		t.Fatal(err)
+	}
+	if phase := configs[0].Phases[0]; phase.WorkDuration != 25*time.Minute || phase.BreakDuration != 5*time.Minute {
+		t.Errorf("phase = %+v, want 25m of work and 5m of break", phase)
+	}
+}
+
+func TestBareNumberIsNoDuration(t *testing.T) {
+	for name, file := range map[string]struct {
+		format configFormat
+		data   string
+	}{
+		"yaml": {yamlFormat{}, "- Name: Tea\n  Phases:\n    - WorkDuration: 25\n      BreakDuration: 5m\n"},
+		"toml": {tomlFormat{}, "[[timers]]\nName = \"Tea\"\n[[timers.Phases]]\nWorkDuration = 25\nBreakDuration = \"5m\"\n"},
+	} {
+		var configs []TimerConfig
+		err := file.format.unmarshal([]byte(file.data), &configs)
+		if err == nil || !strings.Contains(err.Error(), "phase 1 WorkDuration: want a duration") {
+			t.Errorf("%s: got error %v, want one about phase 1 WorkDuration", name, err)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package config
+
+import "fmt"
+
+// ActiveWindow parses a phase's ActiveHours, the same daily windows as quiet
+// hours. Empty hours give a window without a Start.
+func ActiveWindow(hours string) (window QuietHours, err error) {
+	if hours == "" {
+		return QuietHours{}, nil
+	}
+	window, err = ParseQuietHours(hours, "")
+	if err != nil {
+		return QuietHours{}, fmt.Errorf("active hours: %v", err)
+	}
+	return window, nil
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Upper bounds for what a timer can be set to, far beyond any real use but low
+// enough that no arithmetic on them overflows
+const (
+	MaxDuration   = 7 * 24 * time.Hour
+	MaxCycleCount = 10000
+)
+
+var errTooLong = fmt.Errorf("longer than the maximum of %v", MaxDuration)
+
+// DurationProblem is the message for a duration that couldn't be used
+func DurationProblem(err error) string {
+	if errors.Is(err, errTooLong) {
+		return fmt.Sprintf("Duration can be at most %v.", MaxDuration)
+	}
+	return "Invalid duration format."
+}
+
+// AddDurations adds without wrapping around, sums past the largest Duration
+// stay there
+func AddDurations(a, b time.Duration) time.Duration {
+	if b > 0 && a > math.MaxInt64-b {
+		return math.MaxInt64
+	}
+	return a + b
+}
+
+// CheckLimits rejects configs with values out of range, which can only come
+// from editing or importing files
+func CheckLimits(config TimerConfig) error {
+	durations := map[string]time.Duration{
+		"WarmupDuration":       config.WarmupDuration,
+		"CooldownDuration":     config.CooldownDuration,
+		"EscalateDuration":     config.EscalateDuration,
+		"MilestoneMinDuration": config.MilestoneMinDuration,
+		"ReminderDuration":     config.ReminderDuration,
+
+		"TaperWorkDuration":     config.TaperWorkDuration,
+		"TaperBreakDuration":    config.TaperBreakDuration,
+		"TaperMinWorkDuration":  config.TaperMinWorkDuration,
+		"TaperMaxBreakDuration": config.TaperMaxBreakDuration,
+
+		"FinalCycleWorkDuration":  config.FinalCycleWorkDuration,
+		"FinalCycleBreakDuration": config.FinalCycleBreakDuration,
+	}
+	for i, phase := range config.Phases {
+		if _, err := ActiveWindow(phase.ActiveHours); err != nil {
+			return fmt.Errorf("phase %d: %v", i+1, err)
+		}
+		durations[fmt.Sprintf("phase %d WorkDuration", i+1)] = phase.WorkDuration
+		if phase.BreakDuration != UntilResumed {
+			durations[fmt.Sprintf("phase %d BreakDuration", i+1)] = phase.BreakDuration
+		}
+	}
+	for field, d := range durations {
+		if d < 0 || d > MaxDuration {
+			return fmt.Errorf("%s: %v is out of range, use 0 to %v", field, d, MaxDuration)
+		}
+	}
+	if !ValidOrder(config.Order) {
+		return fmt.Errorf("Order: %q is not %s or %s", config.Order, orderPhaseMajor, OrderCycleMajor)
+	}
+	if config.MaxCycles != -1 && (config.MaxCycles < 1 || config.MaxCycles > MaxCycleCount) {
+		return fmt.Errorf("MaxCycles: %d is out of range, use -1 for unlimited or 1 to %d", config.MaxCycles, MaxCycleCount)
+	}
+	return nil
+}
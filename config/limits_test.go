@@ -0,0 +1,67 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDurationBounds(t *testing.T) {
+	for input, want := range map[string]time.Duration{
+		"168:00:00": MaxDuration,
+		"10080":     MaxDuration,
+		"10080:00":  MaxDuration,
+		"168h":      MaxDuration,
+		"-10080":    -MaxDuration,
+		"0":         0,
+	} {
+		got, err := ParseDuration(input)
+		if err != nil || got != want {
+			t.Errorf("ParseDuration(%q) = %v, %v, want %v", input, got, err, want)
+		}
+	}
+	for _, input := range []string{
+		"168:00:01",
+		"169:00:00",
+		"10081",
+		"9999999",
+		"99999999999999999999",
+		"99999999999999:00",
+		"0:99999999999999999",
+		"168h1s",
+		"2562047h",
+	} {
+		if _, err := ParseDuration(input); !errors.Is(err, errTooLong) {
+			t.Errorf("ParseDuration(%q) error = %v, want it too long", input, err)
+		}
+	}
+	if msg := DurationProblem(errTooLong); !strings.Contains(msg, "168h0m0s") {
+		t.Errorf("message for a duration too long = %q, want the maximum in it", msg)
+	}
+}
+
+func TestLoadRejectsOutOfRange(t *testing.T) {
+	for name, config := range map[string]TimerConfig{
+		"MaxCycles":             pomodoro("Tea", 25*time.Minute, 5*time.Minute, MaxCycleCount+1),
+		"phase 1 WorkDuration":  pomodoro("Tea", MaxDuration+time.Second, 5*time.Minute, 4),
+		"phase 1 BreakDuration": pomodoro("Tea", 25*time.Minute, -time.Minute, 4),
+	} {
+		path := filepath.Join(t.TempDir(), "timers.json")
+		data, err := jsonFormat{}.marshal([]TimerConfig{config})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadTimerConfigs(path); err == nil || !strings.Contains(err.Error(), name) {
+			t.Errorf("loading a config with %s out of range: error %v", name, err)
+		}
+	}
+	if err := CheckLimits(pomodoro("Tea", MaxDuration, MaxDuration, MaxCycleCount)); err != nil {
+		t.Errorf("the largest values are rejected: %v", err)
+	}
+}
@@ -0,0 +1,26 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ParseMilestones reads a list of percentages like "25,50,75"
+func ParseMilestones(input string) ([]int, error) {
+	var milestones []int
+	for _, field := range strings.Split(input, ",") {
+		field = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(field), "%"))
+		if field == "" {
+			continue
+		}
+		percent, err := strconv.Atoi(field)
+		if err != nil || percent <= 0 || percent >= 100 {
+			return nil, fmt.Errorf("invalid percentage %q, use numbers between 1 and 99", field)
+		}
+		milestones = append(milestones, percent)
+	}
+	sort.Ints(milestones)
+	return milestones, nil
+}
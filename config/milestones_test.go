@@ -0,0 +1,18 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseMilestones(t *testing.T) {
+	got, err := ParseMilestones("75%, 25,50")
+	if err != nil || !slices.Equal(got, []int{25, 50, 75}) {
+		t.Errorf("parseMilestones = %v, %v, want [25 50 75]", got, err)
+	}
+	for _, input := range []string{"0", "100", "half"} {
+		if _, err := ParseMilestones(input); err == nil {
+			t.Errorf("ParseMilestones(%q) succeeded", input)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Each task's share of the budget is split work to break like a pomodoro
+const planBreakRatio = 6
+
+// ParseWeights reads comma separated relative weights such as "2,1,1"
+func ParseWeights(input string, tasks int) ([]int, error) {
+	parts := strings.Split(input, ",")
+	if len(parts) != tasks {
+		return nil, fmt.Errorf("need %d weights, got %d", tasks, len(parts))
+	}
+	weights := make([]int, tasks)
+	for i, part := range parts {
+		w, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || w < 1 {
+			return nil, fmt.Errorf("invalid weight %q", part)
+		}
+		weights[i] = w
+	}
+	return weights, nil
+}
+
+// PlanBudget splits budget into one single cycle config per task, in
+// proportion to weights (equal shares when nil). Shares are whole seconds,
+// the seconds left over go one each to the first tasks so they add up to
+// the budget exactly.
+func PlanBudget(budget time.Duration, tasks int, weights []int) ([]TimerConfig, error) {
+	if tasks < 1 {
+		return nil, fmt.Errorf("need at least one task")
+	}
+	if weights == nil {
+		weights = make([]int, tasks)
+		for i := range weights {
+			weights[i] = 1
+		}
+	}
+	total := 0
+	for _, w := range weights {
+		total += w
+	}
+	seconds := int64(budget / time.Second)
+	if seconds < int64(total) {
+		return nil, fmt.Errorf("%v is too short to split %d ways", budget, total)
+	}
+
+	shares := make([]int64, tasks)
+	var assigned int64
+	for i, w := range weights {
+		shares[i] = seconds * int64(w) / int64(total)
+		assigned += shares[i]
+	}
+	for i := 0; assigned < seconds; i = (i + 1) % tasks {
+		shares[i]++
+		assigned++
+	}
+
+	configs := make([]TimerConfig, tasks)
+	for i, share := range shares {
+		rest := share / planBreakRatio
+		configs[i] = TimerConfig{
+			Name:      fmt.Sprintf("Task %d", i+1),
+			NotifText: fmt.Sprintf("Task %d time is up", i+1),
+			Phases: []TimerPhase{{
+				WorkDuration:  time.Duration(share-rest) * time.Second,
+				BreakDuration: time.Duration(rest) * time.Second,
+			}},
+			MaxCycles: 1,
+		}
+	}
+	return configs, nil
+}
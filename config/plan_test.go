@@ -0,0 +1,69 @@
+package config
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// planShares is the work plus break of each config of a plan and their sum
+func planShares(configs []TimerConfig) ([]time.Duration, time.Duration) {
+	var shares []time.Duration
+	var sum time.Duration
+	for _, config := range configs {
+		share := config.Phases[0].WorkDuration + config.Phases[0].BreakDuration
+		shares = append(shares, share)
+		sum += share
+	}
+	return shares, sum
+}
+
+func TestPlanBudget(t *testing.T) {
+	configs, err := PlanBudget(3*time.Hour, 4, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(configs) != 4 {
+		t.Fatalf("planned %d timers, want 4", len(configs))
+	}
+	for i, config := range configs {
+		phase := config.Phases[0]
+		if phase.WorkDuration != 37*time.Minute+30*time.Second || phase.BreakDuration != 7*time.Minute+30*time.Second || config.MaxCycles != 1 {
+			t.Errorf("task %d = %+v, want 37m30s of work and 7m30s of break once", i+1, config)
+		}
+	}
+	if _, sum := planShares(configs); sum != 3*time.Hour {
+		t.Errorf("plan sums to %v, want 3h", sum)
+	}
+
+	configs, err = PlanBudget(3*time.Hour, 3, []int{2, 1, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares, sum := planShares(configs)
+	if want := []time.Duration{90 * time.Minute, 45 * time.Minute, 45 * time.Minute}; !slices.Equal(shares, want) || sum != 3*time.Hour {
+		t.Errorf("weighted shares = %v, want %v", shares, want)
+	}
+}
+
+func TestPlanRemainder(t *testing.T) {
+	configs, err := PlanBudget(10*time.Minute+3*time.Second, 4, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares, sum := planShares(configs)
+	want := []time.Duration{151 * time.Second, 151 * time.Second, 151 * time.Second, 150 * time.Second}
+	if !slices.Equal(shares, want) || sum != 10*time.Minute+3*time.Second {
+		t.Errorf("shares = %v summing to %v, want %v", shares, sum, want)
+	}
+
+	if _, err := PlanBudget(3*time.Second, 4, nil); err == nil {
+		t.Error("split 3s four ways")
+	}
+	if _, err := PlanBudget(time.Hour, 0, nil); err == nil {
+		t.Error("planned zero tasks")
+	}
+	if _, err := ParseWeights("2,1", 3); err == nil {
+		t.Error("took 2 weights for 3 tasks")
+	}
+}
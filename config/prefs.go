@@ -0,0 +1,96 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+)
+
+const PrefsFile = "preferences.json"
+
+// Preferences are user settings that apply to every timer and profile
+type Preferences struct {
+	DisplayRounding time.Duration          `json:",omitempty"` // round the shown time to this step
+	UniqueTitles    bool                   `json:",omitempty"` // add cycle and phase to notification titles
+	RefreshInterval time.Duration          `json:",omitempty"` // redraw at most this often, 0 for every tick
+	QuietHours      []QuietHours           `json:",omitempty"` // windows without notifications
+	IncludeBreaks   bool                   `json:",omitempty"` // count breaks in the header's time left
+	CycleDots       bool                   `json:",omitempty"` // show cycles as ●●○○
+	Braille         bool                   `json:",omitempty"` // braille progress bars instead of block elements
+	ASCIIBars       bool                   `json:",omitempty"` // # and - progress bars for terminals without block elements
+	EnergyCurve     []int                  `json:",omitempty"` // focus by hour of the day, see defaultEnergyCurve
+	Words           bool                   `json:",omitempty"` // spell out times for screen readers
+	PinnedPane      bool                   `json:",omitempty"` // show the recent notifications below the timers
+	Webhooks        []string               `json:",omitempty"` // called on the events of every timer, see WebhookPayload
+	Theme           string                 `json:",omitempty"` // one of themes or Themes, off for no colors but the phases', empty for defaultTheme
+	Themes          map[string]ThemeColors `json:",omitempty"` // themes of your own by name
+	IdleQuit        time.Duration          `json:",omitempty"` // save and quit after this long without input or timers
+	Sparkline       bool                   `json:",omitempty"` // show the recent time left as ▇▆▅
+	Database        string                 `json:",omitempty"` // SQLite file the history is also written to
+	Muted           bool                   `json:",omitempty"` // play no sounds, notifications still show
+	FocusMode       bool                   `json:",omitempty"` // starting or resuming a timer pauses all the others
+	AwayAfter       time.Duration          `json:",omitempty"` // idle time after which AutoPauseOnIdle timers pause, 0 for defaultAwayAfter
+
+	// What r does: by default the timer starts over from its first phase and
+	// cycle, with this set only the current segment restarts and the cycle
+	// count is kept
+	ResetKeepsCycles bool `json:",omitempty"`
+}
+
+func SavePreferences(prefs Preferences) error {
+	data, err := json.Marshal(prefs)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(DataFile(PrefsFile), append(data, '\n'), 0644)
+}
+
+func LoadPreferences() (Preferences, error) {
+	var prefs Preferences
+	data, err := os.ReadFile(DataFile(PrefsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return prefs, nil
+		}
+		return prefs, err
+	}
+	err = json.Unmarshal(data, &prefs)
+	return prefs, err
+}
+
+// RoundForDisplay rounds the remaining time to the nearest step. Inside the
+// last step the exact time is kept so it never reads 00:00 with time left.
+func RoundForDisplay(d, step time.Duration) time.Duration {
+	if step <= 0 || d < step {
+		return d
+	}
+	return d.Round(step)
+}
+
+// Timers with more cycles than this keep the numbers, the dots wouldn't fit
+const maxDots = 12
+
+// CycleDots shows the cycles done so far, the current one included, as filled
+// dots and the rest as empty ones. Unlimited timers trail off with "…".
+func CycleDots(cycles, maxCycles int) (string, bool) {
+	if maxCycles == -1 {
+		return strings.Repeat("●", min(cycles, maxDots)) + "…", true
+	}
+	if maxCycles > maxDots {
+		return "", false
+	}
+	filled := min(cycles, maxCycles)
+	return strings.Repeat("●", filled) + strings.Repeat("○", maxCycles-filled), true
+}
+
+// ThemeColors is a theme of the preferences, with colors by name or as
+// 256-color numbers like "208"
+type ThemeColors struct {
+	Work     string `json:",omitempty"`
+	Break    string `json:",omitempty"`
+	Paused   string `json:",omitempty"`
+	Ending   string `json:",omitempty"`
+	Blink    bool   `json:",omitempty"`
+	Selected string `json:",omitempty"`
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Presets are the templates of TemplatesFile plus a few built in ones, which
+// saved templates of the same name take the place of
+var builtinPresets = []TimerConfig{
+	{
+		Name:      "pomodoro",
+		NotifText: "Pomodoro",
+		Phases:    []TimerPhase{{WorkDuration: 25 * time.Minute, BreakDuration: 5 * time.Minute}},
+		MaxCycles: 4,
+	},
+	{
+		Name:      "52-17",
+		NotifText: "52/17",
+		Phases:    []TimerPhase{{WorkDuration: 52 * time.Minute, BreakDuration: 17 * time.Minute}},
+		MaxCycles: -1,
+	},
+	{
+		Name:      "deep-work",
+		NotifText: "Deep work",
+		Phases:    []TimerPhase{{WorkDuration: 90 * time.Minute, BreakDuration: 20 * time.Minute}},
+		MaxCycles: -1,
+	},
+}
+
+// Presets lists the saved templates followed by the built in presets they
+// don't replace
+func Presets() ([]TimerConfig, error) {
+	templates, err := LoadTemplates(DataFile(TemplatesFile))
+	if err != nil {
+		return nil, err
+	}
+	for _, preset := range builtinPresets {
+		if !slices.ContainsFunc(templates, func(t TimerConfig) bool { return strings.EqualFold(t.Name, preset.Name) }) {
+			templates = append(templates, preset)
+		}
+	}
+	return templates, nil
+}
+
+// FindPreset looks a preset up by name, ignoring case
+func FindPreset(name string) (TimerConfig, error) {
+	all, err := Presets()
+	if err != nil {
+		return TimerConfig{}, err
+	}
+	for _, preset := range all {
+		if strings.EqualFold(preset.Name, name) {
+			return preset, nil
+		}
+	}
+	return TimerConfig{}, fmt.Errorf("no preset %s", name)
+}
+
+// SavePreset stores the config as a template, replacing one of the same name
+func SavePreset(config TimerConfig) error {
+	templates, err := LoadTemplates(DataFile(TemplatesFile))
+	if err != nil {
+		return err
+	}
+	templates = slices.DeleteFunc(templates, func(t TimerConfig) bool { return strings.EqualFold(t.Name, config.Name) })
+	return SaveTemplates(DataFile(TemplatesFile), append(templates, config))
+}
+
+// TemplatesFile is the name of the saved presets file in the data directory
+const TemplatesFile = "templates.json"
+
+// LoadTemplates reads the presets saved at path
+func LoadTemplates(path string) ([]TimerConfig, error) {
+	return LoadTimerConfigs(path)
+}
+
+// SaveTemplates writes the presets to path
+func SaveTemplates(path string, templates []TimerConfig) error {
+	return SaveTimerConfigs(path, templates)
+}
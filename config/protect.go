@@ -0,0 +1,35 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ParseCycles reads cycle numbers and ranges like "1-2,5"
+func ParseCycles(input string) ([]int, error) {
+	var cycles []int
+	for _, field := range strings.Split(input, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		from, to, isRange := strings.Cut(field, "-")
+		if !isRange {
+			to = from
+		}
+		first, err1 := strconv.Atoi(strings.TrimSpace(from))
+		last, err2 := strconv.Atoi(strings.TrimSpace(to))
+		if err1 != nil || err2 != nil || first < 1 || last < first || last > MaxCycleCount {
+			return nil, fmt.Errorf("invalid cycles %q, use numbers like 2 or ranges like 1-3", field)
+		}
+		for c := first; c <= last; c++ {
+			if !slices.Contains(cycles, c) {
+				cycles = append(cycles, c)
+			}
+		}
+	}
+	slices.Sort(cycles)
+	return cycles, nil
+}
@@ -0,0 +1,18 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseCycles(t *testing.T) {
+	got, err := ParseCycles("5, 1-2,2")
+	if err != nil || !slices.Equal(got, []int{1, 2, 5}) {
+		t.Errorf("parseCycles = %v, %v, want [1 2 5]", got, err)
+	}
+	for _, input := range []string{"0", "3-1", "two", "1-99999"} {
+		if _, err := ParseCycles(input); err == nil {
+			t.Errorf("ParseCycles(%q) succeeded", input)
+		}
+	}
+}
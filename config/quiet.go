@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// QuietHours is a daily window without notifications. A window that ends
+// before it starts runs past midnight, Days then name the day it starts on.
+type QuietHours struct {
+	Start string   // HH:MM
+	End   string   // HH:MM
+	Days  []string `json:",omitempty"` // empty for every day
+}
+
+// ParseQuietHours reads "12:00-13:00" and an optional list of days like "mon,tue"
+func ParseQuietHours(window, days string) (QuietHours, error) {
+	start, end, ok := strings.Cut(window, "-")
+	if !ok {
+		return QuietHours{}, fmt.Errorf("invalid window %q, use HH:MM-HH:MM", window)
+	}
+	for _, at := range []string{start, end} {
+		if _, err := time.Parse("15:04", at); err != nil {
+			return QuietHours{}, fmt.Errorf("invalid time %q, use HH:MM", at)
+		}
+	}
+	if start == end {
+		return QuietHours{}, fmt.Errorf("quiet hours need to end after they start")
+	}
+	parsed, err := ParseWeekdays(days)
+	if err != nil {
+		return QuietHours{}, err
+	}
+	return QuietHours{Start: start, End: end, Days: parsed}, nil
+}
+
+// String is the window and its days the way the quiet command lists them
+func (q QuietHours) String() string {
+	days := "every day"
+	if len(q.Days) > 0 {
+		days = strings.Join(q.Days, ",")
+	}
+	return fmt.Sprintf("%s-%s (%s)", q.Start, q.End, days)
+}
+
+// Contains reports whether now falls inside the window
+func (q QuietHours) Contains(now time.Time) bool {
+	start, err := time.Parse("15:04", q.Start)
+	if err != nil {
+		return false
+	}
+	end, err := time.Parse("15:04", q.End)
+	if err != nil {
+		return false
+	}
+	minute := now.Hour()*60 + now.Minute()
+	from := start.Hour()*60 + start.Minute()
+	to := end.Hour()*60 + end.Minute()
+
+	if from < to {
+		return minute >= from && minute < to && includesDay(q.Days, now.Weekday())
+	}
+	// Past midnight, the early part belongs to the window of the day before
+	if minute >= from {
+		return includesDay(q.Days, now.Weekday())
+	}
+	return minute < to && includesDay(q.Days, now.AddDate(0, 0, -1).Weekday())
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuietHoursWindow(t *testing.T) {
+	lunch, err := ParseQuietHours("12:00-13:00", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	night, err := ParseQuietHours("22:00-07:00", "fri")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(day int, clock string) time.Time {
+		// March 2024 starts on a Friday
+		parsed, _ := time.Parse("15:04", clock)
+		return time.Date(2024, 3, day, parsed.Hour(), parsed.Minute(), 0, 0, time.UTC)
+	}
+	for _, tc := range []struct {
+		window QuietHours
+		now    time.Time
+		want   bool
+	}{
+		{lunch, at(4, "11:59"), false},
+		{lunch, at(4, "12:00"), true},
+		{lunch, at(4, "12:59"), true},
+		{lunch, at(4, "13:00"), false},
+		{night, at(1, "21:59"), false},
+		{night, at(1, "23:00"), true},
+		{night, at(2, "06:59"), true}, // Saturday morning is still Friday night
+		{night, at(2, "07:00"), false},
+		{night, at(2, "23:00"), false},
+		{night, at(1, "06:00"), false}, // Thursday night isn't quiet
+	} {
+		if got := tc.window.Contains(tc.now); got != tc.want {
+			t.Errorf("%v contains %s = %t, want %t", tc.window, tc.now.Format("Mon 15:04"), got, tc.want)
+		}
+	}
+}
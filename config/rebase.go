@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseScaled reads a multiple of the base duration like "x1.5"
+func parseScaled(input string) (float64, bool, error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+	if !strings.HasPrefix(input, "x") {
+		return 0, false, nil
+	}
+	scale, err := strconv.ParseFloat(input[1:], 64)
+	if err != nil || scale <= 0 {
+		return 0, true, fmt.Errorf("invalid multiple %q, use something like x1.5", input)
+	}
+	return scale, true, nil
+}
+
+// scaled is scale times base, no longer than MaxDuration
+func scaled(base time.Duration, scale float64) time.Duration {
+	d := float64(base) * scale
+	if d > float64(MaxDuration) {
+		return MaxDuration
+	}
+	return time.Duration(d)
+}
+
+// ResolvePhases returns a copy of phases with the base-relative durations
+// worked out from base, absolute ones are kept
+func ResolvePhases(phases []TimerPhase, base time.Duration) []TimerPhase {
+	resolved := append([]TimerPhase(nil), phases...)
+	if base <= 0 {
+		return resolved
+	}
+	for i := range resolved {
+		if resolved[i].WorkScale > 0 {
+			resolved[i].WorkDuration = scaled(base, resolved[i].WorkScale)
+		}
+		if resolved[i].BreakScale > 0 {
+			resolved[i].BreakDuration = scaled(base, resolved[i].BreakScale)
+		}
+	}
+	return resolved
+}
+
+// PhaseDuration reads a phase duration, either absolute or like "x2" for a
+// multiple of base
+func PhaseDuration(input string, base time.Duration) (time.Duration, float64, error) {
+	scale, relative, err := parseScaled(input)
+	if !relative {
+		d, err := ParseDuration(input)
+		return d, 0, err
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	if base <= 0 {
+		return 0, 0, fmt.Errorf("multiples need a base duration")
+	}
+	return scaled(base, scale), scale, nil
+}
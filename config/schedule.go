@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ScheduledStart returns when the config starts on the day of now
+func ScheduledStart(config TimerConfig, now time.Time) (time.Time, bool) {
+	if config.StartAt == "" {
+		return time.Time{}, false
+	}
+	at, err := time.Parse("15:04", config.StartAt)
+	if err != nil {
+		return time.Time{}, false
+	}
+	year, month, day := now.Date()
+	return time.Date(year, month, day, at.Hour(), at.Minute(), 0, 0, now.Location()), true
+}
+
+// RunsOn reports whether a scheduled config starts on the given weekday
+func (c TimerConfig) RunsOn(day time.Weekday) bool {
+	return includesDay(c.Days, day)
+}
+
+// includesDay reports whether day is one of the named days, no days means all
+func includesDay(days []string, day time.Weekday) bool {
+	if len(days) == 0 {
+		return true
+	}
+	for _, name := range days {
+		if d, ok := parseWeekday(name); ok && d == day {
+			return true
+		}
+	}
+	return false
+}
+
+func parseWeekday(name string) (time.Weekday, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if len(name) < 3 {
+		return 0, false
+	}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.HasPrefix(strings.ToLower(d.String()), name) {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// ParseWeekdays turns "mon,tue,fri" into short day names, empty means every day
+func ParseWeekdays(input string) ([]string, error) {
+	var days []string
+	for _, name := range strings.Split(input, ",") {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		d, ok := parseWeekday(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown weekday %q", strings.TrimSpace(name))
+		}
+		days = append(days, d.String()[:3])
+	}
+	return days, nil
+}
+
+// ScheduleString is when the config starts, the way the schedule command
+// lists it
+func ScheduleString(config TimerConfig) string {
+	days := "every day"
+	if len(config.Days) > 0 {
+		days = strings.Join(config.Days, ",")
+	}
+	return fmt.Sprintf("%s at %s (%s)", config.Name, config.StartAt, days)
+}
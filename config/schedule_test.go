@@ -0,0 +1,16 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseWeekdays(t *testing.T) {
+	days, err := ParseWeekdays("mon, Tuesday,fri")
+	if want := []string{"Mon", "Tue", "Fri"}; err != nil || !slices.Equal(days, want) {
+		t.Errorf("parseWeekdays = %v, %v, want %v", days, err, want)
+	}
+	if _, err := ParseWeekdays("mon,funday"); err == nil {
+		t.Error("parseWeekdays accepted funday")
+	}
+}
@@ -0,0 +1,245 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The config files are checked against the fields of TimerConfig before they
+// are decoded, so a misspelled field or a value of the wrong kind is reported
+// with where it is, "timer 2 (Tea): phase 1 WorkDuration: ...", instead of
+// being dropped or failing with a bare decoding error. Durations are
+// written like 25m, 1h30m, 25:00 or 1:30:00 and become nanoseconds here. A
+// bare number is only a duration in JSON, as nanoseconds, which is how JSON
+// files were saved before durations were written out.
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// checkConfigs checks a decoded list of timers and converts its durations,
+// nanos accepts numbers as durations in nanoseconds
+func checkConfigs(doc any, nanos bool) (any, error) {
+	list, ok := asList(doc)
+	if !ok {
+		return nil, fmt.Errorf("want a list of timers, got %s", describe(doc))
+	}
+	configType := reflect.TypeOf(TimerConfig{})
+	for i, item := range list {
+		name := ""
+		if fields, ok := item.(map[string]any); ok {
+			name, _ = fields["Name"].(string)
+		}
+		checked, err := checkValue(item, configType, "", nanos)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", timerLabel(i, name), err)
+		}
+		list[i] = checked
+	}
+	return list, nil
+}
+
+// timerLabel names the i-th timer of a file in errors
+func timerLabel(i int, name string) string {
+	if name == "" {
+		return fmt.Sprintf("timer %d", i+1)
+	}
+	return fmt.Sprintf("timer %d (%s)", i+1, name)
+}
+
+// checkValue checks that value can be decoded into a t, path is where it is
+// for errors
+func checkValue(value any, t reflect.Type, path string, nanos bool) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	fail := func(want string) (any, error) {
+		return nil, fmt.Errorf("%s: want %s, got %s", path, want, describe(value))
+	}
+
+	switch {
+	case t == durationType:
+		if s, ok := value.(string); ok {
+			d, err := parseConfigDuration(s)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", path, err)
+			}
+			return int64(d), nil
+		}
+		if _, ok := integer(value); !ok || !nanos {
+			return fail("a duration like 25m or 1:30:00")
+		}
+		return value, nil
+
+	case t == timeType:
+		switch value := value.(type) {
+		case time.Time:
+			return value.Format(time.RFC3339Nano), nil
+		case string:
+			if _, err := time.Parse(time.RFC3339Nano, value); err != nil {
+				return fail("a time like 2024-05-01T09:30:00Z")
+			}
+			return value, nil
+		}
+		return fail("a time like 2024-05-01T09:30:00Z")
+
+	case t.Kind() == reflect.Struct:
+		fields, ok := value.(map[string]any)
+		if !ok {
+			return fail("a table of fields")
+		}
+		// In order so the same mistake is always the one reported
+		keys := make([]string, 0, len(fields))
+		for key := range fields {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			field := fields[key]
+			// Field names match regardless of case, like encoding/json
+			sf, ok := t.FieldByNameFunc(func(name string) bool { return strings.EqualFold(name, key) })
+			if !ok || !sf.IsExported() {
+				return nil, fmt.Errorf("%s: unknown field", join(path, key))
+			}
+			checked, err := checkValue(field, sf.Type, join(path, sf.Name), nanos)
+			if err != nil {
+				return nil, err
+			}
+			fields[key] = checked
+		}
+		return fields, nil
+
+	case t.Kind() == reflect.Slice:
+		list, ok := asList(value)
+		if !ok {
+			return fail("a list")
+		}
+		for i := range list {
+			where := fmt.Sprintf("%s item %d", path, i+1)
+			if t.Elem() == reflect.TypeOf(TimerPhase{}) {
+				where = fmt.Sprintf("phase %d", i+1)
+			}
+			checked, err := checkValue(list[i], t.Elem(), where, nanos)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = checked
+		}
+		return list, nil
+
+	case t.Kind() == reflect.String:
+		if _, ok := value.(string); !ok {
+			return fail("text")
+		}
+	case t.Kind() == reflect.Bool:
+		if _, ok := value.(bool); !ok {
+			return fail("true or false")
+		}
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Int64:
+		if _, ok := integer(value); !ok {
+			return fail("a whole number")
+		}
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		if _, ok := number(value); !ok {
+			return fail("a number")
+		}
+	}
+	return value, nil
+}
+
+// join appends a field to a path, "phase 1" and "WorkDuration" make
+// "phase 1 WorkDuration" like the errors of CheckLimits
+func join(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + " " + field
+}
+
+// asList accepts the lists of every decoder, TOML returns arrays of tables
+// as []map[string]any
+func asList(value any) ([]any, bool) {
+	switch value := value.(type) {
+	case []any:
+		return value, true
+	case []map[string]any:
+		list := make([]any, len(value))
+		for i := range value {
+			list[i] = value[i]
+		}
+		return list, true
+	}
+	return nil, false
+}
+
+func integer(value any) (int64, bool) {
+	switch value := value.(type) {
+	case int:
+		return int64(value), true
+	case int64:
+		return value, true
+	case uint64:
+		return int64(value), value <= math.MaxInt64
+	case json.Number:
+		n, err := value.Int64()
+		return n, err == nil
+	case float64:
+		return int64(value), value == math.Trunc(value) && math.Abs(value) < 1<<63
+	}
+	return 0, false
+}
+
+func number(value any) (float64, bool) {
+	switch value := value.(type) {
+	case json.Number:
+		f, err := value.Float64()
+		return f, err == nil
+	case float64:
+		return value, true
+	}
+	if n, ok := integer(value); ok {
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// describe names the kind of a decoded value for errors
+func describe(value any) string {
+	switch value := value.(type) {
+	case string:
+		return strconv.Quote(value)
+	case bool:
+		return strconv.FormatBool(value)
+	case map[string]any, []map[string]any:
+		return "a table"
+	case []any:
+		return "a list"
+	}
+	if n, ok := number(value); ok {
+		return strconv.FormatFloat(n, 'g', -1, 64)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// parseConfigDuration reads a duration of a config file, Go style like 25m or
+// 1h30m, or on the clock like 25:00 or 1:30:00. Unlike typed input a number
+// alone isn't minutes.
+func parseConfigDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+	if strings.Contains(s, ":") {
+		if d, err := ParseDuration(s); err == nil && d >= 0 {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid duration %q, use e.g. 25m, 1h30m, 25:00 or 1:30:00", s)
+}
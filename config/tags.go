@@ -0,0 +1,17 @@
+package config
+
+import (
+	"slices"
+	"strings"
+)
+
+// ParseTags reads comma separated tags, spaces around them are dropped
+func ParseTags(input string) []string {
+	var tags []string
+	for _, tag := range strings.Split(input, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
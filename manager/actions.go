@@ -0,0 +1,99 @@
+package manager
+
+import (
+	"fmt"
+
+	"multi-timer/timer"
+)
+
+// notificationAction is a button on a notification, key is what the
+// notifier answers with when it's clicked
+type notificationAction struct {
+	key   string
+	label string
+}
+
+// actionNotifier is a Notifier that can put buttons on a notification. answer
+// is called with the key of the button clicked, from another goroutine.
+type actionNotifier interface {
+	NotifyActions(title, message string, level Urgency, actions []notificationAction, answer func(key string)) error
+}
+
+// The buttons of the notification that work is done and the break started
+var breakActions = []notificationAction{
+	{"start-break", "Start Break"},
+	{"skip-break", "Skip Break"},
+	{"snooze", fmt.Sprintf("Snooze %v", timer.HoursMinutes(timer.DefaultSnooze))},
+}
+
+// sendBreakNotification announces the break with buttons to act on it, where
+// the notifier has them. Must be called with tm.mu held.
+func (tm *TimerManager) sendBreakNotification(t *timer.Timer, title, message string) {
+	notifier, ok := tm.Notifier.(actionNotifier)
+	if !ok {
+		tm.SendNotification(title, message, NormalUrgency)
+		return
+	}
+	if tm.quiet(tm.Now()) {
+		return
+	}
+	phase, cycle := t.State.CurrentPhase, t.State.Cycles
+	answer := func(key string) {
+		tm.breakAction(t, phase, cycle, key)
+	}
+	if err := notifier.NotifyActions(title, message, NormalUrgency, breakActions, answer); err != nil {
+		tm.ReportError("Error sending notification", err)
+	}
+}
+
+// breakAction carries out a button clicked on the break notification of the
+// phase and cycle, unless the timer has moved on since
+func (tm *TimerManager) breakAction(t *timer.Timer, phase, cycle int, key string) {
+	tm.Lock()
+	num := -1
+	for i, timer := range tm.ActiveTimers {
+		if timer == t {
+			num = i + 1
+		}
+	}
+	if num < 0 || t.State.IsWork || t.State.Stage != timer.StageCycles || t.State.CurrentPhase != phase || t.State.Cycles != cycle {
+		tm.Unlock()
+		return
+	}
+	tm.NoteInput()
+
+	switch key {
+	case "start-break":
+		// The break is already counting, make sure it isn't held up
+		t.Acknowledge()
+		if t.IsPaused {
+			t.IsPaused = false
+			tm.PauseGroup(t)
+		}
+	case "skip-break":
+		t.Acknowledge()
+		if !t.Protected() {
+			tm.EndBreakEarly(num)
+		}
+	case "snooze":
+		if !t.Protected() {
+			t.Snooze(timer.DefaultSnooze)
+		}
+	}
+	tm.Unlock()
+	tm.redraw()
+}
+
+// EndBreakEarly ends the break of timer num and starts its next cycle,
+// finishing the timer if there is none. Must be called with tm.mu held.
+func (tm *TimerManager) EndBreakEarly(num int) {
+	timer := tm.ActiveTimers[num-1]
+	tm.cyclesDone++
+	tm.PauseGroup(timer)
+	completed := timer.EndBreak()
+	tm.handleEvents(timer)
+	if completed {
+		tm.ActiveTimers = append(tm.ActiveTimers[:num-1], tm.ActiveTimers[num:]...)
+		tm.finish(timer)
+	}
+}
@@ -0,0 +1,40 @@
+package manager
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"multi-timer/config"
+	"multi-timer/timer"
+)
+
+// ArchiveFile is the name of the archive in the data directory
+const ArchiveFile = "archive.jsonl"
+
+// ArchiveEntry is one line of the archive, written when a timer completes
+type ArchiveEntry struct {
+	Completed       time.Time
+	Config          config.TimerConfig
+	CompletedCycles int
+	WorkTime        time.Duration
+}
+
+func appendArchive(path string, entry ArchiveEntry) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return json.NewEncoder(file).Encode(entry)
+}
+
+// archive records a completed timer. Must be called with tm.mu held.
+func (tm *TimerManager) archive(t *timer.Timer) error {
+	return appendArchive(tm.ArchivePath, ArchiveEntry{
+		Completed:       tm.Now(),
+		Config:          t.Config(),
+		CompletedCycles: t.Stats.CompletedCycles,
+		WorkTime:        t.Stats.WorkTime,
+	})
+}
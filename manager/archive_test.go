@@ -0,0 +1,47 @@
+package manager
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestArchiveCompletedTimer(t *testing.T) {
+	tm, _ := newTestManager(t)
+	config := pomodoro("Tea", 2*time.Minute, time.Minute, 2)
+	config.NotifText = "Tea time"
+	config.Tags = []string{"break"}
+	addTimers(tm, config, pomodoro("Read", 30*time.Minute, 5*time.Minute, 4))
+	advance(tm, 6*time.Minute)
+
+	file, err := os.Open(tm.ArchivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	var entries []ArchiveEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry ArchiveEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("archive has %d entries, want Tea's alone", len(entries))
+	}
+	entry := entries[0]
+	if want := time.Date(2024, 3, 4, 9, 6, 0, 0, time.UTC); !entry.Completed.Equal(want) {
+		t.Errorf("completed at %v, want %v", entry.Completed, want)
+	}
+	if entry.Config.Name != "Tea" || entry.Config.NotifText != "Tea time" || entry.Config.MaxCycles != 2 ||
+		len(entry.Config.Tags) != 1 || entry.Config.Phases[0].WorkDuration != 2*time.Minute {
+		t.Errorf("archived config = %+v, want Tea's", entry.Config)
+	}
+	if entry.CompletedCycles != 2 || entry.WorkTime != 4*time.Minute {
+		t.Errorf("archived %d cycles and %v of work, want 2 and 4m", entry.CompletedCycles, entry.WorkTime)
+	}
+}
@@ -0,0 +1,109 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"multi-timer/timer"
+)
+
+// Timers with AutoPauseOnIdle pause their work while the screen is locked or
+// nothing was typed or clicked for prefs.AwayAfter, and carry on once the
+// user is back. How that is found out depends on the platform, see userAway.
+const (
+	defaultAwayAfter = 5 * time.Minute
+	AwayPoll         = 5 * time.Second
+)
+
+// errNoIdleDetection is returned by userAway where it can't tell
+var errNoIdleDetection = errors.New("idle detection isn't supported here")
+
+// WatchAway checks every interval whether the user is away, pausing and
+// resuming the timers that asked for it
+func (tm *TimerManager) WatchAway(interval time.Duration) {
+	go func() {
+		reported := false
+		for range time.Tick(interval) {
+			tm.Lock()
+			wanted := false
+			for _, timer := range tm.ActiveTimers {
+				wanted = wanted || timer.AutoPause
+			}
+			after := tm.Prefs.AwayAfter
+			tm.Unlock()
+			if !wanted {
+				continue
+			}
+			if after <= 0 {
+				after = defaultAwayAfter
+			}
+
+			idle, locked, err := userAway()
+			if err != nil {
+				if !reported {
+					tm.mu.Lock()
+					tm.ReportError("Error checking for idle", err)
+					tm.mu.Unlock()
+					tm.redraw()
+					reported = true
+				}
+				continue
+			}
+			tm.Lock()
+			changed := tm.setAway(locked || idle >= after)
+			tm.Unlock()
+			if changed {
+				tm.redraw()
+			}
+		}
+	}()
+}
+
+// setAway pauses the work of the AutoPauseOnIdle timers while away and resumes
+// the ones it paused on return, reporting whether any timer changed. Must be
+// called with tm.mu held.
+func (tm *TimerManager) setAway(away bool) bool {
+	changed := false
+	for _, t := range tm.ActiveTimers {
+		switch {
+		case away && t.AutoPause && !t.IsPaused && t.State.IsWork && t.State.Stage == timer.StageCycles && t.Pauseable():
+			t.IsPaused = true
+			t.PausedByIdle = true
+			changed = true
+		case !away && t.PausedByIdle:
+			t.PausedByIdle = false
+			if t.IsPaused {
+				t.IsPaused = false
+				tm.PauseGroup(t)
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// SetAutoPause turns AutoPauseOnIdle of timer num on or off and saves it with
+// the timer's config
+func (tm *TimerManager) SetAutoPause(num int, on bool) error {
+	tm.Lock()
+	if num < 1 || num > len(tm.ActiveTimers) {
+		tm.Unlock()
+		return fmt.Errorf("no timer %d", num)
+	}
+	timer := tm.ActiveTimers[num-1]
+	timer.AutoPause = on
+	if !on && timer.PausedByIdle {
+		timer.PausedByIdle = false
+		timer.IsPaused = false
+		tm.PauseGroup(timer)
+	}
+	j := tm.PairConfigs()[num-1]
+	if j < 0 {
+		tm.Unlock()
+		return nil
+	}
+	tm.Configs[j].AutoPauseOnIdle = on
+	tm.Unlock()
+	return tm.SaveConfigs()
+}
@@ -0,0 +1,31 @@
+//go:build darwin
+
+package manager
+
+import (
+	"os/exec"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var hidIdleTime = regexp.MustCompile(`"HIDIdleTime" = (\d+)`)
+
+// userAway reads the time since the last input from IOKit's HID system, by
+// way of ioreg so no cgo is needed. A locked screen stops input, so it shows
+// up as idle time.
+func userAway() (idle time.Duration, locked bool, err error) {
+	out, err := exec.Command("ioreg", "-c", "IOHIDSystem", "-d", "4").Output()
+	if err != nil {
+		return 0, false, err
+	}
+	match := hidIdleTime.FindSubmatch(out)
+	if match == nil {
+		return 0, false, errNoIdleDetection
+	}
+	ns, err := strconv.ParseInt(string(match[1]), 10, 64)
+	if err != nil {
+		return 0, false, err
+	}
+	return time.Duration(ns), false, nil
+}
@@ -0,0 +1,57 @@
+//go:build linux && !nodbus
+
+package manager
+
+import (
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+var (
+	sessionBusOnce sync.Once
+	sessionBus     *dbus.Conn
+	sessionBusErr  error
+)
+
+// userAway asks the screen saver on the session bus whether the screen is
+// locked, and GNOME's idle monitor or the freedesktop screen saver (KDE) how
+// long there was no input
+func userAway() (idle time.Duration, locked bool, err error) {
+	sessionBusOnce.Do(func() {
+		sessionBus, sessionBusErr = dbus.ConnectSessionBus()
+	})
+	if sessionBusErr != nil {
+		return 0, false, sessionBusErr
+	}
+
+	found := false
+	for _, saver := range []struct{ name, path string }{
+		{"org.freedesktop.ScreenSaver", "/org/freedesktop/ScreenSaver"},
+		{"org.gnome.ScreenSaver", "/org/gnome/ScreenSaver"},
+	} {
+		var active bool
+		if sessionBus.Object(saver.name, dbus.ObjectPath(saver.path)).Call(saver.name+".GetActive", 0).Store(&active) == nil {
+			found = true
+			locked = locked || active
+		}
+	}
+
+	var ms uint64
+	var seconds uint32
+	monitor := sessionBus.Object("org.gnome.Mutter.IdleMonitor", "/org/gnome/Mutter/IdleMonitor/Core")
+	if monitor.Call("org.gnome.Mutter.IdleMonitor.GetIdletime", 0).Store(&ms) == nil {
+		found = true
+		idle = time.Duration(ms) * time.Millisecond
+	} else if sessionBus.Object("org.freedesktop.ScreenSaver", "/ScreenSaver").
+		Call("org.freedesktop.ScreenSaver.GetSessionIdleTime", 0).Store(&seconds) == nil {
+		found = true
+		idle = time.Duration(seconds) * time.Second
+	}
+
+	if !found {
+		return 0, false, errNoIdleDetection
+	}
+	return idle, locked, nil
+}
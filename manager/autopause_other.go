@@ -0,0 +1,9 @@
+//go:build !(linux && !nodbus) && !darwin && !windows
+
+package manager
+
+import "time"
+
+func userAway() (idle time.Duration, locked bool, err error) {
+	return 0, false, errNoIdleDetection
+}
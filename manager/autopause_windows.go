@@ -0,0 +1,42 @@
+//go:build windows
+
+package manager
+
+import (
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+var (
+	user32           = syscall.NewLazyDLL("user32.dll")
+	kernel32         = syscall.NewLazyDLL("kernel32.dll")
+	getLastInputInfo = user32.NewProc("GetLastInputInfo")
+	getTickCount     = kernel32.NewProc("GetTickCount")
+	openInputDesktop = user32.NewProc("OpenInputDesktop")
+	closeDesktop     = user32.NewProc("CloseDesktop")
+)
+
+type lastInputInfo struct {
+	size uint32
+	time uint32
+}
+
+// userAway asks Win32 for the tick count of the last input. While the
+// workstation is locked the input desktop can't be opened.
+func userAway() (idle time.Duration, locked bool, err error) {
+	info := lastInputInfo{size: uint32(unsafe.Sizeof(lastInputInfo{}))}
+	if ok, _, err := getLastInputInfo.Call(uintptr(unsafe.Pointer(&info))); ok == 0 {
+		return 0, false, err
+	}
+	now, _, _ := getTickCount.Call()
+	idle = time.Duration(uint32(now)-info.time) * time.Millisecond
+
+	const desktopSwitchDesktop = 0x0100
+	desktop, _, _ := openInputDesktop.Call(0, 0, desktopSwitchDesktop)
+	if desktop == 0 {
+		return idle, true, nil
+	}
+	closeDesktop.Call(desktop)
+	return idle, false, nil
+}
@@ -0,0 +1,111 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"multi-timer/config"
+	"multi-timer/timer"
+)
+
+// Bumped whenever the bundle layout changes incompatibly
+const bundleVersion = 1
+
+// Bundle is a whole setup in one file: the timers, the preferences and the
+// templates timers can be created from
+type Bundle struct {
+	Version     int
+	Configs     []config.TimerConfig
+	Preferences *config.Preferences  `json:",omitempty"`
+	Templates   []config.TimerConfig `json:",omitempty"`
+}
+
+// ExportBundle writes the current setup. Must be called with tm.mu held.
+func (tm *TimerManager) ExportBundle(path string) error {
+	templates, err := config.LoadTemplates(config.DataFile(config.TemplatesFile))
+	if err != nil {
+		return err
+	}
+	prefs := tm.Prefs
+	data, err := json.MarshalIndent(Bundle{
+		Version:     bundleVersion,
+		Configs:     tm.Configs,
+		Preferences: &prefs,
+		Templates:   templates,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// ReadBundle reads a bundle exported to path
+func ReadBundle(path string) (Bundle, error) {
+	var bundle Bundle
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return bundle, err
+	}
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return bundle, err
+	}
+	if bundle.Version < 1 || bundle.Version > bundleVersion {
+		return bundle, fmt.Errorf("bundle version %d is not supported, this version reads up to %d", bundle.Version, bundleVersion)
+	}
+	for _, configs := range [][]config.TimerConfig{bundle.Configs, bundle.Templates} {
+		for i, cfg := range configs {
+			if len(cfg.Phases) == 0 {
+				return bundle, fmt.Errorf("%s: has no phases", cfg.Name)
+			}
+			configs[i].Phases = config.ResolvePhases(cfg.Phases, cfg.BaseDuration)
+			if err := config.CheckLimits(configs[i]); err != nil {
+				return bundle, fmt.Errorf("%s: %v", cfg.Name, err)
+			}
+		}
+	}
+	return bundle, nil
+}
+
+// ImportBundle adds the timers of a bundle, starting them like new ones, and
+// the templates whose names aren't taken yet. The preferences replace ours
+// only with withPrefs. Must be called with tm.mu held.
+func (tm *TimerManager) ImportBundle(bundle Bundle, withPrefs bool) error {
+	for _, config := range bundle.Configs {
+		tm.Configs = append(tm.Configs, config)
+		if config.StartAt != "" {
+			continue
+		}
+		t := timer.TimerFromConfig(config)
+		tm.ActiveTimers = append(tm.ActiveTimers, t)
+		if tm.GroupRunning(t) {
+			t.IsPaused = true
+		}
+	}
+
+	if withPrefs && bundle.Preferences != nil {
+		tm.Prefs = *bundle.Preferences
+		if err := config.SavePreferences(tm.Prefs); err != nil {
+			return err
+		}
+	}
+
+	if len(bundle.Templates) == 0 {
+		return nil
+	}
+	templates, err := config.LoadTemplates(config.DataFile(config.TemplatesFile))
+	if err != nil {
+		return err
+	}
+	taken := make(map[string]bool)
+	for _, template := range templates {
+		taken[template.Name] = true
+	}
+	for _, template := range bundle.Templates {
+		if !taken[template.Name] {
+			templates = append(templates, template)
+			taken[template.Name] = true
+		}
+	}
+	return config.SaveTemplates(config.DataFile(config.TemplatesFile), templates)
+}
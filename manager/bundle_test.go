@@ -0,0 +1,113 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"multi-timer/config"
+)
+
+func sampleConfigs() []config.TimerConfig {
+	return []config.TimerConfig{
+		{
+			Name:      "Tea",
+			NotifText: "Tea time",
+			Phases: []config.TimerPhase{
+				{WorkDuration: 25 * time.Minute, BreakDuration: 5 * time.Minute, Outcome: "draft", PhaseColor: "green"},
+				{WorkDuration: 50 * time.Minute, BreakDuration: config.UntilResumed, WorkScale: 2},
+			},
+			MaxCycles:         4,
+			BaseDuration:      25 * time.Minute,
+			WarmupDuration:    90 * time.Second,
+			Tags:              []string{"study", "deep"},
+			Milestones:        []int{25, 50},
+			AutoPauseOnIdle:   true,
+			RecentCompletions: []time.Time{time.Date(2024, 3, 4, 9, 30, 0, 0, time.UTC)},
+		},
+		{
+			Name:      "Stretch",
+			NotifText: "Stretch",
+			Phases:    []config.TimerPhase{{WorkDuration: time.Hour + 30*time.Minute, BreakDuration: 10 * time.Minute}},
+			MaxCycles: -1,
+		},
+	}
+}
+
+func TestBundleRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "setup.json")
+	configs := sampleConfigs()
+	prefs := config.Preferences{DisplayRounding: 5 * time.Second, CycleDots: true, QuietHours: []config.QuietHours{{Start: "12:00", End: "13:00"}}}
+	templates := []config.TimerConfig{
+		pomodoro("Deep work", 90*time.Minute, 20*time.Minute, 2),
+		pomodoro("Tea", 3*time.Minute, time.Minute, 1),
+	}
+
+	// Export from one setup
+	tm, _ := newTestManager(t)
+	if err := config.SaveTemplates(config.DataFile(config.TemplatesFile), templates); err != nil {
+		t.Fatal(err)
+	}
+	tm.Lock()
+	tm.Configs = configs
+	tm.Prefs = prefs
+	err := tm.ExportBundle(path)
+	tm.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// and import into another that has a timer and a template of its own
+	other, _ := newTestManager(t)
+	ownTemplate := pomodoro("Tea", 5*time.Minute, time.Minute, 1)
+	if err := config.SaveTemplates(config.DataFile(config.TemplatesFile), []config.TimerConfig{ownTemplate}); err != nil {
+		t.Fatal(err)
+	}
+	addTimers(other, pomodoro("Read", 30*time.Minute, 5*time.Minute, 4))
+	bundle, err := ReadBundle(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other.Lock()
+	err = other.ImportBundle(bundle, true)
+	gotConfigs, gotPrefs := other.Configs, other.Prefs
+	other.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantConfigs := append([]config.TimerConfig{pomodoro("Read", 30*time.Minute, 5*time.Minute, 4)}, configs...)
+	if !reflect.DeepEqual(gotConfigs, wantConfigs) {
+		t.Errorf("configs = %+v\nwant      %+v", gotConfigs, wantConfigs)
+	}
+	if names, want := activeNames(other), []string{"Read", "Tea", "Stretch"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("timers = %v, want %v", names, want)
+	}
+	if !reflect.DeepEqual(gotPrefs, prefs) {
+		t.Errorf("preferences = %+v, want %+v", gotPrefs, prefs)
+	}
+	if saved, err := config.LoadPreferences(); err != nil || !reflect.DeepEqual(saved, prefs) {
+		t.Errorf("saved preferences = %+v, %v, want %+v", saved, err, prefs)
+	}
+	// The template named like one already there isn't taken
+	gotTemplates, err := config.LoadTemplates(config.DataFile(config.TemplatesFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []config.TimerConfig{ownTemplate, templates[0]}; !reflect.DeepEqual(gotTemplates, want) {
+		t.Errorf("templates = %+v\nwant        %+v", gotTemplates, want)
+	}
+}
+
+func TestBundleFromNewerVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "setup.json")
+	if err := os.WriteFile(path, []byte(`{"Version": 2, "Configs": []}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadBundle(path); err == nil || !strings.Contains(err.Error(), "version 2") {
+		t.Errorf("got error %v, want one about version 2", err)
+	}
+}
@@ -0,0 +1,227 @@
+package manager
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"multi-timer/config"
+)
+
+// How far ahead calendar imports look by default
+const DefaultCalendarWindow = 24 * time.Hour
+
+type calendarEvent struct {
+	summary string
+	start   time.Time
+}
+
+// calendarSource provides the events countdowns are made for
+type calendarSource interface {
+	events() ([]calendarEvent, error)
+}
+
+// IcsFile reads the events of an iCalendar file
+type IcsFile string
+
+func (path IcsFile) events() ([]calendarEvent, error) {
+	data, err := os.ReadFile(string(path))
+	if err != nil {
+		return nil, err
+	}
+	return parseICS(data)
+}
+
+// eventList is a fixed set of events, for sources that already have them parsed
+type eventList []calendarEvent
+
+func (list eventList) events() ([]calendarEvent, error) {
+	return list, nil
+}
+
+// parseICS reads the summary and start of every VEVENT. Only what's needed
+// for countdowns is understood, recurrence rules are ignored.
+func parseICS(data []byte) ([]calendarEvent, error) {
+	var events []calendarEvent
+	var current *calendarEvent
+	for i, line := range unfoldICS(data) {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		name, params, _ := strings.Cut(name, ";")
+		switch strings.ToUpper(name) {
+		case "BEGIN":
+			if strings.EqualFold(value, "VEVENT") {
+				current = &calendarEvent{}
+			}
+		case "END":
+			if strings.EqualFold(value, "VEVENT") && current != nil {
+				if !current.start.IsZero() {
+					events = append(events, *current)
+				}
+				current = nil
+			}
+		case "SUMMARY":
+			if current != nil {
+				current.summary = unescapeICS(value)
+			}
+		case "DTSTART":
+			if current == nil {
+				continue
+			}
+			start, err := parseICSTime(value, params)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", i+1, err)
+			}
+			current.start = start
+		}
+	}
+	return events, nil
+}
+
+// unfoldICS joins the continuation lines, which start with a space or tab
+func unfoldICS(data []byte) []string {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func unescapeICS(value string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
+
+// parseICSTime handles UTC ("...Z"), TZID and floating local times as well
+// as all-day dates
+func parseICSTime(value, params string) (time.Time, error) {
+	loc := time.Local
+	for _, param := range strings.Split(params, ";") {
+		key, tzid, _ := strings.Cut(param, "=")
+		if strings.EqualFold(key, "TZID") {
+			l, err := time.LoadLocation(strings.Trim(tzid, `"`))
+			if err != nil {
+				return time.Time{}, fmt.Errorf("unknown time zone %q", tzid)
+			}
+			loc = l
+		}
+	}
+	switch {
+	case strings.HasSuffix(value, "Z"):
+		return time.Parse("20060102T150405Z", value)
+	case len(value) == len("20060102"):
+		return time.ParseInLocation("20060102", value, loc)
+	default:
+		return time.ParseInLocation("20060102T150405", value, loc)
+	}
+}
+
+// CalendarCountdowns makes a single countdown config for every event that
+// starts within window of now, soonest first
+func CalendarCountdowns(source calendarSource, now time.Time, window time.Duration) ([]config.TimerConfig, error) {
+	events, err := source.events()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].start.Before(events[j].start) })
+
+	var configs []config.TimerConfig
+	for _, event := range events {
+		until := event.start.Sub(now).Truncate(time.Second)
+		if until <= 0 || until > window {
+			continue
+		}
+		name := event.summary
+		if name == "" {
+			name = "Event"
+		}
+		configs = append(configs, config.TimerConfig{
+			Name:      name,
+			NotifText: fmt.Sprintf("%s starts now", name),
+			Phases:    []config.TimerPhase{{WorkDuration: until}},
+			MaxCycles: 1,
+		})
+	}
+	return configs, nil
+}
+
+// RenderICS turns the work blocks of the history at or after since into an
+// iCalendar file with one VEVENT each, in UTC so any calendar app places them
+// right
+func RenderICS(entries []HistoryEntry, since, now time.Time) string {
+	var b strings.Builder
+	line := func(s string) {
+		b.WriteString(foldICS(s))
+		b.WriteString("\r\n")
+	}
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//multi-timer//sessions//EN")
+	line("CALSCALE:GREGORIAN")
+	stamp := now.UTC().Format("20060102T150405Z")
+	for _, entry := range entries {
+		if entry.Event != "work" || entry.Start == nil || entry.Start.Before(since) {
+			continue
+		}
+		start := entry.Start.UTC().Format("20060102T150405Z")
+		line("BEGIN:VEVENT")
+		line(fmt.Sprintf("UID:%s-%s@multi-timer", start, escapeUID(entry.Timer)))
+		line("DTSTAMP:" + stamp)
+		line("DTSTART:" + start)
+		line("DTEND:" + entry.Time.UTC().Format("20060102T150405Z"))
+		line("SUMMARY:" + escapeICS(entry.Timer))
+		line(fmt.Sprintf("DESCRIPTION:Phase %d\\, cycle %d", max(entry.Phase, 1), entry.Cycle))
+		if len(entry.Tags) > 0 {
+			tags := make([]string, len(entry.Tags))
+			for i, tag := range entry.Tags {
+				tags[i] = escapeICS(tag)
+			}
+			line("CATEGORIES:" + strings.Join(tags, ","))
+		}
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return b.String()
+}
+
+func escapeICS(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(value)
+}
+
+// escapeUID keeps the timer name in a UID to letters, digits and dashes
+func escapeUID(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 128 && (r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return r
+		}
+		return '-'
+	}, name)
+}
+
+// foldICS breaks lines longer than 75 bytes, continuation lines start with a
+// space. Multi-byte characters are never split.
+func foldICS(line string) string {
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
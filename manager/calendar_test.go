@@ -0,0 +1,74 @@
+package manager
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"multi-timer/config"
+)
+
+const sampleICS = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Standup\r\n" +
+	"DTSTART:20240304T094500Z\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Review with design\\, product and the long\r\n" +
+	"  list of others\r\n" +
+	"DTSTART;TZID=UTC:20240304T120000\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Breakfast\r\n" +
+	"DTSTART:20240304T080000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Tomorrow's planning\r\n" +
+	"DTSTART:20240305T100000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART:20240304T100000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestCalendarCountdowns(t *testing.T) {
+	events, err := parseICS([]byte(sampleICS))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 5 {
+		t.Fatalf("parsed %d events, want 5: %+v", len(events), events)
+	}
+
+	now := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	configs, err := CalendarCountdowns(eventList(events), now, 8*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	countdown := func(name string, until time.Duration) config.TimerConfig {
+		return config.TimerConfig{
+			Name:      name,
+			NotifText: name + " starts now",
+			Phases:    []config.TimerPhase{{WorkDuration: until}},
+			MaxCycles: 1,
+		}
+	}
+	// Breakfast is over and the planning is past the window
+	want := []config.TimerConfig{
+		countdown("Standup", 45*time.Minute),
+		countdown("Event", time.Hour),
+		countdown("Review with design, product and the long list of others", 3*time.Hour),
+	}
+	if !reflect.DeepEqual(configs, want) {
+		t.Errorf("configs = %+v\nwant      %+v", configs, want)
+	}
+}
+
+func TestParseICSBadStart(t *testing.T) {
+	data := "BEGIN:VEVENT\nSUMMARY:Standup\nDTSTART:tomorrow\nEND:VEVENT\n"
+	if _, err := parseICS([]byte(data)); err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("got error %v, want one about line 3", err)
+	}
+}
@@ -0,0 +1,33 @@
+package manager
+
+import (
+	"errors"
+	"slices"
+
+	"multi-timer/timer"
+)
+
+// startNext starts the config a completed timer chains into. A chain that
+// leads back to a timer it already ran stops there instead of looping
+// forever. Must be called with tm.mu held.
+func (tm *TimerManager) startNext(t *timer.Timer) {
+	if t.Next == "" {
+		return
+	}
+	chain := append(slices.Clone(t.Chain), t.State.Name)
+	if slices.Contains(chain, t.Next) {
+		tm.ReportError("Not starting "+t.Next, errors.New("the chain loops back to it"))
+		return
+	}
+	for _, config := range tm.Configs {
+		if config.Name != t.Next || len(config.Phases) == 0 {
+			continue
+		}
+		next := timer.TimerFromConfig(config)
+		next.Chain = chain
+		tm.ActiveTimers = append(tm.ActiveTimers, next)
+		tm.PauseGroup(next)
+		return
+	}
+	tm.ReportError("Not starting "+t.Next, errors.New("there is no timer with that name"))
+}
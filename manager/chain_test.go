@@ -0,0 +1,68 @@
+package manager
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"multi-timer/config"
+)
+
+func chained(name, next string) config.TimerConfig {
+	cfg := pomodoro(name, 2*time.Minute, time.Minute, 1)
+	cfg.NextTimer = next
+	return cfg
+}
+
+func TestChainStartsSuccessor(t *testing.T) {
+	tm, _ := newTestManager(t)
+	addTimers(tm, chained("Focus", "Review"))
+	tm.Lock()
+	tm.Configs = append(tm.Configs, chained("Review", ""))
+	tm.Unlock()
+
+	advance(tm, 2*time.Minute)
+	if got, want := activeNames(tm), []string{"Focus"}; !slices.Equal(got, want) {
+		t.Errorf("timers = %v before Focus completed, want %v", got, want)
+	}
+
+	advance(tm, time.Minute)
+	if got, want := activeNames(tm), []string{"Review"}; !slices.Equal(got, want) {
+		t.Fatalf("timers = %v after Focus completed, want %v", got, want)
+	}
+	tm.Lock()
+	defer tm.Unlock()
+	if review := tm.ActiveTimers[0]; review.State.CurrentTime != 2*time.Minute || !review.State.IsWork {
+		t.Errorf("Review starts with %v of work %t, want a fresh 2m", review.State.CurrentTime, review.State.IsWork)
+	}
+}
+
+func TestChainStopsAtLoop(t *testing.T) {
+	tm, _ := newTestManager(t)
+	addTimers(tm, chained("Focus", "Review"))
+	tm.Lock()
+	tm.Configs = append(tm.Configs, chained("Review", "Focus"))
+	tm.Unlock()
+
+	advance(tm, 6*time.Minute)
+	if got := activeNames(tm); len(got) != 0 {
+		t.Errorf("timers = %v, want the chain to stop after Review", got)
+	}
+	tm.Lock()
+	defer tm.Unlock()
+	if !strings.Contains(tm.lastError, "Not starting Focus") || !strings.Contains(tm.lastError, "loops back") {
+		t.Errorf("error = %q, want one about the loop", tm.lastError)
+	}
+}
+
+func TestChainToMissingTimer(t *testing.T) {
+	tm, _ := newTestManager(t)
+	addTimers(tm, chained("Focus", "Gone"))
+	advance(tm, 3*time.Minute)
+	tm.Lock()
+	defer tm.Unlock()
+	if !strings.Contains(tm.lastError, "Not starting Gone") {
+		t.Errorf("error = %q, want one about the missing timer", tm.lastError)
+	}
+}
@@ -0,0 +1,36 @@
+package manager
+
+import "multi-timer/timer"
+
+// The actions on a single timer that the prompt, the full screen interface,
+// the daemon and the HTTP API all offer, so they behave the same everywhere.
+
+// TogglePause pauses a running timer or resumes a paused one, together with
+// its linked group. Resuming pauses the rest of its exclusive group, pausing
+// a stopwatch logs its laps. It returns false for a deep work cycle, which
+// can't be paused. Must be called with tm.mu held.
+func (tm *TimerManager) TogglePause(t *timer.Timer) bool {
+	if !t.Pauseable() {
+		return false
+	}
+	t.IsPaused = !t.IsPaused
+	if !t.IsPaused {
+		tm.PauseGroup(t)
+	} else {
+		tm.WriteLaps(t, false)
+	}
+	tm.pauseLinked(t)
+	return true
+}
+
+// ResetTimer starts the timer over the way prefs.ResetKeepsCycles asks,
+// logging the laps and the part of the segment cut short first. Must be
+// called with tm.mu held.
+func (tm *TimerManager) ResetTimer(t *timer.Timer) {
+	tm.WriteLaps(t, true)
+	tm.LogInterrupted(t)
+	t.Reset(tm.Prefs.ResetKeepsCycles)
+	if !t.Started().IsZero() {
+		t.SetStarted(tm.Now()) // the history shouldn't count the time before the reset
+	}
+}
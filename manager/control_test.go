@@ -0,0 +1,37 @@
+package manager
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResetModes(t *testing.T) {
+	for _, keepCycles := range []bool{false, true} {
+		tm, _ := newTestManager(t)
+		tm.Prefs.ResetKeepsCycles = keepCycles
+		timers := addTimers(tm, pomodoro("Tea", 2*time.Minute, time.Minute, 4))
+		// Halfway through the second cycle's break
+		advance(tm, 5*time.Minute+30*time.Second)
+
+		tm.Lock()
+		timer := timers[0]
+		if timer.State.Cycles != 2 || timer.State.IsWork {
+			t.Fatalf("before the reset: cycle %d, work %v, want the second break", timer.State.Cycles, timer.State.IsWork)
+		}
+		tm.ResetTimer(timer)
+		state := timer.State
+		tm.Unlock()
+
+		if keepCycles {
+			if state.Cycles != 2 || state.IsWork || state.CurrentTime != time.Minute {
+				t.Errorf("segment reset: cycle %d, work %v, %v left, want the second break restarted at 1m",
+					state.Cycles, state.IsWork, state.CurrentTime)
+			}
+			continue
+		}
+		if state.Cycles != 1 || !state.IsWork || state.CurrentPhase != 0 || state.CurrentTime != 2*time.Minute {
+			t.Errorf("full reset: cycle %d, phase %d, work %v, %v left, want the first work segment at 2m",
+				state.Cycles, state.CurrentPhase, state.IsWork, state.CurrentTime)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package manager
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"multi-timer/config"
+	"multi-timer/timer"
+)
+
+// `multi-timer daemon` runs the timers without a display, listening on a Unix
+// socket so they keep going after the terminal is closed, and `multi-timer
+// client <command>` sends it one command and prints the answer.
+const SocketFile = "multi-timer.sock"
+
+const daemonUsage = "commands: list, pause <number>, reset <number>, add <name> <work> <break> [cycles], start <preset> [name]"
+
+// ErrNoDaemon is returned by RunClient when there is no daemon to send to
+var ErrNoDaemon = errors.New("no daemon running")
+
+// errDaemonRunning is returned by ServeDaemon when another daemon already
+// answers on the socket
+var errDaemonRunning = errors.New("daemon already running")
+
+// ServeDaemon answers clients until the listener fails
+func (tm *TimerManager) ServeDaemon(path string) error {
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return fmt.Errorf("%w on %s", errDaemonRunning, path)
+	}
+	os.Remove(path) // left behind by a daemon that didn't shut down cleanly
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+	fmt.Println("Daemon listening on", path)
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		go tm.serveClient(conn)
+	}
+}
+
+// serveClient reads one command line and writes back the answer
+func (tm *TimerManager) serveClient(conn net.Conn) {
+	defer conn.Close()
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return
+	}
+	fmt.Fprint(conn, tm.daemonCommand(strings.TrimSpace(line)))
+}
+
+// daemonCommand runs a client command and returns the answer, one or more
+// lines. Names with spaces are sent double quoted.
+func (tm *TimerManager) daemonCommand(command string) string {
+	fields := splitCommand(command)
+	if len(fields) == 0 {
+		return daemonUsage + "\n"
+	}
+	tm.mu.Lock()
+	tm.NoteInput()
+	tm.mu.Unlock()
+
+	name := strings.ToLower(fields[0])
+	switch name {
+	case "list":
+		tm.Lock()
+		defer tm.Unlock()
+		if len(tm.ActiveTimers) == 0 {
+			return "No timers running.\n"
+		}
+		var b strings.Builder
+		for i, timer := range tm.ActiveTimers {
+			status := ""
+			if timer.IsPaused {
+				status = " (PAUSED)"
+			}
+			fmt.Fprintf(&b, "%d. %s%s\n", i+1, timer.Render(tm.Prefs), status)
+		}
+		return b.String()
+
+	case "pause", "reset":
+		if len(fields) != 2 {
+			return fmt.Sprintf("Usage: %s <number>\n", name)
+		}
+		num, err := strconv.Atoi(fields[1])
+		tm.Lock()
+		defer tm.Unlock()
+		if err != nil || num < 1 || num > len(tm.ActiveTimers) {
+			return "Invalid timer number.\n"
+		}
+		timer := tm.ActiveTimers[num-1]
+		if name == "reset" {
+			tm.ResetTimer(timer)
+			return fmt.Sprintf("Reset %s.\n", timer.State.Name)
+		}
+		if !tm.TogglePause(timer) {
+			return "Deep work cycle, it can't be paused.\n"
+		}
+		if !timer.IsPaused {
+			return fmt.Sprintf("Resumed %s.\n", timer.State.Name)
+		}
+		return fmt.Sprintf("Paused %s.\n", timer.State.Name)
+
+	case "add":
+		if len(fields) < 4 || len(fields) > 5 {
+			return "Usage: add <name> <work> <break> [cycles]\n"
+		}
+		work, err := config.ParseDuration(fields[2])
+		if err != nil || work <= 0 {
+			return config.DurationProblem(err) + "\n"
+		}
+		rest, err := config.ParseDuration(fields[3])
+		if err != nil || rest < 0 {
+			return config.DurationProblem(err) + "\n"
+		}
+		cfg := config.TimerConfig{
+			Name:      fields[1],
+			NotifText: fields[1],
+			Phases:    []config.TimerPhase{{WorkDuration: work, BreakDuration: rest}},
+			MaxCycles: -1,
+		}
+		if len(fields) == 5 {
+			if cfg.MaxCycles, err = strconv.Atoi(fields[4]); err != nil {
+				return "Invalid number of cycles.\n"
+			}
+		}
+		if err := config.CheckLimits(cfg); err != nil {
+			return err.Error() + "\n"
+		}
+		if err := tm.AddTimer(cfg); err != nil {
+			return fmt.Sprintf("Error saving timer configurations: %v\n", err)
+		}
+		return fmt.Sprintf("Added %s.\n", cfg.Name)
+
+	case "start":
+		if len(fields) < 2 {
+			return "Usage: start <preset> [name]\n"
+		}
+		cfg, err := config.FindPreset(fields[1])
+		if err != nil {
+			return err.Error() + "\n"
+		}
+		if len(fields) > 2 {
+			cfg.Name = strings.Join(fields[2:], " ")
+		}
+		if err := tm.AddTimer(cfg); err != nil {
+			return fmt.Sprintf("Error saving timer configurations: %v\n", err)
+		}
+		return fmt.Sprintf("Started %s.\n", cfg.Name)
+	}
+	return daemonUsage + "\n"
+}
+
+// AddTimer starts a timer for the config and saves it with the others
+func (tm *TimerManager) AddTimer(cfg config.TimerConfig) error {
+	tm.Lock()
+	tm.appendTimer(cfg)
+	tm.Unlock()
+	return tm.SaveConfigs()
+}
+
+// appendTimer starts a timer from the config, last in the display, and adds
+// the config to the saved ones. Must be called with tm.mu held.
+func (tm *TimerManager) appendTimer(cfg config.TimerConfig) {
+	t := timer.TimerFromConfig(cfg)
+	tm.ActiveTimers = append(tm.ActiveTimers, t)
+	tm.PauseGroup(t)
+	tm.Configs = append(tm.Configs, cfg)
+}
+
+// RunClient sends the command and its arguments to the daemon and copies the
+// answer to out. Every argument is quoted, so one with spaces stays one field.
+func RunClient(path string, args []string, out io.Writer) error {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrNoDaemon, err)
+	}
+	defer conn.Close()
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = strconv.Quote(arg)
+	}
+	if _, err := fmt.Fprintln(conn, strings.Join(quoted, " ")); err != nil {
+		return err
+	}
+	_, err = io.Copy(out, conn)
+	return err
+}
+
+// splitCommand splits a daemon command into fields like strings.Fields,
+// except that a double quoted field may contain spaces
+func splitCommand(line string) []string {
+	var fields []string
+	for {
+		line = strings.TrimLeft(line, " \t")
+		if line == "" {
+			return fields
+		}
+		if line[0] == '"' {
+			if quoted, err := strconv.QuotedPrefix(line); err == nil {
+				field, _ := strconv.Unquote(quoted)
+				fields = append(fields, field)
+				line = line[len(quoted):]
+				continue
+			}
+		}
+		end := strings.IndexAny(line, " \t")
+		if end < 0 {
+			end = len(line)
+		}
+		fields = append(fields, line[:end])
+		line = line[end:]
+	}
+}
@@ -0,0 +1,107 @@
+package manager
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"multi-timer/config"
+)
+
+// socketPath is a socket in a new temporary directory, short enough for the
+// limit on socket paths that t.TempDir can exceed
+func socketPath(t *testing.T) string {
+	dir, err := os.MkdirTemp("", "mt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return filepath.Join(dir, SocketFile)
+}
+
+func TestDaemonCommands(t *testing.T) {
+	tm, _ := newTestManager(t)
+	if got := tm.daemonCommand("list"); got != "No timers running.\n" {
+		t.Errorf("list with no timers = %q", got)
+	}
+	if got := tm.daemonCommand(`add "Tea time" 2m 1m 4`); got != "Added Tea time.\n" {
+		t.Fatalf("add = %q", got)
+	}
+	if got := tm.daemonCommand("add Read 0 1m"); got == "Added Read.\n" {
+		t.Error("added a timer without work time")
+	}
+	advance(tm, 30*time.Second)
+
+	if got := tm.daemonCommand("PAUSE 1"); got != "Paused Tea time.\n" {
+		t.Errorf("PAUSE 1 = %q", got)
+	}
+	if got := tm.daemonCommand("list"); !strings.HasPrefix(got, "1. Tea time") || !strings.HasSuffix(got, " (PAUSED)\n") {
+		t.Errorf("list = %q, want Tea time paused", got)
+	}
+	if got := tm.daemonCommand("pause 2"); got != "Invalid timer number.\n" {
+		t.Errorf("pause 2 = %q", got)
+	}
+	if got := tm.daemonCommand("Reset 1"); got != "Reset Tea time.\n" {
+		t.Errorf("Reset 1 = %q", got)
+	}
+	tm.Lock()
+	left := tm.ActiveTimers[0].State.CurrentTime
+	tm.Unlock()
+	if left != 2*time.Minute {
+		t.Errorf("Tea time has %v left after the reset, want 2m", left)
+	}
+
+	saved, err := config.LoadTimerConfigs(tm.ConfigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(saved) != 1 || saved[0].Name != "Tea time" || saved[0].MaxCycles != 4 {
+		t.Errorf("saved configs = %+v, want the added timer", saved)
+	}
+}
+
+func TestClientTalksToDaemon(t *testing.T) {
+	path := socketPath(t)
+	if err := RunClient(path, []string{"list"}, &strings.Builder{}); !errors.Is(err, ErrNoDaemon) {
+		t.Errorf("client without a daemon: %v, want errNoDaemon", err)
+	}
+
+	tm, _ := newTestManager(t)
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go tm.serveClient(conn)
+		}
+	}()
+
+	var out strings.Builder
+	if err := RunClient(path, []string{"add", "Tea time", "25m", "5m"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "Added Tea time.\n" {
+		t.Errorf("add answered %q", out.String())
+	}
+	out.Reset()
+	if err := RunClient(path, []string{"list"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out.String(), "1. Tea time") {
+		t.Errorf("list answered %q", out.String())
+	}
+
+	if err := tm.ServeDaemon(path); !errors.Is(err, errDaemonRunning) {
+		t.Errorf("second daemon on the socket: %v, want errDaemonRunning", err)
+	}
+}
@@ -0,0 +1,74 @@
+package manager
+
+import (
+	"fmt"
+
+	"multi-timer/config"
+	"multi-timer/timer"
+)
+
+// pauseRules maps each timer name to the timers it pauses when it starts work
+func pauseRules(configs []config.TimerConfig) map[string][]string {
+	rules := make(map[string][]string)
+	for _, config := range configs {
+		rules[config.Name] = append(rules[config.Name], config.PausesOnWork...)
+	}
+	return rules
+}
+
+// reaches reports whether from pauses to, directly or through other rules
+func reaches(rules map[string][]string, from, to string) bool {
+	seen := make(map[string]bool)
+	var visit func(name string) bool
+	visit = func(name string) bool {
+		if name == to {
+			return true
+		}
+		if seen[name] {
+			return false
+		}
+		seen[name] = true
+		for _, next := range rules[name] {
+			if visit(next) {
+				return true
+			}
+		}
+		return false
+	}
+	return visit(from)
+}
+
+// ConflictingRules lists the rules that lead back to the timer that set them
+// off, those are ignored so timers don't keep pausing each other
+func ConflictingRules(configs []config.TimerConfig) []string {
+	rules := pauseRules(configs)
+	var conflicts []string
+	for _, config := range configs {
+		for _, target := range config.PausesOnWork {
+			if reaches(rules, target, config.Name) {
+				conflicts = append(conflicts, fmt.Sprintf("%s pauses %s", config.Name, target))
+			}
+		}
+	}
+	return conflicts
+}
+
+// pauseDependents pauses the timers t has a rule for, now that it started
+// work. Must be called with tm.mu held.
+func (tm *TimerManager) pauseDependents(t *timer.Timer) {
+	if len(t.PausesOnWork) == 0 {
+		return
+	}
+	rules := pauseRules(tm.Configs)
+	for _, target := range t.PausesOnWork {
+		if reaches(rules, target, t.State.Name) {
+			continue
+		}
+		for _, timer := range tm.ActiveTimers {
+			if timer != t && timer.State.Name == target && timer.Pauseable() {
+				tm.catchUp(timer)
+				timer.IsPaused = true
+			}
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package manager
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"multi-timer/config"
+)
+
+func pausing(name string, targets ...string) config.TimerConfig {
+	cfg := pomodoro(name, 2*time.Minute, time.Minute, 4)
+	cfg.PausesOnWork = targets
+	return cfg
+}
+
+func TestWorkStartPausesDependents(t *testing.T) {
+	tm, _ := newTestManager(t)
+	addTimers(tm,
+		pausing("Focus", "Email"),
+		pomodoro("Email", 30*time.Minute, 5*time.Minute, 4),
+		pomodoro("Tea", 30*time.Minute, 5*time.Minute, 4),
+	)
+	advance(tm, 2*time.Minute+30*time.Second) // Focus is on its break
+	tm.Lock()
+	if got := pausedNames(tm); len(got) != 0 {
+		t.Errorf("paused %v before Focus's next work, want none", got)
+	}
+	tm.Unlock()
+
+	advance(tm, 30*time.Second)
+	tm.Lock()
+	defer tm.Unlock()
+	if got, want := pausedNames(tm), []string{"Email"}; !slices.Equal(got, want) {
+		t.Errorf("paused %v once Focus started work, want %v", got, want)
+	}
+}
+
+func TestConflictingRulesAreIgnored(t *testing.T) {
+	configs := []config.TimerConfig{
+		pausing("Focus", "Email"),
+		pausing("Email", "Chat"),
+		pausing("Chat", "Focus"),
+		pausing("Tea", "Email"),
+	}
+	want := []string{"Focus pauses Email", "Email pauses Chat", "Chat pauses Focus"}
+	if got := ConflictingRules(configs); !slices.Equal(got, want) {
+		t.Errorf("conflicts = %v, want %v", got, want)
+	}
+
+	tm, _ := newTestManager(t)
+	addTimers(tm, configs...)
+	advance(tm, 3*time.Minute)
+	tm.Lock()
+	defer tm.Unlock()
+	// Only Tea's rule stands
+	if got, want := pausedNames(tm), []string{"Email"}; !slices.Equal(got, want) {
+		t.Errorf("paused %v, want only %v", got, want)
+	}
+}
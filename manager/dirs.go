@@ -0,0 +1,105 @@
+package manager
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"multi-timer/config"
+)
+
+// UseDataDir makes dir the data directory, creating it. The first time, the
+// files older versions kept in the working directory are moved into it, and
+// their names are returned so the move can be reported.
+func UseDataDir(dir string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	config.DataDir = dir
+	if same, err := sameDir(dir, "."); err != nil || same {
+		return nil, err
+	}
+
+	// Only when the data directory has no timers yet, so a config made in
+	// some other directory doesn't replace the one in use
+	base := strings.TrimSuffix(config.ConfigFile, filepath.Ext(config.ConfigFile))
+	configs := []string{base + ".json", base + ".yaml", base + ".yml", base + ".toml"}
+	for _, name := range configs {
+		if _, err := os.Stat(config.DataFile(name)); err == nil {
+			return nil, nil
+		}
+	}
+	local := false
+	for _, name := range configs {
+		if _, err := os.Stat(name); err == nil {
+			local = true
+		}
+	}
+	if !local {
+		return nil, nil
+	}
+
+	var moved []string
+	for _, name := range append(configs, config.PrefsFile, config.TemplatesFile, historyFile, ArchiveFile, StateFile, profilesDir) {
+		if _, err := os.Stat(name); err != nil {
+			continue
+		}
+		if _, err := os.Stat(config.DataFile(name)); err == nil {
+			continue
+		}
+		if err := moveFile(name, config.DataFile(name)); err != nil {
+			return moved, fmt.Errorf("moving %s to %s: %w", name, dir, err)
+		}
+		moved = append(moved, name)
+	}
+	return moved, nil
+}
+
+func sameDir(a, b string) (bool, error) {
+	infoA, err := os.Stat(a)
+	if err != nil {
+		return false, err
+	}
+	infoB, err := os.Stat(b)
+	if err != nil {
+		return false, err
+	}
+	return os.SameFile(infoA, infoB), nil
+}
+
+// moveFile renames from to to, copying files when they are on different
+// file systems
+func moveFile(from, to string) error {
+	if err := os.Rename(from, to); err == nil {
+		return nil
+	}
+	info, err := os.Stat(from)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return os.Rename(from, to) // a directory has to be moved by hand, say why
+	}
+
+	src, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(to, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		os.Remove(to)
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(to)
+		return err
+	}
+	return os.Remove(from)
+}
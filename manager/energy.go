@@ -0,0 +1,103 @@
+package manager
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"multi-timer/config"
+	"multi-timer/timer"
+)
+
+// How far ahead the energy command looks, and the level below which an hour
+// counts as a focus dip
+const (
+	EnergyWindow = 4 * time.Hour
+	lowEnergy    = 50
+)
+
+// defaultEnergyCurve is the expected focus, 0 to 100, for every hour of the
+// day: a morning peak, the dip after lunch and a smaller late afternoon peak
+var defaultEnergyCurve = []int{
+	20, 20, 20, 20, 20, 25, 35, 50, 65, 80, 90, 85,
+	70, 50, 40, 45, 60, 70, 65, 55, 45, 35, 25, 20,
+}
+
+// transition is where a timer moves on to the segment seg
+type transition struct {
+	Index int
+	Name  string
+	At    time.Time
+	Seg   timer.Segment
+}
+
+type energyNote struct {
+	transition
+	Level int
+	Dip   bool
+}
+
+// energyLevel is the level of the curve for the hour of at. Curves that
+// don't have all 24 hours fall back to the default.
+func energyLevel(curve []int, at time.Time) int {
+	if len(curve) != 24 {
+		curve = defaultEnergyCurve
+	}
+	return curve[at.Hour()]
+}
+
+// AnnotateEnergy gives every transition the level of the hour it happens in
+func AnnotateEnergy(transitions []transition, curve []int) []energyNote {
+	notes := make([]energyNote, len(transitions))
+	for i, tr := range transitions {
+		level := energyLevel(curve, tr.At)
+		notes[i] = energyNote{tr, level, level < lowEnergy}
+	}
+	return notes
+}
+
+// ParseEnergyCurve sets the levels of hours like "13=40,14=35" on top of curve
+func ParseEnergyCurve(input string, curve []int) ([]int, error) {
+	if len(curve) != 24 {
+		curve = defaultEnergyCurve
+	}
+	result := append([]int(nil), curve...)
+	for _, field := range strings.Split(input, ",") {
+		hour, level, ok := strings.Cut(strings.TrimSpace(field), "=")
+		h, err1 := strconv.Atoi(hour)
+		l, err2 := strconv.Atoi(level)
+		if !ok || err1 != nil || err2 != nil || h < 0 || h > 23 || l < 0 || l > 100 {
+			return nil, fmt.Errorf("invalid hour level %q, use HOUR=LEVEL with hours 0-23 and levels 0-100", field)
+		}
+		result[h] = l
+	}
+	return result, nil
+}
+
+// UpcomingTransitions lists the transitions of the running countdowns within
+// window of now, soonest first. Must be called with tm.mu held.
+func (tm *TimerManager) UpcomingTransitions(now time.Time, window time.Duration) []transition {
+	var transitions []transition
+	for i, t := range tm.ActiveTimers {
+		if t.IsPaused || t.CountUp || t.OnOpenBreak() {
+			continue
+		}
+		at := now.Add(t.State.CurrentTime)
+		for _, seg := range t.UpcomingSegments(timer.GanttPreview) {
+			if at.Sub(now) > window {
+				break
+			}
+			transitions = append(transitions, transition{i + 1, t.State.Name, at, seg})
+			if seg.Duration == config.UntilResumed {
+				break
+			}
+			at = at.Add(seg.Duration)
+		}
+	}
+	sort.SliceStable(transitions, func(i, j int) bool {
+		return transitions[i].At.Before(transitions[j].At)
+	})
+	return transitions
+}
@@ -0,0 +1,73 @@
+package manager
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"multi-timer/timer"
+)
+
+func TestAnnotateEnergy(t *testing.T) {
+	curve, err := ParseEnergyCurve("13=30,14=45,15=55", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	at := func(hour, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+	transitions := []transition{
+		{1, "Tea", at(10, 30), timer.Segment{Stage: timer.StageCycles, Cycle: 1, IsWork: false, Duration: 5 * time.Minute}},
+		{1, "Tea", at(12, 59), timer.Segment{Stage: timer.StageCycles, Cycle: 2, IsWork: true, Duration: 25 * time.Minute}},
+		{2, "Read", at(13, 0), timer.Segment{Stage: timer.StageCycles, Cycle: 1, IsWork: false, Duration: 5 * time.Minute}},
+		{2, "Read", at(14, 10), timer.Segment{Stage: timer.StageCycles, Cycle: 2, IsWork: true, Duration: 25 * time.Minute}},
+		{1, "Tea", at(15, 45), timer.Segment{Stage: timer.StageCycles, Cycle: 2, IsWork: false, Duration: 5 * time.Minute}},
+	}
+	var levels []int
+	var dips []bool
+	for i, note := range AnnotateEnergy(transitions, curve) {
+		if note.transition != transitions[i] {
+			t.Errorf("note %d is for %+v, want %+v", i, note.transition, transitions[i])
+		}
+		levels = append(levels, note.Level)
+		dips = append(dips, note.Dip)
+	}
+	if want := []int{90, 70, 30, 45, 55}; !slices.Equal(levels, want) {
+		t.Errorf("levels = %v, want %v", levels, want)
+	}
+	if want := []bool{false, false, true, true, false}; !slices.Equal(dips, want) {
+		t.Errorf("dips = %v, want %v", dips, want)
+	}
+}
+
+func TestEnergyCurve(t *testing.T) {
+	// A curve without every hour falls back to the default
+	if got := energyLevel([]int{1, 2, 3}, time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)); got != defaultEnergyCurve[10] {
+		t.Errorf("level = %d, want the default %d", got, defaultEnergyCurve[10])
+	}
+	for _, input := range []string{"24=10", "13=101", "13", "x=1"} {
+		if _, err := ParseEnergyCurve(input, nil); err == nil {
+			t.Errorf("ParseEnergyCurve(%q) succeeded", input)
+		}
+	}
+}
+
+func TestUpcomingTransitions(t *testing.T) {
+	tm, _ := newTestManager(t)
+	addTimers(tm,
+		pomodoro("Tea", 25*time.Minute, 5*time.Minute, 4),
+		pomodoro("Read", 50*time.Minute, 10*time.Minute, 1),
+	)
+	advance(tm, 10*time.Minute)
+	tm.Lock()
+	defer tm.Unlock()
+	var got []string
+	for _, tr := range tm.UpcomingTransitions(tm.Now(), time.Hour) {
+		got = append(got, tr.At.Format("15:04")+" "+tr.Name)
+	}
+	want := []string{"09:25 Tea", "09:30 Tea", "09:50 Read", "09:55 Tea", "10:00 Tea"}
+	if !slices.Equal(got, want) {
+		t.Errorf("transitions = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,30 @@
+package manager
+
+import "multi-timer/timer"
+
+// watchForAck starts waiting for the notification to be acknowledged, a newer
+// notification replaces an older one still waiting. Must be called with tm.mu held.
+func (tm *TimerManager) watchForAck(t *timer.Timer, title, message string) {
+	if !t.Escalate.Enabled() {
+		return
+	}
+	t.Alert = &timer.PendingAlert{Title: title, Message: message, Due: tm.Now().Add(t.Escalate.After)}
+	if tm.nextAlert.IsZero() || t.Alert.Due.Before(tm.nextAlert) {
+		tm.nextAlert = t.Alert.Due
+	}
+}
+
+// escalateAlert resends the pending notification as critical once it is
+// overdue. Must be called with tm.mu held.
+func (tm *TimerManager) escalateAlert(t *timer.Timer) {
+	if t.Alert == nil || tm.Now().Before(t.Alert.Due) {
+		return
+	}
+	tm.SendNotification(t.Alert.Title, t.Alert.Message, CriticalUrgency)
+	t.Alert.Sent++
+	if t.Alert.Sent >= t.Escalate.Repeats {
+		t.Alert = nil
+		return
+	}
+	t.Alert.Due = t.Alert.Due.Add(t.Escalate.After)
+}
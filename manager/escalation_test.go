@@ -0,0 +1,59 @@
+package manager
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"multi-timer/config"
+)
+
+func escalating() config.TimerConfig {
+	cfg := pomodoro("Stretch", time.Minute, 10*time.Minute, 4)
+	cfg.NotifText = "Stretch"
+	cfg.EscalateDuration = 30 * time.Second
+	cfg.EscalateRepeats = 2
+	return cfg
+}
+
+func TestEscalatesWithoutAck(t *testing.T) {
+	tm, notifier := newTestManager(t)
+	addTimers(tm, escalating())
+	advance(tm, time.Minute)
+	if want := []Urgency{NormalUrgency}; !slices.Equal(notifier.levels, want) {
+		t.Fatalf("notified %v at the break, want one normal notification", notifier.notifications)
+	}
+
+	advance(tm, 29*time.Second)
+	if len(notifier.levels) != 1 {
+		t.Errorf("escalated before the timeout: %v", notifier.notifications)
+	}
+	advance(tm, time.Second)
+	if want := []Urgency{NormalUrgency, CriticalUrgency}; !slices.Equal(notifier.levels, want) {
+		t.Errorf("urgencies %v after the timeout, want %v", notifier.levels, want)
+	}
+	if notifier.notifications[1] != notifier.notifications[0] {
+		t.Errorf("escalated %q, want the break notification %q again", notifier.notifications[1], notifier.notifications[0])
+	}
+
+	// It repeats up to the max and then gives up
+	advance(tm, 5*time.Minute)
+	if want := []Urgency{NormalUrgency, CriticalUrgency, CriticalUrgency}; !slices.Equal(notifier.levels, want) {
+		t.Errorf("urgencies %v, want %v", notifier.levels, want)
+	}
+}
+
+func TestAckStopsEscalation(t *testing.T) {
+	tm, notifier := newTestManager(t)
+	timers := addTimers(tm, escalating())
+	advance(tm, time.Minute+10*time.Second)
+	tm.Lock()
+	if !timers[0].Acknowledge() {
+		t.Error("no notification was waiting for the ack")
+	}
+	tm.Unlock()
+	advance(tm, 5*time.Minute)
+	if want := []Urgency{NormalUrgency}; !slices.Equal(notifier.levels, want) {
+		t.Errorf("urgencies %v after the ack, want only the normal notification", notifier.levels)
+	}
+}
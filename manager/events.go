@@ -0,0 +1,90 @@
+package manager
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"multi-timer/timer"
+)
+
+// SentNotification is a notification a timer fired, for the status line and
+// the pinned pane
+type SentNotification struct {
+	Timer   string
+	Message string
+	At      time.Time
+}
+
+// handleEvents reacts to what happened to the timer during its last update.
+// Must be called with tm.mu held.
+func (tm *TimerManager) handleEvents(t *timer.Timer) {
+	for _, event := range t.Events() {
+		switch event.Kind {
+		case timer.PhaseCompleted:
+			if outcome := t.Phases[event.Phase].Outcome; outcome != "" {
+				tm.reviews = append(tm.reviews, OutcomeReview{t.State.Name, event.Phase, outcome, tm.Now()})
+			}
+		case timer.Notification:
+			title := tm.notificationTitle(t, event)
+			message := event.Message
+			if event.Announce && tm.Override != nil {
+				message = tm.Override.text(event.Segment)
+			}
+			if event.Announce && tm.Prefs.Words && !t.CountUp && !t.OnOpenBreak() {
+				message += fmt.Sprintf(", %s of %s", timer.DurationWords(t.State.CurrentTime), strings.ToLower(event.Segment))
+			}
+			if event.Announce && event.Segment == "Break" {
+				tm.sendBreakNotification(t, title, message)
+			} else {
+				tm.SendNotification(title, message, NormalUrgency)
+			}
+			tm.logNotification(SentNotification{t.State.Name, message, tm.Now()})
+			tm.watchForAck(t, title, message)
+			if event.Announce {
+				tm.playSound(t.SegmentSound(event))
+			}
+			if event.Announce && event.Segment == "Work" {
+				tm.pauseDependents(t)
+			}
+			if event.Announce && (event.Segment == "Work" || event.Segment == "Break") {
+				tm.callWebhooks(t, WebhookPayload{
+					Event: strings.ToLower(event.Segment) + "-start",
+					Timer: t.State.Name,
+					Phase: event.Phase + 1,
+					Cycle: event.Cycle,
+					Time:  tm.Now(),
+				})
+			}
+		case timer.SegmentEnded:
+			now := tm.Now()
+			if !t.Started().IsZero() {
+				start := t.Started()
+				tm.logHistory(HistoryEntry{
+					Time:  now,
+					Start: &start,
+					Timer: t.State.Name,
+					Tags:  t.Tags,
+					Event: strings.ToLower(event.Segment),
+					Phase: event.Phase + 1,
+					Cycle: event.Cycle,
+
+					Active:      event.Active,
+					Interrupted: event.Interrupted,
+				})
+			}
+			t.SetStarted(now)
+		case timer.CycleCompleted:
+			tm.recordCompletion(t)
+			tm.logHistory(HistoryEntry{
+				Time:  tm.Now(),
+				Timer: t.State.Name,
+				Tags:  t.Tags,
+				Event: "cycle",
+				Phase: event.Phase + 1,
+				Cycle: event.Cycle,
+			})
+		}
+	}
+	t.ClearEvents()
+}
@@ -0,0 +1,160 @@
+package manager
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"multi-timer/config"
+)
+
+// sessionRecord is one work or break block of the history, as the export
+// command writes it
+type sessionRecord struct {
+	Timer   string
+	Tags    []string `json:",omitempty"`
+	Event   string   // work, break, warmup or cooldown
+	Phase   int
+	Cycle   int
+	Start   time.Time
+	End     time.Time
+	Seconds int64  // the time the block ran, less the pauses
+	Status  string // completed, or interrupted when skipped, reset or deleted
+}
+
+// sessionRecords picks the blocks of the history that started in [from, to),
+// a zero time leaving that end open
+func sessionRecords(entries []HistoryEntry, from, to time.Time) []sessionRecord {
+	var records []sessionRecord
+	for _, entry := range entries {
+		if entry.Start == nil || !slices.Contains([]string{"work", "break", "warmup", "cooldown"}, entry.Event) {
+			continue
+		}
+		if entry.Start.Before(from) || !to.IsZero() && !entry.Start.Before(to) {
+			continue
+		}
+		status := "completed"
+		if entry.Interrupted {
+			status = "interrupted"
+		}
+		records = append(records, sessionRecord{
+			Timer:   entry.Timer,
+			Tags:    entry.Tags,
+			Event:   entry.Event,
+			Phase:   entry.Phase,
+			Cycle:   entry.Cycle,
+			Start:   *entry.Start,
+			End:     entry.Time,
+			Seconds: int64(entry.duration().Round(time.Second) / time.Second),
+			Status:  status,
+		})
+	}
+	return records
+}
+
+// writeSessions writes the records as CSV with a header row, tags separated
+// by semicolons, or as a JSON array
+func writeSessions(w io.Writer, records []sessionRecord, format string) error {
+	switch format {
+	case "json":
+		if records == nil {
+			records = []sessionRecord{} // [] rather than null
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(records)
+	case "csv":
+		writer := csv.NewWriter(w)
+		writer.Write([]string{"timer", "tags", "event", "phase", "cycle", "start", "end", "seconds", "status"})
+		for _, record := range records {
+			writer.Write([]string{
+				record.Timer,
+				strings.Join(record.Tags, ";"),
+				record.Event,
+				strconv.Itoa(record.Phase),
+				strconv.Itoa(record.Cycle),
+				record.Start.Format(time.RFC3339),
+				record.End.Format(time.RFC3339),
+				strconv.FormatInt(record.Seconds, 10),
+				record.Status,
+			})
+		}
+		writer.Flush()
+		return writer.Error()
+	}
+	return fmt.Errorf("unknown format %q, use csv or json", format)
+}
+
+// ExportCommand writes the sessions of the history between two dates, both
+// included:
+//
+//	multi-timer export --format csv --from 2024-03-01 --to 2024-03-31
+func ExportCommand(args []string, configPath string, out io.Writer) error {
+	set := flag.NewFlagSet("export", flag.ContinueOnError)
+	set.SetOutput(out)
+	format := set.String("format", "csv", "csv or json")
+	fromDate := set.String("from", "", "first day to export, YYYY-MM-DD (default: the start of the history)")
+	toDate := set.String("to", "", "last day to export, YYYY-MM-DD (default: the end of the history)")
+	output := set.String("output", "", "file to write to (default: standard output)")
+	if err := set.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if set.NArg() > 0 {
+		return fmt.Errorf("unexpected %q after the flags", strings.Join(set.Args(), " "))
+	}
+	if *format != "csv" && *format != "json" {
+		return fmt.Errorf("unknown format %q, use csv or json", *format)
+	}
+
+	var from, to time.Time
+	if *fromDate != "" {
+		day, err := time.ParseInLocation("2006-01-02", *fromDate, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid --from date %q, use YYYY-MM-DD", *fromDate)
+		}
+		from = day
+	}
+	if *toDate != "" {
+		day, err := time.ParseInLocation("2006-01-02", *toDate, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid --to date %q, use YYYY-MM-DD", *toDate)
+		}
+		to = day.AddDate(0, 0, 1)
+	}
+	if !to.IsZero() && !from.Before(to) {
+		return fmt.Errorf("--from is after --to")
+	}
+
+	entries, err := LoadHistory(config.DataFile(historyFile))
+	if err != nil {
+		return err
+	}
+	records := sessionRecords(entries, from, to)
+	if *output == "" {
+		return writeSessions(out, records, *format)
+	}
+	file, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err := writeSessions(file, records, *format); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Exported %d sessions to %s.\n", len(records), *output)
+	return nil
+}
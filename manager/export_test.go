@@ -0,0 +1,92 @@
+package manager
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"multi-timer/config"
+)
+
+// exportHistory is a block on each of three days, the last one cut short
+func exportHistory() []HistoryEntry {
+	block := func(day, hour int, event string, interrupted bool) HistoryEntry {
+		start := time.Date(2024, 3, day, hour, 0, 0, 0, time.Local)
+		return HistoryEntry{
+			Time: start.Add(25 * time.Minute), Start: &start, Timer: "Tea", Tags: []string{"study", "deep"},
+			Event: event, Phase: 1, Cycle: 1, Active: 25 * time.Minute, Interrupted: interrupted,
+		}
+	}
+	return []HistoryEntry{
+		block(1, 9, "work", false),
+		{Time: time.Date(2024, 3, 1, 9, 25, 0, 0, time.Local), Timer: "Tea", Event: "cycle", Cycle: 1},
+		block(2, 9, "break", false),
+		block(3, 9, "work", true),
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	from := time.Date(2024, 3, 2, 0, 0, 0, 0, time.Local)
+	records := sessionRecords(exportHistory(), from, time.Time{})
+	var out strings.Builder
+	if err := writeSessions(&out, records, "csv"); err != nil {
+		t.Fatal(err)
+	}
+	start := func(day int) string { return time.Date(2024, 3, day, 9, 0, 0, 0, time.Local).Format(time.RFC3339) }
+	end := func(day int) string { return time.Date(2024, 3, day, 9, 25, 0, 0, time.Local).Format(time.RFC3339) }
+	want := "timer,tags,event,phase,cycle,start,end,seconds,status\n" +
+		"Tea,study;deep,break,1,1," + start(2) + "," + end(2) + ",1500,completed\n" +
+		"Tea,study;deep,work,1,1," + start(3) + "," + end(3) + ",1500,interrupted\n"
+	if out.String() != want {
+		t.Errorf("csv =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestExportCommandJSON(t *testing.T) {
+	saved := config.DataDir
+	config.DataDir = t.TempDir()
+	t.Cleanup(func() { config.DataDir = saved })
+	for _, entry := range exportHistory() {
+		if err := appendHistory(config.DataFile(historyFile), entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	output := filepath.Join(t.TempDir(), "sessions.json")
+	var out strings.Builder
+	args := []string{"--format", "json", "--from", "2024-03-01", "--to", "2024-03-02", "--output", output}
+	if err := ExportCommand(args, "", &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "Exported 2 sessions to "+output+".\n" {
+		t.Errorf("export printed %q", out.String())
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var records []sessionRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Event != "work" || records[1].Event != "break" || records[1].Seconds != 1500 {
+		t.Errorf("records = %+v, want the blocks of March 1 and 2", records)
+	}
+
+	for _, args := range [][]string{
+		{"--format", "xml"},
+		{"--from", "March 1"},
+		{"--from", "2024-03-03", "--to", "2024-03-01"},
+	} {
+		if err := ExportCommand(args, "", &out); err == nil {
+			t.Errorf("export %q succeeded", args)
+		}
+	}
+	out.Reset()
+	if err := ExportCommand([]string{"--format", "json", "--from", "2025-01-01"}, "", &out); err != nil || out.String() != "[]\n" {
+		t.Errorf("export of no sessions = %q, %v, want []", out.String(), err)
+	}
+}
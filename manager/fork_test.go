@@ -0,0 +1,60 @@
+package manager
+
+import (
+	"testing"
+	"time"
+
+	"multi-timer/config"
+)
+
+func TestForkEvolvesIndependently(t *testing.T) {
+	tm, notifier := newTestManager(t)
+	cfg := config.TimerConfig{
+		Name: "Essay",
+		Phases: []config.TimerPhase{
+			{WorkDuration: 10 * time.Minute, BreakDuration: 5 * time.Minute},
+			{WorkDuration: 20 * time.Minute, BreakDuration: 5 * time.Minute},
+		},
+		MaxCycles:  1,
+		StartSound: "start.wav",
+		Tags:       []string{"writing"},
+	}
+	timers := addTimers(tm, cfg)
+	original := timers[0]
+	advance(tm, 3*time.Minute)
+
+	tm.Lock()
+	forked := original.Fork()
+	tm.ActiveTimers = append(tm.ActiveTimers, forked)
+	original.IsPaused = true
+	tm.Unlock()
+	if !forked.Started().Equal(original.Started()) {
+		t.Errorf("fork's segment started at %v, want the original's %v", forked.Started(), original.Started())
+	}
+	if forked.State.Name != "Essay (fork)" || forked.State.CurrentTime != 7*time.Minute || !forked.State.IsWork {
+		t.Fatalf("fork %q has %v of work %t left, want Essay (fork) with 7m of work",
+			forked.State.Name, forked.State.CurrentTime, forked.State.IsWork)
+	}
+
+	advance(tm, 10*time.Minute)
+	tm.Lock()
+	if forked.State.IsWork || forked.State.CurrentTime != 2*time.Minute {
+		t.Errorf("fork has %v of work %t left, want 2m of its break", forked.State.CurrentTime, forked.State.IsWork)
+	}
+	if !original.State.IsWork || original.State.CurrentTime != 7*time.Minute {
+		t.Errorf("the paused original moved on to %v of work %t", original.State.CurrentTime, original.State.IsWork)
+	}
+
+	// Changing one doesn't show through to the other
+	original.Phases[1].WorkDuration = time.Minute
+	original.Tags[0] = "email"
+	if forked.Phases[1].WorkDuration != 20*time.Minute || forked.Tags[0] != "writing" {
+		t.Error("the fork shares its phases or tags with the original")
+	}
+	tm.Unlock()
+
+	// The fork carries on the segment, it isn't a timer that just started
+	if len(notifier.sounds) != 1 {
+		t.Errorf("sounds %v, want only the original's start sound", notifier.sounds)
+	}
+}
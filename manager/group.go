@@ -0,0 +1,89 @@
+package manager
+
+import (
+	"slices"
+
+	"multi-timer/timer"
+)
+
+// PauseGroup pauses every other running timer in the exclusive group of t,
+// or in focus mode every other timer, called whenever t starts or resumes.
+// Must be called with tm.mu held.
+func (tm *TimerManager) PauseGroup(t *timer.Timer) {
+	if t.Group == "" && !tm.Prefs.FocusMode {
+		return
+	}
+	for _, timer := range tm.ActiveTimers {
+		if timer == t || t.LinkedTo(timer) {
+			continue
+		}
+		if (timer.Group == t.Group && t.Group != "" || tm.Prefs.FocusMode) && timer.Pauseable() {
+			tm.catchUp(timer)
+			timer.IsPaused = true
+		}
+	}
+}
+
+// GroupRunning reports whether another timer of the exclusive group of t is
+// already running, or in focus mode any other timer. Must be called with
+// tm.mu held.
+func (tm *TimerManager) GroupRunning(t *timer.Timer) bool {
+	if t.Group == "" && !tm.Prefs.FocusMode {
+		return false
+	}
+	for _, timer := range tm.ActiveTimers {
+		if timer != t && (timer.Group == t.Group || tm.Prefs.FocusMode) && !timer.IsPaused {
+			return true
+		}
+	}
+	return false
+}
+
+// pauseLinked pauses or resumes the other timers of the linked group of t so
+// they match it. Deep work cycles are left running. Must be called with tm.mu
+// held.
+func (tm *TimerManager) pauseLinked(t *timer.Timer) {
+	for _, timer := range tm.ActiveTimers {
+		if timer == t || !t.LinkedTo(timer) || timer.IsPaused == t.IsPaused {
+			continue
+		}
+		if t.IsPaused && !timer.Pauseable() {
+			continue
+		}
+		tm.catchUp(timer)
+		timer.IsPaused = t.IsPaused
+		if timer.IsPaused {
+			tm.WriteLaps(timer, false)
+		}
+	}
+}
+
+// PauseAll pauses every running timer, or when none is running resumes the
+// ones it paused last time, and returns the names of those it changed. Deep
+// work cycles aren't paused. Must be called with tm.mu held.
+func (tm *TimerManager) PauseAll() (names []string, paused bool) {
+	for _, timer := range tm.ActiveTimers {
+		if !timer.IsPaused && timer.Pauseable() {
+			names = append(names, timer.State.Name)
+		}
+	}
+	if len(names) > 0 {
+		tm.pausedByAll = tm.pausedByAll[:0]
+		for _, timer := range tm.ActiveTimers {
+			if !timer.IsPaused && timer.Pauseable() {
+				timer.IsPaused = true
+				tm.pausedByAll = append(tm.pausedByAll, timer)
+			}
+		}
+		return names, true
+	}
+	for _, timer := range tm.pausedByAll {
+		if !slices.Contains(tm.ActiveTimers, timer) || !timer.IsPaused || tm.GroupRunning(timer) {
+			continue
+		}
+		timer.IsPaused = false
+		names = append(names, timer.State.Name)
+	}
+	tm.pausedByAll = nil
+	return names, false
+}
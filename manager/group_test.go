@@ -0,0 +1,54 @@
+package manager
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"multi-timer/config"
+)
+
+// pausedNames lists the names of the paused timers. Must be called with
+// tm.mu held.
+func pausedNames(tm *TimerManager) []string {
+	var names []string
+	for _, timer := range tm.ActiveTimers {
+		if timer.IsPaused {
+			names = append(names, timer.State.Name)
+		}
+	}
+	return names
+}
+
+func TestResumingPausesExclusiveGroup(t *testing.T) {
+	tm, _ := newTestManager(t)
+	grouped := func(name, group string) config.TimerConfig {
+		cfg := pomodoro(name, 25*time.Minute, 5*time.Minute, 4)
+		cfg.ExclusiveGroup = group
+		return cfg
+	}
+	timers := addTimers(tm,
+		grouped("Email", "desk"),
+		grouped("Code", "desk"),
+		grouped("Review", "desk"),
+		grouped("Squats", "gym"),
+		grouped("Tea", ""),
+	)
+	tm.Lock()
+	defer tm.Unlock()
+	timers[0].IsPaused = true
+
+	tm.TogglePause(timers[0])
+	if got, want := pausedNames(tm), []string{"Code", "Review"}; !slices.Equal(got, want) {
+		t.Errorf("resuming Email paused %v, want %v", got, want)
+	}
+	tm.TogglePause(timers[1])
+	if got, want := pausedNames(tm), []string{"Email", "Review"}; !slices.Equal(got, want) {
+		t.Errorf("resuming Code paused %v, want %v", got, want)
+	}
+	// Pausing leaves the rest of the group alone
+	tm.TogglePause(timers[1])
+	if got, want := pausedNames(tm), []string{"Email", "Code", "Review"}; !slices.Equal(got, want) {
+		t.Errorf("pausing Code left %v paused, want %v", got, want)
+	}
+}
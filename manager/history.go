@@ -0,0 +1,231 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"multi-timer/config"
+	"multi-timer/timer"
+)
+
+const historyFile = "history.jsonl"
+
+// HistoryEntry is one line of the history log
+type HistoryEntry struct {
+	Time     time.Time
+	Start    *time.Time `json:",omitempty"` // for work/break blocks, Time is the end
+	Timer    string
+	Tags     []string `json:",omitempty"` // the timer's at the time, for stats by tag
+	Event    string
+	Phase    int    `json:",omitempty"` // 1-based like the display
+	Cycle    int    `json:",omitempty"`
+	Outcome  string `json:",omitempty"`
+	Achieved *bool  `json:",omitempty"`
+
+	// How long a work or break block ran by the timer: the wall clock time
+	// from Start to Time less the pauses, scaled by the speed. Missing from
+	// entries written before it was added, see duration.
+	Active time.Duration `json:",omitempty"`
+
+	// The work or break block ended early by a skip, reset or delete
+	Interrupted bool `json:",omitempty"`
+}
+
+// duration is how long the block ran, the wall clock time for older entries
+// without Active
+func (entry HistoryEntry) duration() time.Duration {
+	if entry.Active > 0 || entry.Start == nil {
+		return entry.Active
+	}
+	return entry.Time.Sub(*entry.Start)
+}
+
+// historyWrite is a request to the history writer: an entry to log to path,
+// with switchDB the database to mirror the entries after it into, or with
+// flushed a channel to close once everything before it is written
+type historyWrite struct {
+	path     string
+	entry    HistoryEntry
+	switchDB bool
+	db       *historyDB
+	flushed  chan struct{}
+}
+
+// writeHistoryLoop writes the history in the background, so the disk and the
+// database never hold tm.mu. It owns the database set with UseDatabase.
+func (tm *TimerManager) writeHistoryLoop() {
+	var db *historyDB
+	for write := range tm.historyWrites {
+		switch {
+		case write.switchDB:
+			if db != nil {
+				db.close()
+			}
+			db = write.db
+		case write.flushed != nil:
+			close(write.flushed)
+		default:
+			if err := writeHistory(write.path, db, write.entry); err != nil {
+				// Not here, the manager may be waiting to queue the next entry
+				go func() {
+					tm.mu.Lock()
+					tm.ReportError("Error writing history", err)
+					tm.mu.Unlock()
+					tm.redraw()
+				}()
+			}
+		}
+	}
+}
+
+// logHistory queues the entry for the history writer. Must be called with
+// tm.mu held.
+func (tm *TimerManager) logHistory(entry HistoryEntry) {
+	tm.historyWrites <- historyWrite{path: tm.HistoryPath, entry: entry}
+}
+
+// FlushHistory waits for the entries logged so far to be written
+func (tm *TimerManager) FlushHistory() {
+	flushed := make(chan struct{})
+	tm.historyWrites <- historyWrite{flushed: flushed}
+	<-flushed
+}
+
+func appendHistory(path string, entry HistoryEntry) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return json.NewEncoder(file).Encode(entry)
+}
+
+// LoadHistory reads every entry of the history at path, none if there is no
+// file yet
+func LoadHistory(path string) ([]HistoryEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []HistoryEntry
+	decoder := json.NewDecoder(file)
+	for decoder.More() {
+		var entry HistoryEntry
+		if err := decoder.Decode(&entry); err != nil {
+			return entries, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// How many completions a config keeps in RecentCompletions
+const recentLimit = 20
+
+// recordCompletion adds a completed cycle to the timer's saved config so the
+// config carries its own recent history. The file is written by
+// saveCompletions once tm.mu is released. Must be called with tm.mu held.
+func (tm *TimerManager) recordCompletion(t *timer.Timer) {
+	pairs := tm.PairConfigs()
+	for i, timer := range tm.ActiveTimers {
+		if timer != t || pairs[i] < 0 {
+			continue
+		}
+		config := &tm.Configs[pairs[i]]
+		// Whole seconds in UTC come back from the file unchanged
+		config.RecentCompletions = append(config.RecentCompletions, tm.Now().UTC().Round(time.Second))
+		if extra := len(config.RecentCompletions) - recentLimit; extra > 0 {
+			config.RecentCompletions = append([]time.Time(nil), config.RecentCompletions[extra:]...)
+		}
+		tm.completionsChanged = true
+		return
+	}
+}
+
+// saveCompletions saves the configs if recordCompletion changed them, from a
+// copy so the tick never waits on the disk
+func (tm *TimerManager) saveCompletions() {
+	tm.mu.Lock()
+	if !tm.completionsChanged {
+		tm.mu.Unlock()
+		return
+	}
+	tm.completionsChanged = false
+	path, configs := tm.ConfigPath, slices.Clone(tm.Configs)
+	tm.mu.Unlock()
+
+	if err := config.SaveTimerConfigs(path, configs); err != nil {
+		tm.mu.Lock()
+		tm.ReportError("Error saving timer configurations", err)
+		tm.mu.Unlock()
+		tm.redraw()
+	}
+}
+
+// OutcomeReview is a completed phase with an outcome, waiting for the answer
+// whether it was achieved
+type OutcomeReview struct {
+	Timer   string
+	Phase   int
+	Outcome string
+	at      time.Time
+}
+
+// NextReview is the oldest outcome review waiting for an answer. Must be
+// called with tm.mu held.
+func (tm *TimerManager) NextReview() (OutcomeReview, bool) {
+	if len(tm.reviews) == 0 {
+		return OutcomeReview{}, false
+	}
+	return tm.reviews[0], true
+}
+
+// RecordOutcome answers the oldest outcome review and logs it to the history
+func (tm *TimerManager) RecordOutcome(achieved bool) error {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	if len(tm.reviews) == 0 {
+		return fmt.Errorf("no completed phase is waiting for an outcome")
+	}
+	review := tm.reviews[0]
+	tm.reviews = tm.reviews[1:]
+	tm.logHistory(HistoryEntry{
+		Time:     review.at,
+		Timer:    review.Timer,
+		Event:    "outcome",
+		Phase:    review.Phase + 1,
+		Outcome:  review.Outcome,
+		Achieved: &achieved,
+	})
+	return nil
+}
+
+// LogInterrupted logs the part of the current segment that ran before a reset
+// or delete cut it short. Must be called with tm.mu held.
+func (tm *TimerManager) LogInterrupted(t *timer.Timer) {
+	if t.Started().IsZero() || t.IsStopwatch() {
+		return
+	}
+	start := t.Started()
+	tm.logHistory(HistoryEntry{
+		Time:  tm.Now(),
+		Start: &start,
+		Timer: t.State.Name,
+		Tags:  t.Tags,
+		Event: strings.ToLower(t.SegmentLabel()),
+		Phase: t.State.CurrentPhase + 1,
+		Cycle: t.State.Cycles,
+
+		Active:      t.Active,
+		Interrupted: true,
+	})
+}
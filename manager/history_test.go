@@ -0,0 +1,108 @@
+package manager
+
+import (
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"multi-timer/config"
+)
+
+func TestOutcomeReview(t *testing.T) {
+	tm, _ := newTestManager(t)
+	addTimers(tm, config.TimerConfig{
+		Name: "Essay",
+		Phases: []config.TimerPhase{
+			{WorkDuration: 2 * time.Minute, BreakDuration: time.Minute, Outcome: "finish section 2"},
+			{WorkDuration: 2 * time.Minute, BreakDuration: time.Minute},
+		},
+		MaxCycles: 1,
+	})
+	advance(tm, 2*time.Minute)
+	if err := tm.RecordOutcome(true); err == nil {
+		t.Fatal("recorded an outcome before the phase completed")
+	}
+
+	advance(tm, time.Minute) // the break ends the phase
+	tm.Lock()
+	reviews := len(tm.reviews)
+	tm.Unlock()
+	if reviews != 1 {
+		t.Fatalf("%d outcome reviews waiting after the phase, want 1", reviews)
+	}
+	if err := tm.RecordOutcome(true); err != nil {
+		t.Fatal(err)
+	}
+	// The second phase has no outcome to review
+	advance(tm, 3*time.Minute)
+	if err := tm.RecordOutcome(false); err == nil {
+		t.Error("recorded an outcome for a phase without one")
+	}
+
+	tm.FlushHistory()
+	entries, err := LoadHistory(tm.HistoryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var outcomes []HistoryEntry
+	for _, entry := range entries {
+		if entry.Event == "outcome" {
+			outcomes = append(outcomes, entry)
+		}
+	}
+	if len(outcomes) != 1 {
+		t.Fatalf("history has %d outcomes, want 1: %+v", len(outcomes), entries)
+	}
+	got := outcomes[0]
+	if got.Timer != "Essay" || got.Phase != 1 || got.Outcome != "finish section 2" || got.Achieved == nil || !*got.Achieved {
+		t.Errorf("outcome entry = %+v, want Essay's phase 1 achieved", got)
+	}
+}
+
+func TestRecentCompletionsRoundTrip(t *testing.T) {
+	tm, _ := newTestManager(t)
+	addTimers(tm,
+		pomodoro("Tea", 2*time.Minute, time.Minute, 30),
+		pomodoro("Read", 5*time.Minute, time.Minute, 30),
+	)
+	advance(tm, 6*time.Minute)
+	tm.saveCompletions()
+
+	configs, err := config.LoadTimerConfigs(tm.ConfigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(clock time.Duration) time.Time {
+		return time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC).Add(clock)
+	}
+	if got, want := configs[0].RecentCompletions, []time.Time{at(3 * time.Minute), at(6 * time.Minute)}; !slices.Equal(got, want) {
+		t.Errorf("Tea's saved completions = %v, want %v", got, want)
+	}
+	if got, want := configs[1].RecentCompletions, []time.Time{at(6 * time.Minute)}; !slices.Equal(got, want) {
+		t.Errorf("Read's saved completions = %v, want %v", got, want)
+	}
+
+	// Tea runs to its last cycle at 10:30, the list keeps only the most recent
+	advance(tm, 90*time.Minute)
+	tm.saveCompletions()
+	configs, err = config.LoadTimerConfigs(tm.ConfigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recent := configs[0].RecentCompletions
+	if len(recent) != recentLimit || !recent[len(recent)-1].Equal(at(90*time.Minute)) {
+		t.Errorf("Tea keeps %d completions ending %v, want %d ending with the cycle at 10:30",
+			len(recent), recent[len(recent)-1], recentLimit)
+	}
+}
+
+func TestSaveCompletionsOnlyWhenChanged(t *testing.T) {
+	tm, _ := newTestManager(t)
+	addTimers(tm, pomodoro("Tea", 2*time.Minute, time.Minute, 4))
+	advance(tm, time.Minute)
+	tm.saveCompletions()
+	if _, err := os.Stat(tm.ConfigPath); !os.IsNotExist(err) {
+		t.Errorf("saved the configs before a cycle completed: %v", err)
+	}
+}
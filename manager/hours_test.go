@@ -0,0 +1,57 @@
+package manager
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"multi-timer/config"
+)
+
+func TestPhaseSkippedOutsideHours(t *testing.T) {
+	for _, test := range []struct {
+		start  time.Duration // after 09:00
+		phases []int
+	}{
+		{0, []int{1, 2, 3}},                             // exercise at 09:11, in its hours
+		{11 * time.Hour, []int{1, 3}},                   // exercise at 20:11, skipped
+		{21*time.Hour + 50*time.Minute, []int{1, 2, 3}}, // past midnight, 07:01
+	} {
+		tm, notifier := newTestManager(t)
+		tm.clock = test.start
+		timers := addTimers(tm, config.TimerConfig{
+			Name: "Routine",
+			Phases: []config.TimerPhase{
+				{WorkDuration: 10 * time.Minute, BreakDuration: time.Minute},
+				{WorkDuration: 10 * time.Minute, BreakDuration: time.Minute, ActiveHours: "06:00-20:00"},
+				{WorkDuration: 10 * time.Minute, BreakDuration: time.Minute},
+			},
+			MaxCycles: 1,
+		})
+
+		var phases []int
+		for range 60 * 60 {
+			advance(tm, time.Second)
+			tm.Lock()
+			phase, running := timers[0].State.CurrentPhase+1, len(tm.ActiveTimers) > 0
+			tm.Unlock()
+			if !running {
+				break
+			}
+			if len(phases) == 0 || phases[len(phases)-1] != phase {
+				phases = append(phases, phase)
+			}
+		}
+		start := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC).Add(test.start).Format("15:04")
+		if !slices.Equal(phases, test.phases) {
+			t.Errorf("starting at %s the timer went through phases %v, want %v", start, phases, test.phases)
+		}
+		skipped := slices.ContainsFunc(notifier.notifications, func(n string) bool {
+			return strings.Contains(n, "Skipping phase 2 outside its hours")
+		})
+		if want := !slices.Contains(test.phases, 2); skipped != want {
+			t.Errorf("starting at %s skip notified %v, want %v: %q", start, skipped, want, notifier.notifications)
+		}
+	}
+}
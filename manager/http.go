@@ -0,0 +1,231 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"multi-timer/config"
+)
+
+// The -http server controls the timers from scripts and the like:
+//
+//	GET    /timers              list the timers
+//	POST   /timers              add one, {"Name": "Tea", "Work": "25m", "Break": "5m", "Cycles": 4}
+//	POST   /timers/{id}/pause   pause it, if it isn't already
+//	POST   /timers/{id}/resume  resume it, if it is paused
+//	POST   /timers/{id}/reset   reset it
+//	DELETE /timers/{id}         delete it
+//
+// Timer ids are the 1-based numbers of the display.
+
+// timerStatus is how a timer is shown in the answers
+type timerStatus struct {
+	ID        int
+	Name      string
+	Segment   string
+	Phase     int // 1-based like the display
+	Cycle     int
+	Remaining float64 // seconds, elapsed seconds for count-ups
+	CountUp   bool    `json:",omitempty"`
+	Paused    bool
+}
+
+// NewTimer is the body of POST /timers, Cycles 0 runs until deleted
+type NewTimer struct {
+	Name   string
+	Work   string
+	Break  string
+	Cycles int
+}
+
+// apiHandler serves the timers under /timers
+func (tm *TimerManager) apiHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/timers", tm.handleTimers)
+	mux.HandleFunc("/timers/", tm.handleTimer)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tm.mu.Lock()
+		tm.NoteInput() // requests count as input for idle-quit
+		tm.mu.Unlock()
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// ServeHTTP exposes the timers on addr
+func (tm *TimerManager) ServeHTTP(addr string) error {
+	// Listen up front so a bad address is reported right away
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	handler := tm.apiHandler()
+	go func() {
+		if err := http.Serve(listener, handler); err != nil {
+			tm.mu.Lock()
+			tm.ReportError("Error serving the HTTP API", err)
+			tm.mu.Unlock()
+			tm.redraw()
+		}
+	}()
+	return nil
+}
+
+// statuses lists the timers. Must be called with tm.mu held.
+func (tm *TimerManager) statuses() []timerStatus {
+	statuses := make([]timerStatus, 0, len(tm.ActiveTimers))
+	for i, timer := range tm.ActiveTimers {
+		statuses = append(statuses, timerStatus{
+			ID:        i + 1,
+			Name:      timer.State.Name,
+			Segment:   timer.SegmentLabel(),
+			Phase:     timer.State.CurrentPhase + 1,
+			Cycle:     timer.State.Cycles,
+			Remaining: max(timer.State.CurrentTime, 0).Seconds(),
+			CountUp:   timer.CountUp,
+			Paused:    timer.IsPaused,
+		})
+	}
+	return statuses
+}
+
+func (tm *TimerManager) handleTimers(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		tm.Lock()
+		statuses := tm.statuses()
+		tm.Unlock()
+		writeJSON(w, http.StatusOK, statuses)
+
+	case http.MethodPost:
+		var body NewTimer
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+			return
+		}
+		cfg, err := body.Config()
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		tm.Lock()
+		tm.appendTimer(cfg)
+		status := tm.statuses()[len(tm.ActiveTimers)-1]
+		tm.Unlock()
+		tm.redraw()
+		if err := tm.SaveConfigs(); err != nil {
+			writeError(w, http.StatusInternalServerError, "saving timer configurations: "+err.Error())
+			return
+		}
+		writeJSON(w, http.StatusCreated, status)
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "use GET or POST")
+	}
+}
+
+// handleTimer serves /timers/{id} and the actions under it
+func (tm *TimerManager) handleTimer(w http.ResponseWriter, r *http.Request) {
+	id, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/timers/"), "/")
+	num, err := strconv.Atoi(id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "no such timer")
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+	case action == "" && r.Method == http.MethodDelete:
+	case (action == "pause" || action == "resume" || action == "reset") && r.Method == http.MethodPost:
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "use GET or DELETE on a timer, POST on its pause, resume and reset")
+		return
+	}
+
+	tm.Lock()
+	if num < 1 || num > len(tm.ActiveTimers) {
+		tm.Unlock()
+		writeError(w, http.StatusNotFound, "no such timer")
+		return
+	}
+	timer := tm.ActiveTimers[num-1]
+	status := tm.statuses()[num-1]
+
+	switch {
+	case r.Method == http.MethodDelete:
+		if j := tm.PairConfigs()[num-1]; j >= 0 {
+			tm.Configs = append(tm.Configs[:j], tm.Configs[j+1:]...)
+		}
+		tm.WriteLaps(timer, true)
+		tm.LogInterrupted(timer)
+		tm.ActiveTimers = append(tm.ActiveTimers[:num-1], tm.ActiveTimers[num:]...)
+		tm.Unlock()
+		if err := tm.SaveConfigs(); err != nil {
+			writeError(w, http.StatusInternalServerError, "saving timer configurations: "+err.Error())
+			return
+		}
+		tm.redraw()
+		writeJSON(w, http.StatusOK, status)
+		return
+
+	case action == "pause" && !timer.IsPaused, action == "resume" && timer.IsPaused:
+		if !tm.TogglePause(timer) {
+			tm.Unlock()
+			writeError(w, http.StatusConflict, "deep work cycle, it can't be paused")
+			return
+		}
+
+	case action == "reset":
+		tm.ResetTimer(timer)
+	}
+	status = tm.statuses()[num-1]
+	tm.Unlock()
+	tm.redraw()
+	writeJSON(w, http.StatusOK, status)
+}
+
+// Config checks the body and turns it into a timer config
+func (body NewTimer) Config() (config.TimerConfig, error) {
+	if strings.TrimSpace(body.Name) == "" {
+		return config.TimerConfig{}, fmt.Errorf("missing Name")
+	}
+	work, err := config.ParseDuration(body.Work)
+	if err != nil || work <= 0 {
+		return config.TimerConfig{}, fmt.Errorf("Work: %s", config.DurationProblem(err))
+	}
+	rest, err := config.ParseDuration(body.Break)
+	if err != nil || rest < 0 {
+		return config.TimerConfig{}, fmt.Errorf("Break: %s", config.DurationProblem(err))
+	}
+	cfg := config.TimerConfig{
+		Name:      body.Name,
+		NotifText: body.Name,
+		Phases:    []config.TimerPhase{{WorkDuration: work, BreakDuration: rest}},
+		MaxCycles: body.Cycles,
+	}
+	if body.Cycles == 0 {
+		cfg.MaxCycles = -1
+	}
+	return cfg, config.CheckLimits(cfg)
+}
+
+// redraw asks for the display to catch up with a change made over HTTP
+func (tm *TimerManager) redraw() {
+	select {
+	case tm.DisplayChan <- true:
+	default:
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	writeJSON(w, code, struct{ Error string }{message})
+}
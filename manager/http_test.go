@@ -0,0 +1,129 @@
+package manager
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"multi-timer/config"
+)
+
+// request sends a request to the API and decodes the answer into v
+func request(t *testing.T, server *httptest.Server, method, path, body string, v any) int {
+	t.Helper()
+	req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("%s %s: %v", method, path, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+// savedNames are the names of the configs in the saved file
+func savedNames(t *testing.T, tm *TimerManager) []string {
+	t.Helper()
+	configs, err := config.LoadTimerConfigs(tm.ConfigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, config := range configs {
+		names = append(names, config.Name)
+	}
+	return names
+}
+
+func TestAPIListAddDelete(t *testing.T) {
+	tm, _ := newTestManager(t)
+	addTimers(tm,
+		pomodoro("Tea", 25*time.Minute, 5*time.Minute, 4),
+		pomodoro("Read", 30*time.Minute, 5*time.Minute, 4),
+	)
+	advance(tm, time.Minute)
+	server := httptest.NewServer(tm.apiHandler())
+	defer server.Close()
+
+	var list []timerStatus
+	if code := request(t, server, http.MethodGet, "/timers", "", &list); code != http.StatusOK {
+		t.Fatalf("GET /timers: %d", code)
+	}
+	want := []timerStatus{
+		{ID: 1, Name: "Tea", Segment: "Work", Phase: 1, Cycle: 1, Remaining: 24 * 60},
+		{ID: 2, Name: "Read", Segment: "Work", Phase: 1, Cycle: 1, Remaining: 29 * 60},
+	}
+	if !slices.Equal(list, want) {
+		t.Errorf("list = %+v, want %+v", list, want)
+	}
+
+	var added timerStatus
+	code := request(t, server, http.MethodPost, "/timers", `{"Name": "Walk", "Work": "10m", "Break": "2m", "Cycles": 2}`, &added)
+	if code != http.StatusCreated {
+		t.Fatalf("POST /timers: %d", code)
+	}
+	if added.ID != 3 || added.Name != "Walk" || added.Remaining != 10*60 {
+		t.Errorf("added %+v, want Walk as timer 3 with 10m left", added)
+	}
+	if got, want := savedNames(t, tm), []string{"Tea", "Read", "Walk"}; !slices.Equal(got, want) {
+		t.Errorf("saved %v after adding, want %v", got, want)
+	}
+	var problem struct{ Error string }
+	if code := request(t, server, http.MethodPost, "/timers", `{"Name": "Nap", "Work": "-5m"}`, &problem); code != http.StatusBadRequest || problem.Error == "" {
+		t.Errorf("bad work length: %d %q, want 400 with the problem", code, problem.Error)
+	}
+
+	var deleted timerStatus
+	if code := request(t, server, http.MethodDelete, "/timers/1", "", &deleted); code != http.StatusOK || deleted.Name != "Tea" {
+		t.Fatalf("DELETE /timers/1: %d %+v, want Tea", code, deleted)
+	}
+	if got, want := activeNames(tm), []string{"Read", "Walk"}; !slices.Equal(got, want) {
+		t.Errorf("timers = %v after deleting Tea, want %v", got, want)
+	}
+	if got, want := savedNames(t, tm), []string{"Read", "Walk"}; !slices.Equal(got, want) {
+		t.Errorf("saved %v after deleting, want %v", got, want)
+	}
+	if code := request(t, server, http.MethodDelete, "/timers/3", "", nil); code != http.StatusNotFound {
+		t.Errorf("DELETE of a timer that isn't there: %d, want 404", code)
+	}
+}
+
+// Pausing twice leaves the timer paused, where a toggle would resume it
+func TestAPIPauseIsIdempotent(t *testing.T) {
+	tm, _ := newTestManager(t)
+	addTimers(tm, pomodoro("Tea", 25*time.Minute, 5*time.Minute, 4))
+	server := httptest.NewServer(tm.apiHandler())
+	defer server.Close()
+
+	for i, step := range []struct {
+		action string
+		paused bool
+	}{
+		{"pause", true},
+		{"pause", true},
+		{"resume", false},
+		{"resume", false},
+	} {
+		var status timerStatus
+		if code := request(t, server, http.MethodPost, "/timers/1/"+step.action, "", &status); code != http.StatusOK {
+			t.Fatalf("%d: %s: %d", i+1, step.action, code)
+		}
+		if status.Paused != step.paused {
+			t.Errorf("%d: paused is %t after %s, want %t", i+1, status.Paused, step.action, step.paused)
+		}
+	}
+	if code := request(t, server, http.MethodGet, "/timers/1/pause", "", nil); code != http.StatusMethodNotAllowed {
+		t.Errorf("GET of pause: %d, want 405", code)
+	}
+}
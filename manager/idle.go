@@ -0,0 +1,29 @@
+package manager
+
+import (
+	"fmt"
+	"time"
+)
+
+// idleExpired reports whether the app has gone prefs.IdleQuit without input
+// and without timers, so it should quit. Must be called with tm.mu held.
+func (tm *TimerManager) idleExpired(now time.Time) bool {
+	return tm.Prefs.IdleQuit > 0 && len(tm.ActiveTimers) == 0 && now.Sub(tm.lastInput) >= tm.Prefs.IdleQuit
+}
+
+// NoteInput records a command or request, which puts off the idle quit.
+// Must be called with tm.mu held.
+func (tm *TimerManager) NoteInput() {
+	tm.lastInput = tm.Now()
+}
+
+// Quit saves the timers and their progress on the way out
+func (tm *TimerManager) Quit() {
+	if err := tm.SaveConfigs(); err != nil {
+		fmt.Println("Error saving timer configurations:", err)
+	}
+	if err := tm.writeState(); err != nil {
+		fmt.Println("Error saving timer state:", err)
+	}
+	tm.FlushHistory()
+}
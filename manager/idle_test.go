@@ -0,0 +1,66 @@
+package manager
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestIdleAutoQuit(t *testing.T) {
+	tm, _ := newTestManager(t)
+	tm.Prefs.IdleQuit = 10 * time.Minute
+	tm.lastInput = tm.Now()
+	addTimers(tm, pomodoro("Tea", 2*time.Minute, time.Minute, 1))
+
+	exits := make(chan time.Time, 1)
+	tm.Exit = func(int) { exits <- tm.Now() }
+	ticks := make(chan time.Time)
+	defer close(ticks)
+	tm.StartUpdateLoop(ticks)
+
+	// Ticks a second apart from the loop's start
+	next := time.Now()
+	var quitAt time.Time
+	for quitAt.IsZero() {
+		next = next.Add(time.Second)
+		select {
+		case ticks <- next:
+		case quitAt = <-exits:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out ticking")
+		}
+	}
+
+	// Tea was done at 09:03, but the idle time counts from the last input
+	if want := time.Date(2024, 3, 4, 9, 10, 0, 0, time.UTC); quitAt.Sub(want).Abs() > time.Second {
+		t.Errorf("quit at %s, want 10 minutes after the last input", quitAt.Format("15:04:05"))
+	}
+	for _, path := range []string{tm.ConfigPath, tm.StatePath} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("quitting didn't save %s: %v", path, err)
+		}
+	}
+}
+
+func TestIdleExpired(t *testing.T) {
+	tm, _ := newTestManager(t)
+	tm.lastInput = tm.Now()
+	tm.clock = time.Hour
+	if tm.idleExpired(tm.Now()) {
+		t.Error("idle quit without the option set")
+	}
+	tm.Prefs.IdleQuit = 10 * time.Minute
+	if !tm.idleExpired(tm.Now()) {
+		t.Error("no idle quit an hour after the last input")
+	}
+	addTimers(tm, pomodoro("Tea", 2*time.Minute, time.Minute, 1))
+	if tm.idleExpired(tm.Now()) {
+		t.Error("idle quit with a timer running")
+	}
+	tm.clock += time.Hour
+	tm.lastInput = tm.Now().Add(-9 * time.Minute)
+	tm.ActiveTimers = nil
+	if tm.idleExpired(tm.Now()) {
+		t.Error("idle quit 9 minutes after the last input")
+	}
+}
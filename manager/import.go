@@ -0,0 +1,84 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"multi-timer/config"
+)
+
+// ImportExternal maps the JSON export of other Pomodoro apps to configs. The
+// supported schema is a list of timers:
+//
+//	[{"title": "Study", "workMinutes": 25, "breakMinutes": 5, "rounds": 4}]
+//
+// A missing or zero rounds runs the timer until it's deleted. Any other field
+// is returned in unmapped so the user knows what was dropped.
+func ImportExternal(data []byte) (configs []config.TimerConfig, unmapped []string, err error) {
+	var entries []map[string]any
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, nil, err
+	}
+
+	ignored := make(map[string]bool)
+	for i, entry := range entries {
+		cfg := config.TimerConfig{
+			Name:      fmt.Sprintf("Imported %d", i+1),
+			MaxCycles: -1,
+		}
+		var work, rest float64
+		for key, value := range entry {
+			switch key {
+			case "title":
+				title, ok := value.(string)
+				if !ok {
+					return nil, nil, fmt.Errorf("entry %d: title must be a string", i+1)
+				}
+				if title != "" {
+					cfg.Name = title
+				}
+			case "workMinutes", "breakMinutes", "rounds":
+				n, ok := value.(float64)
+				if !ok || n < 0 {
+					return nil, nil, fmt.Errorf("entry %d: %s must be a non-negative number", i+1, key)
+				}
+				switch key {
+				case "workMinutes":
+					work = n
+				case "breakMinutes":
+					rest = n
+				case "rounds":
+					if int(n) > 0 {
+						cfg.MaxCycles = int(n)
+					}
+				}
+			default:
+				ignored[key] = true
+			}
+		}
+		if work <= 0 {
+			return nil, nil, fmt.Errorf("entry %d: workMinutes is required", i+1)
+		}
+		// Anything this large would overflow the conversion below
+		if limit := config.MaxDuration.Minutes(); work > limit || rest > limit {
+			return nil, nil, fmt.Errorf("entry %d: minutes can be at most %g", i+1, limit)
+		}
+		if cfg.MaxCycles > config.MaxCycleCount {
+			return nil, nil, fmt.Errorf("entry %d: rounds can be at most %d", i+1, config.MaxCycleCount)
+		}
+		cfg.NotifText = cfg.Name
+		cfg.Phases = []config.TimerPhase{{
+			WorkDuration:  time.Duration(work * float64(time.Minute)),
+			BreakDuration: time.Duration(rest * float64(time.Minute)),
+		}}
+		configs = append(configs, cfg)
+	}
+
+	for key := range ignored {
+		unmapped = append(unmapped, key)
+	}
+	sort.Strings(unmapped)
+	return configs, unmapped, nil
+}
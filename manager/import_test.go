@@ -0,0 +1,66 @@
+package manager
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"multi-timer/config"
+)
+
+func TestImportExternal(t *testing.T) {
+	data := `[
+		{"title": "Study", "workMinutes": 25, "breakMinutes": 5, "rounds": 4, "color": "red"},
+		{"workMinutes": 50, "breakMinutes": 10, "id": 7},
+		{"title": "Sprint", "workMinutes": 1.5}
+	]`
+	configs, unmapped, err := ImportExternal([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []config.TimerConfig{
+		{
+			Name:      "Study",
+			NotifText: "Study",
+			Phases:    []config.TimerPhase{{WorkDuration: 25 * time.Minute, BreakDuration: 5 * time.Minute}},
+			MaxCycles: 4,
+		},
+		{
+			Name:      "Imported 2",
+			NotifText: "Imported 2",
+			Phases:    []config.TimerPhase{{WorkDuration: 50 * time.Minute, BreakDuration: 10 * time.Minute}},
+			MaxCycles: -1,
+		},
+		{
+			Name:      "Sprint",
+			NotifText: "Sprint",
+			Phases:    []config.TimerPhase{{WorkDuration: 90 * time.Second}},
+			MaxCycles: -1,
+		},
+	}
+	if !reflect.DeepEqual(configs, want) {
+		t.Errorf("configs:\n%+v\nwant:\n%+v", configs, want)
+	}
+	if want := []string{"color", "id"}; !slices.Equal(unmapped, want) {
+		t.Errorf("unmapped = %v, want %v", unmapped, want)
+	}
+}
+
+func TestImportExternalErrors(t *testing.T) {
+	for data, want := range map[string]string{
+		`{"title": "Study"}`:                         "cannot unmarshal",
+		`[{"title": "Study"}]`:                       "entry 1: workMinutes is required",
+		`[{"workMinutes": 25}, {"title": 3}]`:        "entry 2: title must be a string",
+		`[{"workMinutes": -5}]`:                      "entry 1: workMinutes must be a non-negative number",
+		`[{"workMinutes": "25"}]`:                    "entry 1: workMinutes must be a non-negative number",
+		`[{"workMinutes": 1e300}]`:                   "entry 1: minutes can be at most",
+		`[{"workMinutes": 25, "rounds": 100000000}]`: "entry 1: rounds can be at most",
+	} {
+		_, _, err := ImportExternal([]byte(data))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ImportExternal(%s) = %v, want an error with %q", data, err, want)
+		}
+	}
+}
@@ -0,0 +1,335 @@
+// Package manager runs the timers together. TimerManager ticks them, sends
+// their notifications and sounds, and keeps the history, state and archive,
+// and serves them over the daemon socket, HTTP and metrics.
+package manager
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"multi-timer/config"
+	"multi-timer/timer"
+)
+
+// TimerManager runs the active timers: it ticks them, sends their
+// notifications and keeps the configs, history and state files. Its fields
+// are guarded by mu, see Lock.
+type TimerManager struct {
+	ActiveTimers []*timer.Timer
+	Configs      []config.TimerConfig
+	completed    map[string]int // timers that ran to completion, by name
+	cyclesDone   int            // cycles completed by any timer since launch
+	DisplayChan  chan bool
+	Out          io.Writer
+	Notifier     Notifier
+	Now          func() time.Time
+	lastSchedule time.Time // when the scheduler last looked for due timers
+	lastRefresh  time.Time // when the update loop last asked for a redraw
+	Override     *TextOverride
+	Stepping     bool // no ticker, the step command advances the timers
+	ConfigPath   string
+	Prefs        config.Preferences
+	HistoryPath  string
+	ArchivePath  string
+	StatePath    string
+	reviews      []OutcomeReview // completed phases waiting for an outcome answer
+	Profile      string
+	watcher      *fsnotify.Watcher
+	Speed        float64 // how many timer seconds pass per real second
+	Filter       string  // tag the display is limited to, "" shows every timer
+	mu           sync.Mutex
+
+	// See queue.go
+	clock     time.Duration // timer time since launch
+	pending   time.Duration // timer time the timers haven't caught up on
+	wakeups   wakeQueue
+	nextAlert time.Time // soonest escalation, zero for none
+
+	notificationLog []SentNotification // oldest first, see logNotification
+	paneOffset      int                // how far the pane is scrolled back
+	lastError       string             // of background work, see ReportError
+
+	launched time.Time // start of the session, for the report
+
+	lastInput time.Time      // when a command was last entered, see idleExpired
+	Exit      func(code int) // how the app exits, os.Exit outside of tests
+
+	historyWrites      chan historyWrite // see writeHistoryLoop
+	completionsChanged bool              // the configs need saving, see saveCompletions
+
+	pausedByAll []*timer.Timer // what pa paused, for the next pa to resume
+}
+
+// NewTimerManager is a manager without timers that saves to the data
+// directory and notifies on the desktop
+func NewTimerManager() *TimerManager {
+	tm := &TimerManager{
+		ActiveTimers: make([]*timer.Timer, 0),
+		Configs:      make([]config.TimerConfig, 0),
+		completed:    make(map[string]int),
+		DisplayChan:  make(chan bool, 1),
+		Out:          os.Stdout,
+		Notifier:     newNotifier(),
+		Now:          time.Now,
+		ConfigPath:   config.DataFile(config.ConfigFile),
+		HistoryPath:  config.DataFile(historyFile),
+		ArchivePath:  config.DataFile(ArchiveFile),
+		StatePath:    config.DataFile(StateFile),
+		Speed:        1,
+		launched:     time.Now(),
+		lastInput:    time.Now(),
+		Exit:         os.Exit,
+
+		historyWrites: make(chan historyWrite, 64),
+	}
+	go tm.writeHistoryLoop()
+	return tm
+}
+
+// Launched is when the session started
+func (tm *TimerManager) Launched() time.Time {
+	return tm.launched
+}
+
+// SaveConfigs saves the configs from a copy taken under the lock, as the
+// file is written without holding it and the configs keep changing. Must be
+// called without tm.mu held.
+func (tm *TimerManager) SaveConfigs() error {
+	tm.mu.Lock()
+	path, configs := tm.ConfigPath, slices.Clone(tm.Configs)
+	tm.mu.Unlock()
+	return config.SaveTimerConfigs(path, configs)
+}
+
+// BreakAll sends every running timer that is in work mode on break and
+// returns the names of the timers it switched
+func (tm *TimerManager) BreakAll() []string {
+	tm.Lock()
+	defer tm.Unlock()
+
+	var names []string
+	for _, t := range tm.ActiveTimers {
+		if t.IsPaused || !t.State.IsWork || t.CountUp || t.State.Stage != timer.StageCycles || t.Protected() {
+			continue
+		}
+		t.EndSegment()
+		t.StartBreak()
+		tm.handleEvents(t)
+		names = append(names, t.State.Name)
+	}
+	return names
+}
+
+// refreshDue reports whether the display should be redrawn at the tick at now,
+// keeping redraws at least prefs.RefreshInterval apart. Must be called with tm.mu held.
+func (tm *TimerManager) refreshDue(now time.Time) bool {
+	// Ticks jitter a little, don't let that skip one
+	if now.Sub(tm.lastRefresh) < tm.Prefs.RefreshInterval-timer.TickInterval/2 {
+		return false
+	}
+	tm.lastRefresh = now
+	return true
+}
+
+// SnoozeAll pushes the end of the current segment of every running timer
+// back by d and returns the names of the timers it snoozed
+func (tm *TimerManager) SnoozeAll(d time.Duration) []string {
+	tm.Lock()
+	defer tm.Unlock()
+
+	var names []string
+	for _, timer := range tm.ActiveTimers {
+		if timer.IsPaused || timer.IsStopwatch() || timer.OnOpenBreak() || timer.Protected() {
+			continue
+		}
+		if timer.CountUp {
+			// The end is reached when the time spent gets to the segment length
+			timer.AddTime(-d)
+		} else {
+			timer.AddTime(d)
+		}
+		names = append(names, timer.State.Name)
+	}
+	return names
+}
+
+// Extend lengthens the current segment of timer num by d, or with a negative
+// d shortens it. A segment shortened past its end runs out on the next tick.
+func (tm *TimerManager) Extend(num int, d time.Duration) error {
+	tm.Lock()
+	defer tm.Unlock()
+
+	if num < 1 || num > len(tm.ActiveTimers) {
+		return fmt.Errorf("no timer %d", num)
+	}
+	timer := tm.ActiveTimers[num-1]
+	switch {
+	case timer.IsStopwatch():
+		return fmt.Errorf("a stopwatch has no end to move")
+	case timer.OnOpenBreak():
+		return fmt.Errorf("the break waits for resume, it has no end to move")
+	case timer.Protected():
+		return fmt.Errorf("deep work cycle, its time can't be changed")
+	}
+	if timer.CountUp {
+		timer.AddTime(-d)
+	} else {
+		timer.AddTime(d)
+	}
+	return nil
+}
+
+// How many ticks the step command runs at a time before letting the display
+// and the other frontends at the timers
+const stepBatch = 60
+
+// Step advances the timers by count ticks for the step command, in place of
+// the update loop. Long steps release the lock between batches of ticks.
+func (tm *TimerManager) Step(count int) {
+	for count > 0 {
+		n := min(count, stepBatch)
+		count -= n
+		tm.Lock()
+		for i := 0; i < n; i++ {
+			tm.tick(timer.TickInterval)
+		}
+		tm.Unlock()
+		tm.saveCompletions()
+	}
+}
+
+// StartUpdateLoop advances the timers on every tick from ticks
+func (tm *TimerManager) StartUpdateLoop(ticks <-chan time.Time) {
+	go func() {
+		// The timers get the time since the loop started rather than the sum
+		// of the gaps between ticks, so late ticks don't add up to drift. It
+		// is read off the wall clock, which goes on while the computer sleeps
+		// where the monotonic clock stops. When the wall clock falls behind
+		// the monotonic one, as when it's set back, the loop starts over from
+		// the monotonic reading so the time since the last tick still counts.
+		start := time.Now()
+		last := start
+		var ran time.Duration // the part of the time since start the timers had
+		for now := range ticks {
+			since := now.Round(0).Sub(start.Round(0))
+			if steady := ran + max(now.Sub(last), 0); since < steady {
+				since = steady
+				start = now.Add(-since)
+			}
+			last = now
+			delta := since - ran
+			ran = since
+			tm.mu.Lock()
+			// Catch up on a long gap one tick at a time so every segment
+			// that ran out meanwhile ends in turn
+			needsDisplay := false
+			for ; delta > timer.TickInterval; delta -= timer.TickInterval {
+				needsDisplay = tm.tick(timer.TickInterval) || needsDisplay
+			}
+			needsDisplay = tm.tick(delta) || needsDisplay
+			needsDisplay = needsDisplay && tm.refreshDue(now)
+			idle := tm.idleExpired(tm.Now())
+			tm.mu.Unlock()
+			tm.saveCompletions()
+
+			if idle {
+				fmt.Println("\nNothing to do for a while, quitting.")
+				tm.Quit()
+				tm.Exit(0)
+				return
+			}
+
+			if needsDisplay {
+				// Non-blocking send to display channel
+				select {
+				case tm.DisplayChan <- true:
+				default:
+				}
+			}
+		}
+	}()
+}
+
+// tick advances the timers by the time since the last tick, scaled by the
+// speed, and reports whether anything changed. Only the timers whose wakeup
+// is due are updated, the others save the time up, see queue.go. Must be
+// called with tm.mu held.
+func (tm *TimerManager) tick(delta time.Duration) bool {
+	elapsed := time.Duration(float64(delta) * tm.Speed)
+	tm.clock += elapsed
+	tm.pending += elapsed
+	needsDisplay := len(tm.ActiveTimers) > 0
+	due := tm.dueTimers()
+	if len(due) == 0 && !tm.alertDue() {
+		if tm.runScheduler(tm.Now()) {
+			tm.syncWakeups()
+			needsDisplay = true
+		}
+		return needsDisplay
+	}
+
+	var completed []*timer.Timer
+	alerts := tm.alertDue()
+	// Every due timer catches up before the events of any are handled, so
+	// the ones those pause have had their time and can't complete unseen
+	done := make([]bool, len(due))
+	for i, t := range due {
+		if t.Started().IsZero() {
+			// First tick of a new timer
+			t.SetStarted(tm.Now())
+			t.Clock = tm.Now
+			tm.playSound(t.StartSound)
+			if t.State.Stage == timer.StageCycles && t.State.IsWork && !t.IsStopwatch() {
+				tm.callWebhooks(t, WebhookPayload{Event: "work-start", Timer: t.State.Name, Phase: 1, Cycle: 1, Time: t.Started()})
+			}
+		}
+		cycles := t.Stats.CompletedCycles
+		done[i] = tm.catchUp(t)
+		tm.cyclesDone += t.Stats.CompletedCycles - cycles
+	}
+	for i, t := range due {
+		tm.handleEvents(t)
+		if done[i] {
+			completed = append(completed, t)
+		} else {
+			tm.schedule(t)
+		}
+	}
+	if alerts {
+		for _, timer := range tm.ActiveTimers {
+			tm.escalateAlert(timer)
+		}
+	}
+	if len(completed) > 0 {
+		tm.ActiveTimers = slices.DeleteFunc(tm.ActiveTimers, func(t *timer.Timer) bool { return slices.Contains(completed, t) })
+		// The timers started next mustn't get the time the others saved up
+		tm.settle()
+		for _, timer := range completed {
+			tm.finish(timer)
+		}
+	}
+	// Timers that were added need queueing. The ones the due timers paused
+	// keep their wakeup, which finds them paused and drops them.
+	if tm.runScheduler(tm.Now()) || len(completed) > 0 || alerts {
+		tm.syncWakeups()
+	}
+	return true
+}
+
+// finish handles a timer that completed and was removed from the active
+// timers. Must be called with tm.mu held.
+func (tm *TimerManager) finish(t *timer.Timer) {
+	tm.completed[t.State.Name]++
+	if err := tm.archive(t); err != nil {
+		tm.ReportError("Error writing archive", err)
+	}
+	tm.playSound(t.CompleteSound)
+	tm.callWebhooks(t, WebhookPayload{Event: "completed", Timer: t.State.Name, Time: tm.Now()})
+	tm.startNext(t)
+}
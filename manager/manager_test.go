@@ -0,0 +1,515 @@
+package manager
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"multi-timer/config"
+	"multi-timer/timer"
+)
+
+// recordingNotifier keeps the notifications and sounds instead of showing
+// and playing them
+type recordingNotifier struct {
+	notifications []string
+	levels        []Urgency // of each notification
+	sounds        []string
+}
+
+func (n *recordingNotifier) Notify(title, message string, level Urgency) error {
+	n.notifications = append(n.notifications, title+": "+message)
+	n.levels = append(n.levels, level)
+	return nil
+}
+
+func (n *recordingNotifier) Sound(name string) error {
+	n.sounds = append(n.sounds, name)
+	return nil
+}
+
+// newTestManager is a manager that saves to a directory of its own, prints
+// nothing and runs on a clock that only moves with the ticks. The history
+// writer is stopped once the test is over.
+func newTestManager(tb testing.TB) (*TimerManager, *recordingNotifier) {
+	tb.Helper()
+	saved := config.DataDir
+	config.DataDir = tb.TempDir()
+	tm := NewTimerManager()
+	tb.Cleanup(func() {
+		tm.FlushHistory()
+		close(tm.historyWrites)
+		config.DataDir = saved
+	})
+	notifier := &recordingNotifier{}
+	tm.Notifier = notifier
+	tm.Out = io.Discard
+	start := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	tm.Now = func() time.Time { return start.Add(tm.clock) }
+	tm.Exit = func(int) {}
+	return tm, notifier
+}
+
+// addTimers starts timers for the configs the way the a command does
+func addTimers(tm *TimerManager, configs ...config.TimerConfig) []*timer.Timer {
+	var timers []*timer.Timer
+	tm.Lock()
+	for _, config := range configs {
+		t := timer.TimerFromConfig(config)
+		tm.Configs = append(tm.Configs, config)
+		tm.ActiveTimers = append(tm.ActiveTimers, t)
+		timers = append(timers, t)
+	}
+	tm.Unlock()
+	return timers
+}
+
+// advance runs the update loop for d in ticks of a second
+func advance(tm *TimerManager, d time.Duration) {
+	tm.Lock()
+	for ; d > 0; d -= timer.TickInterval {
+		tm.tick(min(d, timer.TickInterval))
+	}
+	tm.Unlock()
+}
+
+// pomodoro is a config of cycles of work and break, -1 for unlimited
+func pomodoro(name string, work, rest time.Duration, cycles int) config.TimerConfig {
+	return config.TimerConfig{
+		Name:      name,
+		Phases:    []config.TimerPhase{{WorkDuration: work, BreakDuration: rest}},
+		MaxCycles: cycles,
+	}
+}
+
+func TestBreakAll(t *testing.T) {
+	tm, notifier := newTestManager(t)
+	timers := addTimers(tm,
+		pomodoro("Tea", 25*time.Minute, 5*time.Minute, 4),
+		pomodoro("Read", 30*time.Minute, 10*time.Minute, 4),
+		pomodoro("Paused", 30*time.Minute, 10*time.Minute, 4),
+		pomodoro("Resting", 2*time.Minute, 10*time.Minute, 4),
+	)
+	tm.Lock()
+	timers[2].IsPaused = true
+	tm.Unlock()
+	advance(tm, 3*time.Minute) // Resting is on its break
+	notifier.notifications = nil
+
+	names := tm.BreakAll()
+	if want := []string{"Tea", "Read"}; !slices.Equal(names, want) {
+		t.Errorf("break-all sent %v on break, want %v", names, want)
+	}
+	tm.Lock()
+	defer tm.Unlock()
+	for i, want := range []struct {
+		isWork bool
+		left   time.Duration
+	}{
+		{false, 5 * time.Minute},
+		{false, 10 * time.Minute},
+		{true, 30 * time.Minute},
+		{false, 9 * time.Minute},
+	} {
+		timer := timers[i]
+		if timer.State.IsWork != want.isWork || timer.State.CurrentTime != want.left {
+			t.Errorf("%s: work %t with %v left, want work %t with %v left",
+				timer.State.Name, timer.State.IsWork, timer.State.CurrentTime, want.isWork, want.left)
+		}
+	}
+	// The command sends one notification for all of them
+	if len(notifier.notifications) != 0 {
+		t.Errorf("timers notified on their own: %v", notifier.notifications)
+	}
+}
+
+func TestSpeedScalesTicks(t *testing.T) {
+	tm, _ := newTestManager(t)
+	timers := addTimers(tm, pomodoro("Tea", 25*time.Minute, 5*time.Minute, 4))
+	advance(tm, timer.TickInterval) // start it
+	tm.Lock()
+	tm.Speed = 10
+	tm.Unlock()
+
+	advance(tm, timer.TickInterval)
+	tm.Lock()
+	defer tm.Unlock()
+	if got, want := timers[0].State.CurrentTime, 25*time.Minute-time.Second-10*time.Second; got != want {
+		t.Errorf("%v left after a tick at 10x, want %v", got, want)
+	}
+}
+
+// The update loop gives the timers the wall clock time since it started,
+// however the ticks are spaced, and carries on when the clock is set back
+func TestUpdateLoopKeepsTime(t *testing.T) {
+	tm, _ := newTestManager(t)
+	timers := addTimers(tm, pomodoro("Tea", 25*time.Minute, 5*time.Minute, 4))
+	ticks := make(chan time.Time)
+	defer close(ticks)
+	base := time.Now().Round(0) // wall clock readings, like ticks across a sleep
+	tm.StartUpdateLoop(ticks)
+
+	var offsets []time.Duration
+	for at := time.Duration(0); at < time.Minute; {
+		at += 700*time.Millisecond + at%3*300*time.Millisecond // late and early ticks
+		offsets = append(offsets, at)
+	}
+	offsets = append(offsets,
+		10*time.Minute,           // back from sleep
+		10*time.Minute-time.Hour, // the clock was set back
+		10*time.Minute-time.Hour+time.Second,
+		10*time.Minute-time.Hour+time.Second, // waits for the one before
+	)
+	for _, offset := range offsets {
+		ticks <- base.Add(offset)
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+	want := 25*time.Minute - 10*time.Minute - time.Second
+	if got := timers[0].State.CurrentTime; (got - want).Abs() > 100*time.Millisecond {
+		t.Errorf("%v left, want %v", got, want)
+	}
+}
+
+func TestOpenEndedBreak(t *testing.T) {
+	tm, _ := newTestManager(t)
+	timers := addTimers(tm, pomodoro("Call", time.Minute, config.UntilResumed, 2))
+	tr := timers[0]
+	advance(tm, time.Minute)
+	advance(tm, 10*time.Minute)
+
+	tm.Lock()
+	if !tr.OnOpenBreak() || tr.State.Cycles != 1 {
+		t.Fatalf("work %t in cycle %d, want to still be on the first break", tr.State.IsWork, tr.State.Cycles)
+	}
+	// The break counts up the time spent on it
+	if got := tr.State.CurrentTime; got != 10*time.Minute {
+		t.Errorf("break shows %v, want 10m", got)
+	}
+	if got := tr.String(); !strings.Contains(got, "Break (until resumed): +10:00") {
+		t.Errorf("display %q doesn't show the open break", got)
+	}
+	tm.Unlock()
+
+	tm.Lock()
+	tm.EndBreakEarly(1)
+	tm.Unlock()
+	advance(tm, timer.TickInterval)
+
+	tm.Lock()
+	defer tm.Unlock()
+	if !tr.State.IsWork || tr.State.Cycles != 2 || tr.State.CurrentTime != time.Minute-timer.TickInterval {
+		t.Errorf("after resume: work %t, cycle %d, %v left, want the second cycle's work",
+			tr.State.IsWork, tr.State.Cycles, tr.State.CurrentTime)
+	}
+}
+
+func TestWarmupAndCooldown(t *testing.T) {
+	tm, _ := newTestManager(t)
+	config := pomodoro("Run", 2*time.Minute, time.Minute, 2)
+	config.WarmupDuration = time.Minute
+	config.CooldownDuration = 2 * time.Minute
+	timers := addTimers(tm, config)
+
+	// The label of every segment the timer goes through, with the cycle for
+	// the work and breaks
+	type seen struct {
+		label string
+		cycle int
+	}
+	var segments []seen
+	for range 20 * time.Minute / time.Second {
+		tm.Lock()
+		if len(tm.ActiveTimers) > 0 {
+			label, cycle := timers[0].SegmentLabel(), timers[0].State.Cycles
+			if timers[0].State.Stage != timer.StageCycles {
+				cycle = 0
+			}
+			if len(segments) == 0 || segments[len(segments)-1] != (seen{label, cycle}) {
+				segments = append(segments, seen{label, cycle})
+			}
+		}
+		tm.tick(timer.TickInterval)
+		tm.Unlock()
+	}
+
+	want := []seen{
+		{"Warmup", 0},
+		{"Work", 1}, {"Break", 1},
+		{"Work", 2}, {"Break", 2},
+		{"Cooldown", 0},
+	}
+	if !slices.Equal(segments, want) {
+		t.Errorf("segments = %v, want %v", segments, want)
+	}
+	tm.Lock()
+	defer tm.Unlock()
+	if len(tm.ActiveTimers) != 0 {
+		t.Error("the timer didn't complete after its cooldown")
+	}
+	if got := timers[0].Stats.CompletedCycles; got != 2 {
+		t.Errorf("%d cycles completed, want 2 without the warmup and cooldown", got)
+	}
+}
+
+func TestRefreshInterval(t *testing.T) {
+	tm, _ := newTestManager(t)
+	timers := addTimers(tm, pomodoro("Tea", 25*time.Minute, 5*time.Minute, 4))
+	tm.Prefs.RefreshInterval = 5 * time.Second
+
+	// tick runs the way the update loop does and reports whether it redraws
+	tick := func(jitter time.Duration) bool {
+		tm.Lock()
+		defer tm.Unlock()
+		return tm.tick(timer.TickInterval) && tm.refreshDue(tm.Now().Add(-jitter))
+	}
+	renders := 0
+	for i := range 30 {
+		// Ticks that come a little early still count as a second
+		if tick(time.Duration(i%3) * 50 * time.Millisecond) {
+			renders++
+		}
+		tm.Lock()
+		got, want := timers[0].State.CurrentTime, 25*time.Minute-time.Duration(i+1)*time.Second
+		tm.Unlock()
+		if got != want {
+			t.Fatalf("%v left after %d ticks, want %v", got, i+1, want)
+		}
+	}
+	if renders != 6 {
+		t.Errorf("%d renders in 30 ticks, want one every 5", renders)
+	}
+
+	tm.Prefs.RefreshInterval = 0
+	renders = 0
+	for range 10 {
+		if tick(0) {
+			renders++
+		}
+	}
+	if renders != 10 {
+		t.Errorf("%d renders in 10 ticks without an interval, want every tick", renders)
+	}
+}
+
+func TestStep(t *testing.T) {
+	tm, _ := newTestManager(t)
+	tm.Stepping = true
+	timers := addTimers(tm, pomodoro("Tea", 3*time.Second, 2*time.Second, 2))
+
+	type state struct {
+		isWork bool
+		left   time.Duration
+		cycle  int
+	}
+	for i, want := range []state{
+		{true, 2 * time.Second, 1},
+		{true, time.Second, 1},
+		{false, 2 * time.Second, 1},
+		{false, time.Second, 1},
+		{true, 3 * time.Second, 2},
+		{true, 2 * time.Second, 2},
+	} {
+		tm.Step(1)
+		tm.Lock()
+		got := state{timers[0].State.IsWork, timers[0].State.CurrentTime, timers[0].State.Cycles}
+		tm.Unlock()
+		if got != want {
+			t.Errorf("after step %d: %+v, want %+v", i+1, got, want)
+		}
+	}
+
+	// Several at once run the rest of the timer
+	tm.Step(4)
+	if got := activeNames(tm); len(got) != 0 {
+		t.Errorf("timers = %v after stepping through the last cycle, want none", got)
+	}
+}
+
+// A step longer than a batch still runs every tick
+func TestLongStep(t *testing.T) {
+	tm, _ := newTestManager(t)
+	tm.Stepping = true
+	timers := addTimers(tm, pomodoro("Tea", 25*time.Minute, 5*time.Minute, 4))
+	tm.Step(2*stepBatch + 30)
+	tm.Lock()
+	defer tm.Unlock()
+	if got, want := timers[0].State.CurrentTime, 25*time.Minute-(2*stepBatch+30)*timer.TickInterval; got != want {
+		t.Errorf("%v left, want %v", got, want)
+	}
+}
+
+func TestSetRemaining(t *testing.T) {
+	tm, _ := newTestManager(t)
+	timers := addTimers(tm, pomodoro("Tea", 25*time.Minute, 5*time.Minute, 4))
+	advance(tm, 3*time.Minute)
+
+	tm.Lock()
+	timers[0].SetRemaining(10*time.Minute, false)
+	if got := timers[0].String(); got != "Tea - Work: 10:00 (Cycle 1/4) Phase 1/1" {
+		t.Errorf("display = %q, want 10:00 left", got)
+	}
+	tm.Unlock()
+	advance(tm, 90*time.Second)
+	tm.Lock()
+	if got := timers[0].State.CurrentTime; got != 8*time.Minute+30*time.Second {
+		t.Errorf("%v left 90s after setting 10m, want 8m30s", got)
+	}
+
+	// No more than the segment unless overtime is allowed
+	timers[0].SetRemaining(40*time.Minute, false)
+	if got := timers[0].State.CurrentTime; got != 25*time.Minute {
+		t.Errorf("%v left after setting 40m, want the 25m of the segment", got)
+	}
+	timers[0].SetRemaining(40*time.Minute, true)
+	if got := timers[0].String(); got != "Tea - Work: 40:00 (Cycle 1/4) Phase 1/1" {
+		t.Errorf("display = %q, want 40:00 of overtime", got)
+	}
+	if timers[0].SetRemaining(-time.Minute, false) {
+		t.Error("set a negative time left")
+	}
+	tm.Unlock()
+}
+
+func TestSnoozeAll(t *testing.T) {
+	tm, _ := newTestManager(t)
+	timers := addTimers(tm,
+		pomodoro("Tea", 25*time.Minute, 5*time.Minute, 4),
+		pomodoro("Read", 10*time.Minute, 10*time.Minute, 4),
+		pomodoro("Paused", 30*time.Minute, 5*time.Minute, 4),
+		pomodoro("Call", 30*time.Minute, 5*time.Minute, 4),
+	)
+	tm.Lock()
+	timers[2].IsPaused = true
+	timers[3].ToggleDirection()
+	tm.ActiveTimers = append(tm.ActiveTimers, timer.NewStopwatch("Lap"))
+	tm.Unlock()
+	advance(tm, 15*time.Minute)
+
+	tm.Lock()
+	var before []time.Duration
+	for _, timer := range tm.ActiveTimers {
+		before = append(before, timer.State.CurrentTime)
+	}
+	tm.Unlock()
+
+	names := tm.SnoozeAll(5 * time.Minute)
+	if want := []string{"Tea", "Read", "Call"}; !slices.Equal(names, want) {
+		t.Errorf("snoozed %v, want %v", names, want)
+	}
+	tm.Lock()
+	defer tm.Unlock()
+	for i, change := range []time.Duration{5 * time.Minute, 5 * time.Minute, 0, -5 * time.Minute, 0} {
+		timer := tm.ActiveTimers[i]
+		if got := timer.State.CurrentTime - before[i]; got != change {
+			t.Errorf("%s changed by %v, want %v", timer.State.Name, got, change)
+		}
+	}
+	// Read is on its break, which now ends 5 minutes later
+	if timers[1].State.IsWork || timers[1].State.CurrentTime != 10*time.Minute {
+		t.Errorf("Read has %v of work %t left, want 10m of its break", timers[1].State.CurrentTime, timers[1].State.IsWork)
+	}
+}
+
+func TestFinalCycle(t *testing.T) {
+	tm, _ := newTestManager(t)
+	config := pomodoro("Sprints", 2*time.Minute, time.Minute, 3)
+	config.FinalCycleWorkDuration = 5 * time.Minute
+	config.FinalCycleBreakDuration = 3 * time.Minute
+	timers := addTimers(tm, config, pomodoro("Plain", 2*time.Minute, time.Minute, 3))
+
+	check := func(tr *timer.Timer, cycle int, isWork bool, left time.Duration) {
+		t.Helper()
+		tm.Lock()
+		defer tm.Unlock()
+		if tr.State.Cycles != cycle || tr.State.IsWork != isWork || tr.State.CurrentTime != left {
+			t.Errorf("%s at cycle %d work %t with %v left, want cycle %d work %t with %v left",
+				tr.State.Name, tr.State.Cycles, tr.State.IsWork, tr.State.CurrentTime, cycle, isWork, left)
+		}
+	}
+	advance(tm, 6*time.Minute)
+	check(timers[0], 3, true, 5*time.Minute)
+	check(timers[1], 3, true, 2*time.Minute)
+	advance(tm, 5*time.Minute)
+	check(timers[0], 3, false, 3*time.Minute)
+	advance(tm, 3*time.Minute)
+	if got := activeNames(tm); len(got) != 0 {
+		t.Errorf("timers = %v, want Sprints done after its final cycle", got)
+	}
+}
+
+func TestBreakActivitiesRotate(t *testing.T) {
+	tm, notifier := newTestManager(t)
+	config := pomodoro("Tea", 2*time.Minute, time.Minute, 4)
+	config.NotifText = "Break"
+	config.BreakActivities = []string{"stretch", "walk", "water"}
+	addTimers(tm, config)
+	advance(tm, 12*time.Minute)
+
+	var breaks []string
+	for _, notification := range notifier.notifications {
+		if strings.HasPrefix(notification, "Tea: b ") {
+			breaks = append(breaks, notification)
+		}
+	}
+	want := []string{
+		"Tea: b Break - try: stretch",
+		"Tea: b Break - try: walk",
+		"Tea: b Break - try: water",
+		"Tea: b Break - try: stretch",
+	}
+	if !slices.Equal(breaks, want) {
+		t.Errorf("break notifications = %q, want %q", breaks, want)
+	}
+}
+
+func TestTraversalOrder(t *testing.T) {
+	for order, want := range map[string][]string{
+		"": {
+			"phase 1 cycle 1 work", "phase 1 cycle 1 break", "phase 1 cycle 2 work", "phase 1 cycle 2 break",
+			"phase 2 cycle 1 work", "phase 2 cycle 1 break", "phase 2 cycle 2 work", "phase 2 cycle 2 break",
+		},
+		config.OrderCycleMajor: {
+			"phase 1 cycle 1 work", "phase 1 cycle 1 break", "phase 2 cycle 1 work", "phase 2 cycle 1 break",
+			"phase 1 cycle 2 work", "phase 1 cycle 2 break", "phase 2 cycle 2 work", "phase 2 cycle 2 break",
+		},
+	} {
+		tm, _ := newTestManager(t)
+		cfg := config.TimerConfig{
+			Name: "Study",
+			Phases: []config.TimerPhase{
+				{WorkDuration: 2 * time.Minute, BreakDuration: time.Minute},
+				{WorkDuration: 3 * time.Minute, BreakDuration: time.Minute},
+			},
+			MaxCycles: 2,
+			Order:     order,
+		}
+		timers := addTimers(tm, cfg)
+
+		var segments []string
+		for range 20 * 60 {
+			advance(tm, time.Second)
+			tm.Lock()
+			state, running := timers[0].State, len(tm.ActiveTimers) > 0
+			tm.Unlock()
+			if !running {
+				break
+			}
+			segment := "break"
+			if state.IsWork {
+				segment = "work"
+			}
+			s := fmt.Sprintf("phase %d cycle %d %s", state.CurrentPhase+1, state.Cycles, segment)
+			if len(segments) == 0 || segments[len(segments)-1] != s {
+				segments = append(segments, s)
+			}
+		}
+		if !slices.Equal(segments, want) {
+			t.Errorf("order %q went through %q, want %q", order, segments, want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package manager
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// metricsCollector reads the manager state on every scrape, so the only
+// series exported are the ones for timers that currently exist
+type metricsCollector struct {
+	tm        *TimerManager
+	active    *prometheus.Desc
+	cycles    *prometheus.Desc
+	remaining *prometheus.Desc
+}
+
+func newMetricsCollector(tm *TimerManager) *metricsCollector {
+	return &metricsCollector{
+		tm: tm,
+		active: prometheus.NewDesc("multitimer_active_timers",
+			"Number of active timers.", nil, nil),
+		cycles: prometheus.NewDesc("multitimer_completed_cycles_total",
+			"Work/break cycles completed since launch.", nil, nil),
+		remaining: prometheus.NewDesc("multitimer_remaining_seconds",
+			"Seconds left in the current segment of each timer.", []string{"timer"}, nil),
+	}
+}
+
+func (c *metricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.active
+	ch <- c.cycles
+	ch <- c.remaining
+}
+
+func (c *metricsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.tm.Lock()
+	defer c.tm.Unlock()
+
+	ch <- prometheus.MustNewConstMetric(c.active, prometheus.GaugeValue, float64(len(c.tm.ActiveTimers)))
+	ch <- prometheus.MustNewConstMetric(c.cycles, prometheus.CounterValue, float64(c.tm.cyclesDone))
+
+	seen := make(map[string]int)
+	for _, timer := range c.tm.ActiveTimers {
+		// Timers can share a name but series can't
+		name := timer.State.Name
+		seen[name]++
+		if seen[name] > 1 {
+			name = fmt.Sprintf("%s#%d", name, seen[name])
+		}
+		remaining := timer.State.CurrentTime
+		if timer.CountUp || timer.OnOpenBreak() {
+			remaining = 0
+		}
+		ch <- prometheus.MustNewConstMetric(c.remaining, prometheus.GaugeValue, remaining.Seconds(), name)
+	}
+}
+
+// metricsHandler serves the metrics under /metrics
+func (tm *TimerManager) metricsHandler() http.Handler {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(newMetricsCollector(tm))
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	return mux
+}
+
+// ServeMetrics exposes the metrics on addr under /metrics
+func (tm *TimerManager) ServeMetrics(addr string) error {
+	// Listen up front so a bad address is reported right away
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	handler := tm.metricsHandler()
+	go func() {
+		if err := http.Serve(listener, handler); err != nil {
+			tm.mu.Lock()
+			tm.ReportError("Error serving metrics", err)
+			tm.mu.Unlock()
+			tm.redraw()
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,51 @@
+package manager
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScrapeMetrics(t *testing.T) {
+	tm, _ := newTestManager(t)
+	addTimers(tm,
+		pomodoro("Tea", 2*time.Minute, time.Minute, 4),
+		pomodoro("Tea", 25*time.Minute, 5*time.Minute, 4),
+		pomodoro("Read", 30*time.Minute, 5*time.Minute, 4),
+	)
+	advance(tm, 3*time.Minute) // the first Tea completes a cycle
+
+	server := httptest.NewServer(tm.metricsHandler())
+	defer server.Close()
+	resp, err := http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"# TYPE multitimer_active_timers gauge",
+		"multitimer_active_timers 3",
+		"# TYPE multitimer_completed_cycles_total counter",
+		"multitimer_completed_cycles_total 1",
+		"# TYPE multitimer_remaining_seconds gauge",
+		`multitimer_remaining_seconds{timer="Tea"} 120`,
+		`multitimer_remaining_seconds{timer="Tea#2"} 1320`,
+		`multitimer_remaining_seconds{timer="Read"} 1620`,
+	} {
+		if !strings.Contains(string(body), want+"\n") {
+			t.Errorf("scrape has no %q:\n%s", want, body)
+		}
+	}
+	// One series per timer, nothing left over from timers that are gone
+	if n := strings.Count(string(body), "multitimer_remaining_seconds{"); n != 3 {
+		t.Errorf("%d remaining series, want 3", n)
+	}
+}
@@ -0,0 +1,55 @@
+package manager
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// milestoneNotifications picks the milestone notifications out of the
+// recorded ones
+func milestoneNotifications(notifier *recordingNotifier) []string {
+	var milestones []string
+	for _, notification := range notifier.notifications {
+		if strings.Contains(notification, "% through") {
+			milestones = append(milestones, notification)
+		}
+	}
+	return milestones
+}
+
+func TestMilestonesFireOncePerSegment(t *testing.T) {
+	tm, notifier := newTestManager(t)
+	config := pomodoro("Essay", 40*time.Minute, 5*time.Minute, 2)
+	config.NotifText = "Write"
+	config.Milestones = []int{25, 50, 75}
+	config.MilestoneMinDuration = 30 * time.Minute
+	addTimers(tm, config)
+
+	advance(tm, 19*time.Minute)
+	if got, want := milestoneNotifications(notifier), []string{"Essay: 25% through: Write"}; !slices.Equal(got, want) {
+		t.Errorf("milestones after 19m = %v, want %v", got, want)
+	}
+	// Through the rest of the work, the break and the second cycle's work
+	advance(tm, 66*time.Minute)
+	want := []string{
+		"Essay: 25% through: Write", "Essay: 50% through: Write", "Essay: 75% through: Write",
+		"Essay: 25% through: Write", "Essay: 50% through: Write", "Essay: 75% through: Write",
+	}
+	if got := milestoneNotifications(notifier); !slices.Equal(got, want) {
+		t.Errorf("milestones = %v, want each once per work segment", got)
+	}
+}
+
+func TestMilestonesSkipShortSegments(t *testing.T) {
+	tm, notifier := newTestManager(t)
+	config := pomodoro("Tea", 10*time.Minute, 5*time.Minute, 2)
+	config.Milestones = []int{50}
+	config.MilestoneMinDuration = 30 * time.Minute
+	addTimers(tm, config)
+	advance(tm, 30*time.Minute)
+	if got := milestoneNotifications(notifier); len(got) != 0 {
+		t.Errorf("milestones on a segment under the minimum: %v", got)
+	}
+}
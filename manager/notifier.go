@@ -0,0 +1,86 @@
+package manager
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/gen2brain/beeep"
+
+	"multi-timer/config"
+	"multi-timer/timer"
+)
+
+// Urgency is how insistently a notification is shown
+type Urgency int
+
+const (
+	NormalUrgency Urgency = iota
+	CriticalUrgency
+)
+
+// Notifier delivers desktop notifications and sound cues
+type Notifier interface {
+	Notify(title, message string, level Urgency) error
+	Sound(name string) error
+}
+
+type beeepNotifier struct{}
+
+func (beeepNotifier) Notify(title, message string, level Urgency) error {
+	if level == CriticalUrgency {
+		// Alert also plays the system sound
+		return beeep.Alert(title, message, "")
+	}
+	return beeep.Notify(title, message, "")
+}
+
+// SendNotification shows the notification unless notifications are muted.
+// Must be called with tm.mu held.
+func (tm *TimerManager) SendNotification(title, message string, level Urgency) {
+	if tm.quiet(tm.Now()) {
+		return
+	}
+	if err := tm.Notifier.Notify(title, message, level); err != nil {
+		tm.ReportError("Error sending notification", err)
+	}
+}
+
+// notificationTitle is the timer name, made unique per cycle and phase when
+// the notification daemon would otherwise stack them
+func (tm *TimerManager) notificationTitle(t *timer.Timer, event timer.TimerEvent) string {
+	if !tm.Prefs.UniqueTitles {
+		return t.State.Name
+	}
+	return fmt.Sprintf("%s (%s, cycle %d, phase %d)", t.State.Name, event.Segment, event.Cycle, event.Phase+1)
+}
+
+// TextOverride replaces the text of every timer's work and break
+// notifications until it's cleared
+type TextOverride struct {
+	Work string
+	Rest string
+}
+
+func (o *TextOverride) text(segment string) string {
+	if segment == "Break" {
+		return o.Rest
+	}
+	return o.Work
+}
+
+// SetNotifText changes the notification text of timer num and of its saved
+// config, then saves the configs
+func (tm *TimerManager) SetNotifText(num int, text string) error {
+	tm.Lock()
+	if num < 1 || num > len(tm.ActiveTimers) {
+		tm.Unlock()
+		return fmt.Errorf("no timer %d", num)
+	}
+	tm.ActiveTimers[num-1].State.NotifText = text
+	if j := tm.PairConfigs()[num-1]; j >= 0 {
+		tm.Configs[j].NotifText = text
+	}
+	path, configs := tm.ConfigPath, slices.Clone(tm.Configs)
+	tm.Unlock()
+	return config.SaveTimerConfigs(path, configs)
+}
@@ -0,0 +1,11 @@
+//go:build !linux || nodbus
+
+package manager
+
+// newNotifier sends plain notifications. Buttons on macOS and Windows
+// notifications only report back to a signed app bundle or a registered
+// app id, which a command line tool doesn't have, so there the break is
+// answered at the prompt with s, resume and the like.
+func newNotifier() Notifier {
+	return beeepNotifier{}
+}
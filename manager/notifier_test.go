@@ -0,0 +1,89 @@
+package manager
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"multi-timer/config"
+)
+
+func TestUniqueTitles(t *testing.T) {
+	for _, unique := range []bool{false, true} {
+		tm, notifier := newTestManager(t)
+		tm.Prefs.UniqueTitles = unique
+		addTimers(tm, pomodoro("Tea", time.Minute, time.Minute, 2))
+		advance(tm, 5*time.Minute)
+
+		titles := map[string]bool{}
+		for _, notification := range notifier.notifications {
+			title, _, _ := strings.Cut(notification, ": ")
+			titles[title] = true
+		}
+		if !unique {
+			if len(titles) != 1 || !titles["Tea"] {
+				t.Errorf("titles without the option = %v, want all Tea", titles)
+			}
+			continue
+		}
+		if len(titles) != len(notifier.notifications) {
+			t.Errorf("titles repeat with unique titles on: %v", notifier.notifications)
+		}
+		if !titles["Tea (Break, cycle 1, phase 1)"] {
+			t.Errorf("titles = %v, want one for the first break", titles)
+		}
+	}
+}
+
+func TestTextOverride(t *testing.T) {
+	tm, notifier := newTestManager(t)
+	config := pomodoro("Tea", 2*time.Minute, time.Minute, 4)
+	config.NotifText = "Tea time"
+	addTimers(tm, config)
+
+	tm.Lock()
+	tm.Override = &TextOverride{Work: "Back to work", Rest: "Take a break"}
+	tm.Unlock()
+	advance(tm, 3*time.Minute)
+	want := []string{"Tea: Take a break", "Tea: Back to work"}
+	if !slices.Equal(notifier.notifications, want) {
+		t.Errorf("notifications with the override = %v, want %v", notifier.notifications, want)
+	}
+
+	tm.Lock()
+	tm.Override = nil
+	tm.Unlock()
+	notifier.notifications = nil
+	advance(tm, 3*time.Minute)
+	want = []string{"Tea: b Tea time", "Tea: Tea time"}
+	if !slices.Equal(notifier.notifications, want) {
+		t.Errorf("notifications once cleared = %v, want %v", notifier.notifications, want)
+	}
+}
+
+func TestSetNotifText(t *testing.T) {
+	tm, notifier := newTestManager(t)
+	addTimers(tm,
+		pomodoro("Tea", 2*time.Minute, time.Minute, 4),
+		pomodoro("Read", 30*time.Minute, 5*time.Minute, 4),
+	)
+	if err := tm.SetNotifText(1, "Kettle's on,  go!"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tm.SetNotifText(3, "Nobody"); err == nil {
+		t.Error("set the text of a timer that doesn't exist")
+	}
+
+	advance(tm, 2*time.Minute)
+	if want := []string{"Tea: b Kettle's on,  go!"}; !slices.Equal(notifier.notifications, want) {
+		t.Errorf("notifications = %v, want %v", notifier.notifications, want)
+	}
+	saved, err := config.LoadTimerConfigs(tm.ConfigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved[0].NotifText != "Kettle's on,  go!" || saved[1].NotifText != "" {
+		t.Errorf("saved texts %q and %q, want only Tea's changed", saved[0].NotifText, saved[1].NotifText)
+	}
+}
@@ -0,0 +1,50 @@
+package manager
+
+import "fmt"
+
+// How many notifications are kept
+const notificationLogSize = 50
+
+// logNotification appends to the bounded notification log. Must be called
+// with tm.mu held.
+func (tm *TimerManager) logNotification(n SentNotification) {
+	tm.notificationLog = append(tm.notificationLog, n)
+	if len(tm.notificationLog) > notificationLogSize {
+		tm.notificationLog = tm.notificationLog[len(tm.notificationLog)-notificationLogSize:]
+	}
+	if tm.paneOffset > 0 {
+		// Keep showing the same lines while scrolled back
+		tm.paneOffset = min(tm.paneOffset+1, len(tm.notificationLog)-1)
+	}
+}
+
+// Notifications are the notifications sent lately, oldest first. Must be
+// called with tm.mu held.
+func (tm *TimerManager) Notifications() []SentNotification {
+	return tm.notificationLog
+}
+
+// PaneOffset is how far the notification pane is scrolled back. Must be
+// called with tm.mu held.
+func (tm *TimerManager) PaneOffset() int {
+	return tm.paneOffset
+}
+
+// ScrollPane scrolls the notification pane n notifications back, forward for
+// a negative n, as far as a pane of rows goes. Must be called with tm.mu held.
+func (tm *TimerManager) ScrollPane(n, rows int) {
+	tm.paneOffset = max(min(tm.paneOffset+n, len(tm.notificationLog)-rows), 0)
+}
+
+// ReportError shows an error of work done in the background under the timers,
+// where printing it would end up in the middle of the display. Must be called
+// with tm.mu held.
+func (tm *TimerManager) ReportError(what string, err error) {
+	tm.lastError = fmt.Sprintf("%s at %s: %v", what, tm.Now().Format("15:04:05"), err)
+}
+
+// LastError is the last error ReportError showed, "" for none. Must be called
+// with tm.mu held.
+func (tm *TimerManager) LastError() string {
+	return tm.lastError
+}
@@ -0,0 +1,21 @@
+package manager
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNotificationLogBounded(t *testing.T) {
+	tm, _ := newTestManager(t)
+	tm.Lock()
+	defer tm.Unlock()
+	for i := range notificationLogSize + 10 {
+		tm.logNotification(SentNotification{"Tea", "", tm.Now().Add(time.Duration(i) * time.Second)})
+	}
+	if len(tm.notificationLog) != notificationLogSize {
+		t.Fatalf("log holds %d notifications, want %d", len(tm.notificationLog), notificationLogSize)
+	}
+	if got := tm.notificationLog[0].At.Sub(tm.Now()); got != 10*time.Second {
+		t.Errorf("oldest kept notification is %v in, want the first 10 dropped", got)
+	}
+}
@@ -0,0 +1,110 @@
+package manager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"multi-timer/config"
+	"multi-timer/timer"
+)
+
+const (
+	profilesDir    = "profiles"
+	defaultProfile = "default"
+)
+
+// profilePath returns the config file of the named profile, in the same
+// format as the current one. The default profile is the main config file.
+func (tm *TimerManager) profilePath(name string) string {
+	file := strings.TrimSuffix(config.ConfigFile, filepath.Ext(config.ConfigFile)) + filepath.Ext(tm.ConfigPath)
+	if name == defaultProfile {
+		return config.DataFile(file)
+	}
+	return filepath.Join(config.DataDir, profilesDir, name, file)
+}
+
+func validProfileName(name string) bool {
+	return name != "" && name != "." && name != ".." && filepath.Base(name) == name
+}
+
+// SwitchProfile saves the current timers and replaces them with the timers
+// of the named profile
+func (tm *TimerManager) SwitchProfile(name string) error {
+	if !validProfileName(name) {
+		return fmt.Errorf("invalid profile name %q", name)
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	if err := config.SaveTimerConfigs(tm.ConfigPath, tm.Configs); err != nil {
+		return err
+	}
+	path := tm.profilePath(name)
+	configs, err := config.LoadTimerConfigs(path)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tm.ActiveTimers = make([]*timer.Timer, 0, len(configs))
+	for _, config := range configs {
+		if len(config.Phases) > 0 && config.StartAt == "" {
+			t := timer.TimerFromConfig(config)
+			tm.ActiveTimers = append(tm.ActiveTimers, t)
+			if tm.GroupRunning(t) {
+				t.IsPaused = true
+			}
+		}
+	}
+	tm.Configs = configs
+	tm.completed = make(map[string]int)
+	tm.ConfigPath = path
+	tm.Profile = name
+	if name == defaultProfile {
+		tm.Profile = ""
+	}
+
+	if tm.watcher != nil {
+		if err := tm.watcher.Add(filepath.Dir(path)); err != nil {
+			fmt.Println("Error watching timer configurations:", err)
+		}
+	}
+	return nil
+}
+
+// CloneProfile copies the config file of profile src to the new profile dst
+func (tm *TimerManager) CloneProfile(src, dst string) error {
+	for _, name := range []string{src, dst} {
+		if !validProfileName(name) {
+			return fmt.Errorf("invalid profile name %q", name)
+		}
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	// The current profile may have changes that aren't saved yet
+	if err := config.SaveTimerConfigs(tm.ConfigPath, tm.Configs); err != nil {
+		return err
+	}
+	from, to := tm.profilePath(src), tm.profilePath(dst)
+	data, err := os.ReadFile(from)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("profile %q has no timers", src)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(to); err == nil {
+		return fmt.Errorf("profile %q already exists", dst)
+	}
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(to, data, 0644)
+}
@@ -0,0 +1,116 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"multi-timer/config"
+)
+
+// activeNames lists the names of the running timers
+func activeNames(tm *TimerManager) []string {
+	tm.Lock()
+	defer tm.Unlock()
+	var names []string
+	for _, timer := range tm.ActiveTimers {
+		names = append(names, timer.State.Name)
+	}
+	return names
+}
+
+func TestSwitchProfile(t *testing.T) {
+	tm, _ := newTestManager(t)
+	addTimers(tm, pomodoro("Report", 50*time.Minute, 10*time.Minute, 4))
+
+	personal := []config.TimerConfig{
+		pomodoro("Guitar", 20*time.Minute, 5*time.Minute, 2),
+		pomodoro("Reading", 30*time.Minute, 5*time.Minute, -1),
+	}
+	path := tm.profilePath("personal")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.SaveTimerConfigs(path, personal); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tm.SwitchProfile("personal"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := activeNames(tm), []string{"Guitar", "Reading"}; !slices.Equal(got, want) {
+		t.Errorf("after switching to personal the timers are %v, want %v", got, want)
+	}
+
+	// The work timers were saved on the way out and come back
+	if err := tm.SwitchProfile(defaultProfile); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := activeNames(tm), []string{"Report"}; !slices.Equal(got, want) {
+		t.Errorf("after switching back the timers are %v, want %v", got, want)
+	}
+	if tm.Profile != "" || tm.ConfigPath != config.DataFile(config.ConfigFile) {
+		t.Errorf("profile %q with configs at %s, want the default", tm.Profile, tm.ConfigPath)
+	}
+}
+
+func TestSwitchProfileRejectsPaths(t *testing.T) {
+	tm, _ := newTestManager(t)
+	for _, name := range []string{"", "..", "a/b"} {
+		if err := tm.SwitchProfile(name); err == nil {
+			t.Errorf("SwitchProfile(%q) succeeded", name)
+		}
+	}
+}
+
+func TestCloneProfile(t *testing.T) {
+	tm, _ := newTestManager(t)
+	addTimers(tm, pomodoro("Report", 50*time.Minute, 10*time.Minute, 4))
+	path := tm.profilePath("personal")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.SaveTimerConfigs(path, []config.TimerConfig{pomodoro("Guitar", 20*time.Minute, 5*time.Minute, 2)}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tm.CloneProfile("personal", "travel"); err != nil {
+		t.Fatal(err)
+	}
+	source, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clone, err := os.ReadFile(tm.profilePath("travel"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(clone) != string(source) {
+		t.Errorf("clone =\n%s\nwant the source\n%s", clone, source)
+	}
+
+	// The current profile is saved first, so the clone has its timers
+	if err := tm.CloneProfile(defaultProfile, "work"); err != nil {
+		t.Fatal(err)
+	}
+	configs, err := config.LoadTimerConfigs(tm.profilePath("work"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(configs) != 1 || configs[0].Name != "Report" {
+		t.Errorf("clone of the current profile has %v, want Report", configs)
+	}
+
+	if err := tm.CloneProfile("personal", "travel"); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("cloning onto an existing profile: error %v", err)
+	}
+	if err := tm.CloneProfile("missing", "other"); err == nil {
+		t.Error("cloned a profile that doesn't exist")
+	}
+	if err := tm.CloneProfile("personal", "../escape"); err == nil {
+		t.Error("cloned to a path outside the profiles")
+	}
+}
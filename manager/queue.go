@@ -0,0 +1,147 @@
+package manager
+
+import (
+	"container/heap"
+	"slices"
+	"time"
+
+	"multi-timer/timer"
+)
+
+// The update loop doesn't touch every timer on every tick. Between the
+// moments something can happen to a timer (a segment running out, a
+// milestone, a brand new timer) a tick only adds its time to tm.pending.
+// The soonest of those moments are kept in a min-heap: a tick that reaches
+// one pops the timers that are due and updates only those, and the others
+// catch up on the time in one go when they are looked at. Code outside the
+// update loop uses tm.Lock and tm.Unlock, so it always sees the timers up to
+// date and the heap entries of the timers it paused, edited or added are
+// moved. Inside the update loop, whatever pauses or resumes another timer
+// catches it up first, or the time it ran since the last catch up is lost.
+//
+// That includes the display, so every redraw catches every timer up. With
+// the display redrawn every second the heap only saves the work of the ticks
+// in between: the boundary checks, events and scheduling of the timers that
+// aren't due. It saves the most with a longer -refresh, and when nothing
+// redraws, as in the daemon. See BenchmarkRedraw.
+
+// wakeup is the manager clock reading at which a timer needs a full update
+type wakeup struct {
+	timer *timer.Timer
+	due   time.Duration
+}
+
+// wakeQueue keeps Timer.Wake pointing at each timer's entry, for heap.Fix
+// and heap.Remove
+type wakeQueue []wakeup
+
+func (q wakeQueue) Len() int { return len(q) }
+
+func (q wakeQueue) Less(i, j int) bool { return q[i].due < q[j].due }
+
+func (q wakeQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].timer.SetWake(i + 1)
+	q[j].timer.SetWake(j + 1)
+}
+
+func (q *wakeQueue) Push(x any) {
+	entry := x.(wakeup)
+	*q = append(*q, entry)
+	entry.timer.SetWake(len(*q))
+}
+
+func (q *wakeQueue) Pop() any {
+	old := *q
+	last := old[len(old)-1]
+	*q = old[:len(old)-1]
+	last.timer.SetWake(0)
+	return last
+}
+
+// catchUp gives the timer the part of the saved up time it hasn't had yet.
+// Must be called with tm.mu held.
+func (tm *TimerManager) catchUp(t *timer.Timer) bool {
+	return t.CatchUp(tm.pending)
+}
+
+// settle brings every timer up to date with the time the update loop has
+// been saving up. The due timers were updated when their wakeup came, so no
+// timer crosses a boundary on the way and nothing but the times changes.
+// Must be called with tm.mu held.
+func (tm *TimerManager) settle() {
+	if tm.pending == 0 {
+		return
+	}
+	for _, timer := range tm.ActiveTimers {
+		timer.Settle(tm.pending)
+	}
+	tm.pending = 0
+}
+
+// schedule queues, moves or drops the timer's wakeup if its next boundary
+// changed since it was queued. Must be called with tm.mu held.
+func (tm *TimerManager) schedule(t *timer.Timer) {
+	d, ok := t.NextBoundary()
+	// Timers that haven't caught up are that much behind the clock
+	due := tm.clock - tm.pending + t.CaughtUp() + d
+	switch {
+	case ok && t.Wake() == 0:
+		heap.Push(&tm.wakeups, wakeup{t, due})
+	case ok && tm.wakeups[t.Wake()-1].due != due:
+		tm.wakeups[t.Wake()-1].due = due
+		heap.Fix(&tm.wakeups, t.Wake()-1)
+	case !ok && t.Wake() > 0:
+		heap.Remove(&tm.wakeups, t.Wake()-1)
+	}
+}
+
+// syncWakeups schedules every timer, which moves the heap entries of those
+// paused, edited or added since they were queued and none of the others, and
+// finds the soonest escalation. Must be called with tm.mu held.
+func (tm *TimerManager) syncWakeups() {
+	tm.nextAlert = time.Time{}
+	for _, timer := range tm.ActiveTimers {
+		tm.schedule(timer)
+		if timer.Alert != nil && (tm.nextAlert.IsZero() || timer.Alert.Due.Before(tm.nextAlert)) {
+			tm.nextAlert = timer.Alert.Due
+		}
+	}
+}
+
+// dueTimers pops the timers whose wakeup the clock reached, leaving out those
+// deleted since they were queued. Must be called with tm.mu held.
+func (tm *TimerManager) dueTimers() []*timer.Timer {
+	var due []*timer.Timer
+	for len(tm.wakeups) > 0 && tm.wakeups[0].due <= tm.clock {
+		timer := heap.Pop(&tm.wakeups).(wakeup).timer
+		if slices.Contains(tm.ActiveTimers, timer) {
+			due = append(due, timer)
+		}
+	}
+	return due
+}
+
+// alertDue reports whether a pending notification is due to escalate. Must
+// be called with tm.mu held.
+func (tm *TimerManager) alertDue() bool {
+	return !tm.nextAlert.IsZero() && !tm.Now().Before(tm.nextAlert)
+}
+
+// Lock takes tm.mu with the timers up to date
+func (tm *TimerManager) Lock() {
+	tm.mu.Lock()
+	tm.settle()
+}
+
+// Unlock releases tm.mu once the wakeups reflect whatever was changed
+func (tm *TimerManager) Unlock() {
+	tm.syncWakeups()
+	tm.mu.Unlock()
+}
+
+// Clock is the timer time since launch, which moves with the ticks. Must be
+// called with tm.mu held.
+func (tm *TimerManager) Clock() time.Duration {
+	return tm.clock
+}
@@ -0,0 +1,328 @@
+package manager
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"multi-timer/config"
+	"multi-timer/timer"
+)
+
+func TestDueTimersInWakeupOrder(t *testing.T) {
+	tm, _ := newTestManager(t)
+	timers := addTimers(tm,
+		pomodoro("long", 50*time.Minute, 10*time.Minute, -1),
+		pomodoro("short", 5*time.Minute, time.Minute, -1),
+		pomodoro("middle", 25*time.Minute, 5*time.Minute, -1),
+	)
+	advance(tm, time.Second) // the first tick starts them all
+
+	tests := []struct {
+		after time.Duration
+		want  []string // the names of the timers due, in order
+	}{
+		{5*time.Minute - 2*time.Second, nil},
+		{time.Second, []string{"short"}},
+		// short's break and work end every few minutes, middle's work at 25:00
+		{20 * time.Minute, []string{"short", "short", "short", "short", "short", "short", "short", "middle"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		tm.Lock()
+		for end := tm.clock + tt.after; tm.clock < end; {
+			tm.clock += timer.TickInterval
+			tm.pending += timer.TickInterval
+			for _, timer := range tm.dueTimers() {
+				got = append(got, timer.State.Name)
+				tm.catchUp(timer)
+				tm.handleEvents(timer)
+			}
+			tm.syncWakeups()
+		}
+		tm.Unlock()
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("due within %v: got %v, want %v", tt.after, got, tt.want)
+		}
+	}
+
+	// Every timer still knows where its entry is
+	for i, entry := range tm.wakeups {
+		if entry.timer.Wake() != i+1 {
+			t.Errorf("%s: wake is %d, its entry is at %d", entry.timer.State.Name, entry.timer.Wake(), i+1)
+		}
+	}
+	if len(tm.wakeups) != len(timers) {
+		t.Errorf("got %d wakeups for %d timers", len(tm.wakeups), len(timers))
+	}
+}
+
+func TestTickUpdatesOnlyDueTimers(t *testing.T) {
+	tm, _ := newTestManager(t)
+	timers := addTimers(tm,
+		pomodoro("short", 10*time.Second, 5*time.Second, -1),
+		pomodoro("long", time.Hour, 5*time.Minute, -1),
+	)
+	short, long := timers[0], timers[1]
+	advance(tm, time.Second) // the first tick counts for the timers too
+
+	tm.mu.Lock()
+	for i := 0; i < 10; i++ {
+		tm.tick(timer.TickInterval)
+	}
+	// Without the lock the long timer hasn't been touched since the first tick
+	if got, want := long.State.CurrentTime, time.Hour-time.Second; got != want {
+		t.Errorf("long timer: got %v before catching up, want %v", got, want)
+	}
+	if short.State.IsWork {
+		t.Errorf("short timer: still on work after its wakeup")
+	}
+	tm.mu.Unlock()
+
+	tm.Lock()
+	defer tm.Unlock()
+	if got, want := long.State.CurrentTime, time.Hour-11*time.Second; got != want {
+		t.Errorf("long timer: got %v once locked, want %v", got, want)
+	}
+	if got, want := short.State.CurrentTime, 4*time.Second; got != want {
+		t.Errorf("short timer: got %v of break, want %v", got, want)
+	}
+}
+
+func TestUnlockMovesChangedWakeups(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(tm *TimerManager, tr *timer.Timer)
+		queued bool
+		dueIn  time.Duration
+	}{
+		{"unchanged", func(*TimerManager, *timer.Timer) {}, true, 25 * time.Minute},
+		{"paused", func(tm *TimerManager, tr *timer.Timer) { tm.TogglePause(tr) }, false, 0},
+		{"time added", func(tm *TimerManager, tr *timer.Timer) { tr.AddTime(5 * time.Minute) }, true, 30 * time.Minute},
+		{"time set", func(tm *TimerManager, tr *timer.Timer) { tr.SetRemaining(time.Minute, false) }, true, time.Minute},
+		{"edited", func(tm *TimerManager, tr *timer.Timer) {
+			config := tr.Config()
+			config.Phases[0].WorkDuration = 10 * time.Minute
+			tr.ApplyConfig(config)
+		}, true, 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm, _ := newTestManager(t)
+			timer := addTimers(tm, pomodoro("work", 25*time.Minute, 5*time.Minute, -1))[0]
+			advance(tm, time.Second)
+			tm.Lock()
+			timer.SetRemaining(25*time.Minute, false)
+			tm.Unlock()
+
+			tm.Lock()
+			tt.change(tm, timer)
+			tm.Unlock()
+
+			tm.mu.Lock()
+			defer tm.mu.Unlock()
+			if queued := timer.Wake() > 0; queued != tt.queued {
+				t.Fatalf("queued: got %v, want %v", queued, tt.queued)
+			}
+			if !tt.queued {
+				return
+			}
+			if got := tm.wakeups[timer.Wake()-1].due - tm.clock; got != tt.dueIn {
+				t.Errorf("due in %v, want %v", got, tt.dueIn)
+			}
+		})
+	}
+}
+
+func TestResumedTimerWakesUp(t *testing.T) {
+	tm, _ := newTestManager(t)
+	timer := addTimers(tm, pomodoro("work", time.Minute, 30*time.Second, -1))[0]
+	advance(tm, 10*time.Second)
+	tm.Lock()
+	tm.TogglePause(timer)
+	tm.Unlock()
+	advance(tm, 10*time.Minute)
+	tm.Lock()
+	tm.TogglePause(timer)
+	tm.Unlock()
+	advance(tm, 51*time.Second)
+
+	tm.Lock()
+	defer tm.Unlock()
+	if timer.State.IsWork {
+		t.Fatalf("still on work after a minute of it")
+	}
+	if got, want := timer.State.CurrentTime, 29*time.Second; got != want {
+		t.Errorf("break: got %v left, want %v", got, want)
+	}
+}
+
+// tickEveryTimer is how the update loop worked before wakeups: every timer is
+// updated on every tick
+// A timer paused by another's rule mid-step keeps the time it ran before,
+// though it wasn't due and hadn't caught up
+func TestDependentPausedMidStepCatchesUp(t *testing.T) {
+	tm, _ := newTestManager(t)
+	tm.Stepping = true
+	a := pomodoro("A", time.Minute, time.Minute, -1)
+	a.PausesOnWork = []string{"B"}
+	timers := addTimers(tm, a, pomodoro("B", 10*time.Minute, 5*time.Minute, 1))
+
+	// A's second work starts at 2:00 and pauses B
+	tm.Step(122)
+	tm.Lock()
+	defer tm.Unlock()
+	b := timers[1]
+	if !b.IsPaused {
+		t.Fatal("B is still running after A started work")
+	}
+	if got := b.State.CurrentTime; got != 8*time.Minute {
+		t.Errorf("B has %v left, want the 8m it had when A paused it", got)
+	}
+}
+
+func tickEveryTimer(tm *TimerManager, elapsed time.Duration) {
+	tm.clock += elapsed
+	for i := len(tm.ActiveTimers) - 1; i >= 0; i-- {
+		timer := tm.ActiveTimers[i]
+		if timer.Started().IsZero() {
+			timer.SetStarted(tm.Now())
+		}
+		if timer.Update(elapsed) {
+			tm.ActiveTimers = append(tm.ActiveTimers[:i], tm.ActiveTimers[i+1:]...)
+		}
+		tm.handleEvents(timer)
+	}
+}
+
+func TestQueueMatchesEveryTimerTicks(t *testing.T) {
+	configs := []config.TimerConfig{
+		pomodoro("a", 25*time.Minute, 5*time.Minute, 4),
+		pomodoro("b", 7*time.Minute, 3*time.Minute, -1),
+		pomodoro("c", 90*time.Second, 45*time.Second, 10),
+		{Name: "d", Phases: []config.TimerPhase{{WorkDuration: 20 * time.Minute, BreakDuration: 10 * time.Minute}}, MaxCycles: 3, Milestones: []int{50, 75}},
+	}
+	queued, _ := newTestManager(t)
+	addTimers(queued, configs...)
+	everyTick, _ := newTestManager(t)
+	addTimers(everyTick, configs...)
+
+	for minute := 1; minute <= 180; minute++ {
+		advance(queued, time.Minute)
+		everyTick.mu.Lock()
+		for i := 0; i < 60; i++ {
+			tickEveryTimer(everyTick, timer.TickInterval)
+		}
+		everyTick.mu.Unlock()
+
+		queued.Lock()
+		got := fmt.Sprint(timerStates(queued.ActiveTimers))
+		queued.Unlock()
+		if want := fmt.Sprint(timerStates(everyTick.ActiveTimers)); got != want {
+			t.Fatalf("after %d minutes:\n got %s\nwant %s", minute, got, want)
+		}
+	}
+}
+
+func timerStates(timers []*timer.Timer) []timer.TimerState {
+	states := make([]timer.TimerState, len(timers))
+	for i, timer := range timers {
+		states[i] = timer.State
+	}
+	return states
+}
+
+// benchmarkManager runs n timers of work lengths from 20 minutes up, or with
+// idle of a thousand hours so no segment ends during the benchmark. They
+// have no saved configs, so completed cycles aren't saved to disk.
+func benchmarkManager(b *testing.B, n int, idle bool) *TimerManager {
+	tm, _ := newTestManager(b)
+	tm.Lock()
+	for i := 0; i < n; i++ {
+		work := time.Duration(20+i%40) * time.Minute
+		if idle {
+			work = 1000 * time.Hour
+		}
+		tm.ActiveTimers = append(tm.ActiveTimers, timer.TimerFromConfig(pomodoro(fmt.Sprint("timer ", i), work, 5*time.Minute, -1)))
+	}
+	tm.tick(timer.TickInterval) // start them
+	tm.Unlock()
+	return tm
+}
+
+// BenchmarkTick compares a minute of ticks with the wakeup heap against
+// updating every timer on every tick, with segments ending now and then and
+// without
+func BenchmarkTick(b *testing.B) {
+	for _, idle := range []bool{false, true} {
+		for _, n := range []int{10, 100, 1000} {
+			kind := "busy"
+			if idle {
+				kind = "idle"
+			}
+			b.Run(fmt.Sprintf("queue/%s/%d", kind, n), func(b *testing.B) {
+				tm := benchmarkManager(b, n, idle)
+				tm.mu.Lock()
+				defer tm.mu.Unlock()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					for j := 0; j < 60; j++ {
+						tm.tick(timer.TickInterval)
+					}
+				}
+			})
+			b.Run(fmt.Sprintf("every-timer/%s/%d", kind, n), func(b *testing.B) {
+				tm := benchmarkManager(b, n, idle)
+				tm.mu.Lock()
+				defer tm.mu.Unlock()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					for j := 0; j < 60; j++ {
+						tickEveryTimer(tm, timer.TickInterval)
+					}
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkLock is what a command pays to look at the timers between ticks
+func BenchmarkLock(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			tm := benchmarkManager(b, n, false)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				tm.mu.Lock()
+				tm.tick(timer.TickInterval)
+				tm.mu.Unlock()
+				tm.Lock()
+				tm.Unlock()
+			}
+		})
+	}
+}
+
+// BenchmarkRedraw is a minute of ticks with the display catching every timer
+// up every refresh ticks, the heap only saves the work in between
+func BenchmarkRedraw(b *testing.B) {
+	for _, refresh := range []int{1, 5, 60} {
+		for _, n := range []int{100, 1000} {
+			b.Run(fmt.Sprintf("every-%ds/%d", refresh, n), func(b *testing.B) {
+				tm := benchmarkManager(b, n, false)
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					for j := 1; j <= 60; j++ {
+						tm.mu.Lock()
+						tm.tick(timer.TickInterval)
+						tm.mu.Unlock()
+						if j%refresh == 0 {
+							tm.Lock()
+							tm.Unlock()
+						}
+					}
+				}
+			})
+		}
+	}
+}
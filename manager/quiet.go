@@ -0,0 +1,13 @@
+package manager
+
+import "time"
+
+// quiet reports whether notifications are muted at now
+func (tm *TimerManager) quiet(now time.Time) bool {
+	for _, q := range tm.Prefs.QuietHours {
+		if q.Contains(now) {
+			return true
+		}
+	}
+	return false
+}
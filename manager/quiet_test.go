@@ -0,0 +1,36 @@
+package manager
+
+import (
+	"testing"
+	"time"
+
+	"multi-timer/config"
+)
+
+func TestQuietHoursSuppressNotifications(t *testing.T) {
+	tm, notifier := newTestManager(t)
+	quiet, err := config.ParseQuietHours("09:00-09:30", "mon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tm.Prefs.QuietHours = []config.QuietHours{quiet}
+	timers := addTimers(tm, pomodoro("Tea", 10*time.Minute, 5*time.Minute, 4))
+
+	// Three transitions inside the window, the timer keeps running
+	advance(tm, 29*time.Minute)
+	if len(notifier.notifications) != 0 {
+		t.Errorf("notified during quiet hours: %v", notifier.notifications)
+	}
+	tm.Lock()
+	cycles, isWork := timers[0].State.Cycles, timers[0].State.IsWork
+	tm.Unlock()
+	if cycles != 2 || isWork {
+		t.Errorf("timer at cycle %d work %t, want the second break", cycles, isWork)
+	}
+
+	// The third cycle starts at 09:30, once the window is over
+	advance(tm, time.Minute)
+	if len(notifier.notifications) != 1 {
+		t.Errorf("notifications after quiet hours: %v, want the third cycle's", notifier.notifications)
+	}
+}
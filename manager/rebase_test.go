@@ -0,0 +1,53 @@
+package manager
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"multi-timer/config"
+	"multi-timer/timer"
+)
+
+func TestRebase(t *testing.T) {
+	tm, _ := newTestManager(t)
+	cfg := config.TimerConfig{
+		Name: "Study",
+		Phases: config.ResolvePhases([]config.TimerPhase{
+			{WorkScale: 1, BreakDuration: 5 * time.Minute},
+			{WorkDuration: 50 * time.Minute, BreakScale: 0.5},
+		}, 20*time.Minute),
+		BaseDuration: 20 * time.Minute,
+		MaxCycles:    2,
+	}
+	timers := addTimers(tm, cfg)
+	advance(tm, 5*time.Minute)
+
+	tm.Lock()
+	defer tm.Unlock()
+	if !timers[0].Rebase(30 * time.Minute) {
+		t.Fatal("couldn't rebase a timer with relative phases")
+	}
+	want := []config.TimerPhase{
+		{WorkDuration: 30 * time.Minute, WorkScale: 1, BreakDuration: 5 * time.Minute},
+		{WorkDuration: 50 * time.Minute, BreakDuration: 15 * time.Minute, BreakScale: 0.5},
+	}
+	if !reflect.DeepEqual(timers[0].Phases, want) {
+		t.Errorf("phases = %+v\nwant     %+v", timers[0].Phases, want)
+	}
+	if timers[0].Base != 30*time.Minute {
+		t.Errorf("base = %v, want 30m", timers[0].Base)
+	}
+	// The segment carries on with the time it had left
+	if got := timers[0].State.CurrentTime; got != 15*time.Minute {
+		t.Errorf("%v left after the rebase, want 15m", got)
+	}
+
+	absolute := timer.TimerFromConfig(pomodoro("Tea", 25*time.Minute, 5*time.Minute, 4))
+	if absolute.Rebase(30 * time.Minute) {
+		t.Error("rebased a timer without relative phases")
+	}
+	if absolute.Phases[0].WorkDuration != 25*time.Minute {
+		t.Errorf("rebasing changed an absolute phase to %v", absolute.Phases[0].WorkDuration)
+	}
+}
@@ -0,0 +1,55 @@
+package manager
+
+import (
+	"sort"
+	"time"
+
+	"multi-timer/config"
+)
+
+// AggregateRemaining adds up the time left on the running timers that
+// complete on their own, only the work unless prefs.IncludeBreaks is set.
+// Must be called with tm.mu held.
+func (tm *TimerManager) AggregateRemaining() (time.Duration, bool) {
+	var total time.Duration
+	counted := false
+	for _, timer := range tm.ActiveTimers {
+		if timer.IsPaused {
+			continue
+		}
+		remaining, ok := timer.WorkRemaining()
+		if tm.Prefs.IncludeBreaks {
+			remaining, ok = timer.TotalRemaining()
+		}
+		if ok {
+			total = config.AddDurations(total, remaining)
+			counted = true
+		}
+	}
+	return total, counted
+}
+
+type endingTimer struct {
+	Index     int
+	Name      string
+	Remaining time.Duration
+}
+
+// EndingWithin lists the running timers that complete within window, soonest
+// first. Must be called with tm.mu held.
+func (tm *TimerManager) EndingWithin(window time.Duration) []endingTimer {
+	var ending []endingTimer
+	for i, timer := range tm.ActiveTimers {
+		if timer.IsPaused {
+			continue
+		}
+		remaining, ok := timer.TotalRemaining()
+		if ok && remaining <= window {
+			ending = append(ending, endingTimer{i + 1, timer.State.Name, remaining})
+		}
+	}
+	sort.SliceStable(ending, func(i, j int) bool {
+		return ending[i].Remaining < ending[j].Remaining
+	})
+	return ending
+}
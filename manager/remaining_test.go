@@ -0,0 +1,41 @@
+package manager
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEndingWithin(t *testing.T) {
+	tm, _ := newTestManager(t)
+	timers := addTimers(tm,
+		pomodoro("Long", 25*time.Minute, 5*time.Minute, 4),
+		pomodoro("Short", 10*time.Minute, 2*time.Minute, 1),
+		pomodoro("Paused", 5*time.Minute, time.Minute, 1),
+		pomodoro("Forever", 5*time.Minute, time.Minute, -1),
+		pomodoro("Soon", 3*time.Minute, time.Minute, 1),
+	)
+	tm.Lock()
+	defer tm.Unlock()
+	timers[2].IsPaused = true
+
+	ending := tm.EndingWithin(30 * time.Minute)
+	want := []endingTimer{{5, "Soon", 0}, {2, "Short", 0}}
+	if len(ending) != len(want) {
+		t.Fatalf("ending within 30m = %+v, want Soon and Short", ending)
+	}
+	for i, got := range ending {
+		if got.Index != want[i].Index || got.Name != want[i].Name {
+			t.Errorf("ending[%d] = #%d %s, want #%d %s", i, got.Index, got.Name, want[i].Index, want[i].Name)
+		}
+		remaining, _ := timers[got.Index-1].TotalRemaining()
+		if got.Remaining != remaining {
+			t.Errorf("%s ends in %v, want its total remaining %v", got.Name, got.Remaining, remaining)
+		}
+	}
+	if ending := tm.EndingWithin(time.Minute); len(ending) != 0 {
+		t.Errorf("ending within 1m = %+v, want none", ending)
+	}
+	if ending := tm.EndingWithin(3 * time.Hour); len(ending) != 3 || ending[2].Name != "Long" {
+		t.Errorf("ending within 3h = %+v, want Long last", ending)
+	}
+}
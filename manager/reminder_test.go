@@ -0,0 +1,64 @@
+package manager
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// remindersOf counts the recorded notifications of Tea with text
+func remindersOf(notifier *recordingNotifier, text string) int {
+	n := 0
+	for _, notification := range notifier.notifications {
+		if notification == "Tea: "+text {
+			n++
+		}
+	}
+	return n
+}
+
+func TestReminderCadence(t *testing.T) {
+	tm, notifier := newTestManager(t)
+	config := pomodoro("Tea", 25*time.Minute, 5*time.Minute, 4)
+	config.ReminderDuration = 20 * time.Minute
+	config.ReminderText = "Drink water"
+	timers := addTimers(tm, config)
+
+	advance(tm, 19*time.Minute)
+	if n := remindersOf(notifier, "Drink water"); n != 0 {
+		t.Errorf("%d reminders after 19m, want none", n)
+	}
+	advance(tm, time.Minute)
+	if n := remindersOf(notifier, "Drink water"); n != 1 {
+		t.Errorf("%d reminders after 20m, want 1", n)
+	}
+
+	// Paused time doesn't count
+	tm.Lock()
+	timers[0].IsPaused = true
+	tm.Unlock()
+	advance(tm, 30*time.Minute)
+	tm.Lock()
+	timers[0].IsPaused = false
+	tm.Unlock()
+	advance(tm, 19*time.Minute)
+	if n := remindersOf(notifier, "Drink water"); n != 1 {
+		t.Errorf("%d reminders after 39m of active time, want 1", n)
+	}
+	advance(tm, time.Minute)
+	if n := remindersOf(notifier, "Drink water"); n != 2 {
+		t.Errorf("%d reminders after 40m of active time, want 2", n)
+	}
+
+	// The phases carried on as usual in between: work ended at 25m and the
+	// break at 30m
+	want := []string{"Tea: Drink water", "Tea: b ", "Tea: ", "Tea: Drink water"}
+	if !slices.Equal(notifier.notifications, want) {
+		t.Errorf("notifications = %q, want %q", notifier.notifications, want)
+	}
+	tm.Lock()
+	defer tm.Unlock()
+	if got := timers[0].State.CurrentTime; timers[0].State.Cycles != 2 || got != 15*time.Minute {
+		t.Errorf("timer at cycle %d with %v left, want cycle 2 with 15m", timers[0].State.Cycles, got)
+	}
+}
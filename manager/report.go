@@ -0,0 +1,82 @@
+package manager
+
+import (
+	"time"
+
+	"multi-timer/timer"
+)
+
+// SessionPlan is what a timer was planned to take and where it stands
+type SessionPlan struct {
+	Name      string
+	Planned   time.Duration // timer time from start to completion
+	Bounded   bool          // false when it doesn't complete on its own
+	Remaining time.Duration
+	Current   time.Duration // wall time in the unfinished segment so far
+}
+
+// ReportRow is the line of one timer in the session report
+type ReportRow struct {
+	Name    string
+	Planned time.Duration
+	Bounded bool
+	Done    time.Duration // timer time of the plan already served
+	Actual  time.Duration // wall time the segments took
+}
+
+// Overrun is how much longer than planned the time served took: pauses,
+// snoozes, added time and breaks waiting for resume
+func (r ReportRow) Overrun() time.Duration {
+	return r.Actual - r.Done
+}
+
+// SessionReport compares the plans with the wall time the history recorded
+// for the same timers. Only the history of the session should be passed.
+func SessionReport(plans []SessionPlan, history []HistoryEntry) []ReportRow {
+	actual := make(map[string]time.Duration)
+	for _, entry := range history {
+		if entry.Start != nil {
+			actual[entry.Timer] += entry.Time.Sub(*entry.Start)
+		}
+	}
+	rows := make([]ReportRow, 0, len(plans))
+	for _, plan := range plans {
+		row := ReportRow{Name: plan.Name, Planned: plan.Planned, Bounded: plan.Bounded}
+		if plan.Bounded {
+			row.Done = max(plan.Planned-plan.Remaining, 0)
+		}
+		row.Actual = actual[plan.Name] + plan.Current
+		// Timers sharing a name share the history, count it once
+		delete(actual, plan.Name)
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// SessionPlans lists the running timers and those that completed this
+// session. Must be called with tm.mu held.
+func (tm *TimerManager) SessionPlans() []SessionPlan {
+	var plans []SessionPlan
+	now := tm.Now()
+	for _, t := range tm.ActiveTimers {
+		if t.IsStopwatch() {
+			continue
+		}
+		plan := SessionPlan{Name: t.State.Name}
+		plan.Planned, plan.Bounded = timer.TimerFromConfig(t.Config()).TotalRemaining()
+		plan.Remaining, _ = t.TotalRemaining()
+		if !t.Started().IsZero() {
+			plan.Current = now.Sub(t.Started())
+		}
+		plans = append(plans, plan)
+	}
+	for _, config := range tm.Configs {
+		if tm.completed[config.Name] == 0 || tm.isRunning(config.Name) {
+			continue
+		}
+		plan := SessionPlan{Name: config.Name}
+		plan.Planned, plan.Bounded = timer.TimerFromConfig(config).TotalRemaining()
+		plans = append(plans, plan)
+	}
+	return plans
+}
@@ -0,0 +1,46 @@
+package manager
+
+import (
+	"time"
+
+	"multi-timer/config"
+	"multi-timer/timer"
+)
+
+// runScheduler starts every scheduled timer whose start time passed since the
+// last check and whose days include today. Must be called with tm.mu held.
+func (tm *TimerManager) runScheduler(now time.Time) bool {
+	if tm.lastSchedule.IsZero() {
+		// Start times before launch were missed, don't fire them all at once
+		tm.lastSchedule = now
+		return false
+	}
+
+	started := false
+	for _, cfg := range tm.Configs {
+		start, ok := config.ScheduledStart(cfg, now)
+		if !ok || !start.After(tm.lastSchedule) || start.After(now) || !cfg.RunsOn(now.Weekday()) {
+			continue
+		}
+		if tm.isRunning(cfg.Name) || len(cfg.Phases) == 0 {
+			continue
+		}
+		// Pausing the group must not lose the time the others saved up
+		tm.settle()
+		t := timer.TimerFromConfig(cfg)
+		tm.ActiveTimers = append(tm.ActiveTimers, t)
+		tm.PauseGroup(t)
+		started = true
+	}
+	tm.lastSchedule = now
+	return started
+}
+
+func (tm *TimerManager) isRunning(name string) bool {
+	for _, timer := range tm.ActiveTimers {
+		if timer.State.Name == name {
+			return true
+		}
+	}
+	return false
+}
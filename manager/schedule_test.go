@@ -0,0 +1,46 @@
+package manager
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"multi-timer/config"
+)
+
+func TestWeekdaySchedule(t *testing.T) {
+	weekdays := config.TimerConfig{
+		Name:      "Standup",
+		Phases:    []config.TimerPhase{{WorkDuration: 15 * time.Minute, BreakDuration: 0}},
+		MaxCycles: 1,
+		StartAt:   "09:30",
+		Days:      []string{"Mon", "Tue", "Wed", "Thu", "Fri"},
+	}
+	everyDay := weekdays
+	everyDay.Name, everyDay.Days = "Stretch", nil
+
+	// 2024-03-04 is a Monday
+	for day := 4; day <= 10; day++ {
+		date := time.Date(2024, 3, day, 0, 0, 0, 0, time.UTC)
+		t.Run(date.Weekday().String(), func(t *testing.T) {
+			tm, _ := newTestManager(t)
+			tm.Configs = []config.TimerConfig{weekdays, everyDay}
+			tm.Lock()
+			defer tm.Unlock()
+			tm.runScheduler(date.Add(9 * time.Hour))
+			tm.runScheduler(date.Add(9*time.Hour + 31*time.Minute))
+
+			var started []string
+			for _, timer := range tm.ActiveTimers {
+				started = append(started, timer.State.Name)
+			}
+			want := []string{"Standup", "Stretch"}
+			if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+				want = []string{"Stretch"}
+			}
+			if !slices.Equal(started, want) {
+				t.Errorf("started %v, want %v", started, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package manager
+
+import (
+	"fmt"
+)
+
+// Skip moves timer num past its current segment, or with cycle past the rest
+// of its cycle. Must be called with tm.mu held.
+func (tm *TimerManager) Skip(num int, cycle bool) error {
+	if num < 1 || num > len(tm.ActiveTimers) {
+		return fmt.Errorf("no timer %d", num)
+	}
+	t := tm.ActiveTimers[num-1]
+	switch {
+	case t.IsStopwatch():
+		return fmt.Errorf("a stopwatch has nothing to skip")
+	case t.Protected():
+		return fmt.Errorf("deep work cycle, it can't be skipped")
+	}
+
+	cycles := t.Stats.CompletedCycles
+	var completed bool
+	if cycle {
+		completed = t.SkipCycle()
+	} else {
+		completed = t.SkipSegment()
+	}
+	tm.cyclesDone += t.Stats.CompletedCycles - cycles
+	tm.handleEvents(t)
+	if completed {
+		tm.ActiveTimers = append(tm.ActiveTimers[:num-1], tm.ActiveTimers[num:]...)
+		tm.finish(t)
+	}
+	return nil
+}
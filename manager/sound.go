@@ -0,0 +1,152 @@
+package manager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gen2brain/beeep"
+	"github.com/gopxl/beep/v2"
+	"github.com/gopxl/beep/v2/flac"
+	"github.com/gopxl/beep/v2/mp3"
+	"github.com/gopxl/beep/v2/speaker"
+	"github.com/gopxl/beep/v2/vorbis"
+	"github.com/gopxl/beep/v2/wav"
+)
+
+type tone struct {
+	freq     float64
+	duration int // milliseconds
+}
+
+// sounds are the cues a config can name for its sounds, which can also be
+// the path of an audio file, see audioExtensions
+var sounds = map[string][]tone{
+	"beep":    {{beeep.DefaultFreq, 200}},
+	"chime":   {{880, 120}, {1320, 240}},
+	"bell":    {{660, 400}},
+	"fanfare": {{523, 150}, {659, 150}, {784, 150}, {1047, 400}},
+}
+
+// SoundNames lists the built in sounds for the help
+func SoundNames() string {
+	var names []string
+	for name := range sounds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// ValidSound reports whether name is a built in sound or an audio file, or
+// empty for none
+func ValidSound(name string) bool {
+	_, ok := sounds[name]
+	return name == "" || ok || IsAudioFile(name)
+}
+
+// Audio files are decoded and played in process, see playFile
+var audioExtensions = []string{".wav", ".mp3", ".ogg", ".flac"}
+
+// speakerRate is what the speaker is opened at, files at other rates are
+// resampled to it
+const speakerRate beep.SampleRate = 44100
+
+var (
+	speakerOnce sync.Once
+	speakerErr  error
+)
+
+// IsAudioFile reports whether name has the extension of an audio file that
+// can be played
+func IsAudioFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, audio := range audioExtensions {
+		if ext == audio {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeAudio opens the audio file with the decoder for its extension
+func decodeAudio(path string) (beep.StreamSeekCloser, beep.Format, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, beep.Format{}, err
+	}
+	var (
+		stream beep.StreamSeekCloser
+		format beep.Format
+	)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".wav":
+		stream, format, err = wav.Decode(f)
+	case ".mp3":
+		stream, format, err = mp3.Decode(f)
+	case ".ogg":
+		stream, format, err = vorbis.Decode(f)
+	case ".flac":
+		stream, format, err = flac.Decode(f)
+	default:
+		err = fmt.Errorf("unsupported audio file %s", path)
+	}
+	if err != nil {
+		f.Close()
+		return nil, beep.Format{}, err
+	}
+	return stream, format, nil
+}
+
+// playFile starts playing the audio file without waiting for it to end
+func playFile(path string) error {
+	speakerOnce.Do(func() {
+		speakerErr = speaker.Init(speakerRate, speakerRate.N(time.Second/10))
+	})
+	if speakerErr != nil {
+		return speakerErr
+	}
+	stream, format, err := decodeAudio(path)
+	if err != nil {
+		return err
+	}
+	var played beep.Streamer = stream
+	if format.SampleRate != speakerRate {
+		played = beep.Resample(4, format.SampleRate, speakerRate, stream)
+	}
+	speaker.Play(beep.Seq(played, beep.Callback(func() {
+		stream.Close()
+	})))
+	return nil
+}
+
+func (beeepNotifier) Sound(name string) error {
+	if IsAudioFile(name) {
+		return playFile(name)
+	}
+	tones, ok := sounds[name]
+	if !ok {
+		return fmt.Errorf("unknown sound %q", name)
+	}
+	for _, t := range tones {
+		if err := beeep.Beep(t.freq, t.duration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// playSound plays one of a timer's cues, if it has one, unless sounds are
+// muted or it's quiet hours. Must be called with tm.mu held.
+func (tm *TimerManager) playSound(name string) {
+	if name == "" || tm.Prefs.Muted || tm.quiet(tm.Now()) {
+		return
+	}
+	if err := tm.Notifier.Sound(name); err != nil {
+		tm.ReportError("Error playing sound", err)
+	}
+}
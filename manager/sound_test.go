@@ -0,0 +1,40 @@
+package manager
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestStartAndCompleteSounds(t *testing.T) {
+	tm, notifier := newTestManager(t)
+	config := pomodoro("Tea", 2*time.Minute, time.Minute, 2)
+	config.StartSound = "chime"
+	config.CompleteSound = "fanfare"
+	config.WorkSound = "tick"
+	config.BreakSound = "bell"
+	addTimers(tm, config)
+
+	advance(tm, time.Second)
+	if want := []string{"chime"}; !slices.Equal(notifier.sounds, want) {
+		t.Errorf("sounds at the start = %v, want %v", notifier.sounds, want)
+	}
+	advance(tm, 6*time.Minute)
+	want := []string{"chime", "bell", "tick", "bell", "fanfare"}
+	if !slices.Equal(notifier.sounds, want) {
+		t.Errorf("sounds = %v, want %v", notifier.sounds, want)
+	}
+}
+
+func TestMutedPlaysNoSounds(t *testing.T) {
+	tm, notifier := newTestManager(t)
+	tm.Prefs.Muted = true
+	config := pomodoro("Tea", time.Minute, time.Minute, 1)
+	config.StartSound = "chime"
+	config.CompleteSound = "fanfare"
+	addTimers(tm, config)
+	advance(tm, 3*time.Minute)
+	if len(notifier.sounds) != 0 {
+		t.Errorf("muted timers played %v", notifier.sounds)
+	}
+}
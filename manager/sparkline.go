@@ -0,0 +1,16 @@
+package manager
+
+import "time"
+
+// SampleRemaining records the time left on every timer, at most once a
+// second so the line covers the last sparkSamples seconds however often the
+// display redraws. Must be called with tm.mu held.
+func (tm *TimerManager) SampleRemaining(now time.Time) {
+	for _, timer := range tm.ActiveTimers {
+		if timer.IsStopwatch() || now.Sub(timer.Samples.Last) < time.Second {
+			continue
+		}
+		timer.Samples.Add(timer.State.CurrentTime)
+		timer.Samples.Last = now
+	}
+}
@@ -0,0 +1,135 @@
+package manager
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// The history log can be mirrored into an SQLite database for analytics.
+// Every run of the app is a session and every history entry an event of one
+// timer in one session.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS timers (
+	id   INTEGER PRIMARY KEY,
+	name TEXT NOT NULL UNIQUE
+);
+CREATE TABLE IF NOT EXISTS sessions (
+	id      INTEGER PRIMARY KEY,
+	started TEXT NOT NULL UNIQUE
+);
+CREATE TABLE IF NOT EXISTS events (
+	id       INTEGER PRIMARY KEY,
+	session  INTEGER NOT NULL REFERENCES sessions(id),
+	timer    INTEGER NOT NULL REFERENCES timers(id),
+	event    TEXT NOT NULL,
+	phase    INTEGER,
+	cycle    INTEGER,
+	start    TEXT,
+	time     TEXT NOT NULL,
+	active   REAL, -- seconds the block ran, less the pauses
+	outcome  TEXT,
+	achieved INTEGER
+);`
+
+type historyDB struct {
+	db      *sql.DB
+	session int64
+}
+
+// openHistoryDB opens the database at path, creating the tables the first
+// time, and adds the session that started at launched
+func openHistoryDB(path string, launched time.Time) (*historyDB, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	// One connection, or every connection to ":memory:" gets its own database
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("creating tables: %w", err)
+	}
+	// Databases created before the active column was added get it now
+	var hasActive bool
+	err = db.QueryRow(`SELECT COUNT(*) > 0 FROM pragma_table_info('events') WHERE name = 'active'`).Scan(&hasActive)
+	if err == nil && !hasActive {
+		_, err = db.Exec(`ALTER TABLE events ADD COLUMN active REAL`)
+	}
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("adding the active column: %w", err)
+	}
+	h := &historyDB{db: db}
+	h.session, err = h.id("sessions", "started", launched.Format(time.RFC3339Nano))
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return h, nil
+}
+
+// id is the id of the row of table with column set to value, added if needed
+func (h *historyDB) id(table, column string, value any) (int64, error) {
+	if _, err := h.db.Exec(fmt.Sprintf("INSERT OR IGNORE INTO %s (%s) VALUES (?)", table, column), value); err != nil {
+		return 0, err
+	}
+	var id int64
+	err := h.db.QueryRow(fmt.Sprintf("SELECT id FROM %s WHERE %s = ?", table, column), value).Scan(&id)
+	return id, err
+}
+
+func (h *historyDB) insert(entry HistoryEntry) error {
+	timer, err := h.id("timers", "name", entry.Timer)
+	if err != nil {
+		return err
+	}
+	var start, active, outcome, achieved any
+	if entry.Start != nil {
+		start = entry.Start.Format(time.RFC3339Nano)
+		active = entry.duration().Seconds()
+	}
+	if entry.Outcome != "" {
+		outcome = entry.Outcome
+	}
+	if entry.Achieved != nil {
+		achieved = *entry.Achieved
+	}
+	_, err = h.db.Exec(`INSERT INTO events (session, timer, event, phase, cycle, start, time, active, outcome, achieved)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		h.session, timer, entry.Event, entry.Phase, entry.Cycle, start, entry.Time.Format(time.RFC3339Nano), active, outcome, achieved)
+	return err
+}
+
+func (h *historyDB) close() error {
+	return h.db.Close()
+}
+
+// UseDatabase opens the database at path, "" for none, and has the history
+// writer mirror the entries logged from now on into it. It's called when the
+// preference changes rather than on every write, so a database that can't be
+// opened fails once. Must be called without tm.mu held.
+func (tm *TimerManager) UseDatabase(path string) error {
+	var db *historyDB
+	if path != "" {
+		var err error
+		if db, err = openHistoryDB(path, tm.launched); err != nil {
+			return err
+		}
+	}
+	tm.historyWrites <- historyWrite{db: db, switchDB: true}
+	return nil
+}
+
+// writeHistory adds the entry to the history log and the database, if any
+func writeHistory(path string, db *historyDB, entry HistoryEntry) error {
+	if err := appendHistory(path, entry); err != nil {
+		return err
+	}
+	if db == nil {
+		return nil
+	}
+	return db.insert(entry)
+}
@@ -0,0 +1,74 @@
+package manager
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// sqliteEvent is a row of the events table with the names joined in
+type sqliteEvent struct {
+	timer, event string
+	phase, cycle int
+	active       float64
+}
+
+func sqliteEvents(t *testing.T, db *historyDB) []sqliteEvent {
+	t.Helper()
+	rows, err := db.db.Query(`SELECT timers.name, event, phase, cycle, COALESCE(active, 0) FROM events
+		JOIN timers ON timers.id = events.timer ORDER BY events.id`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var events []sqliteEvent
+	for rows.Next() {
+		var e sqliteEvent
+		if err := rows.Scan(&e.timer, &e.event, &e.phase, &e.cycle, &e.active); err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return events
+}
+
+func TestSQLiteRowsForCompletedEvents(t *testing.T) {
+	tm, _ := newTestManager(t)
+	db, err := openHistoryDB(":memory:", tm.launched)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// What UseDatabase does, keeping hold of the database to read it back
+	tm.historyWrites <- historyWrite{db: db, switchDB: true}
+
+	addTimers(tm, pomodoro("Tea", 2*time.Minute, time.Minute, 2), pomodoro("Read", 10*time.Minute, time.Minute, 1))
+	advance(tm, 7*time.Minute)
+	tm.FlushHistory()
+
+	events := sqliteEvents(t, db)
+	// Read is still in its first work block
+	want := []sqliteEvent{
+		{"Tea", "work", 1, 1, 120},
+		{"Tea", "break", 1, 1, 60},
+		{"Tea", "cycle", 1, 1, 0},
+		{"Tea", "work", 1, 2, 120},
+		{"Tea", "break", 1, 2, 60},
+		{"Tea", "cycle", 1, 2, 0},
+	}
+	if !slices.Equal(events, want) {
+		t.Errorf("events = %+v\nwant     %+v", events, want)
+	}
+
+	var timers, sessions, session int
+	err = db.db.QueryRow(`SELECT (SELECT COUNT(*) FROM timers), (SELECT COUNT(*) FROM sessions), MAX(session) FROM events`).
+		Scan(&timers, &sessions, &session)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if timers != 1 || sessions != 1 || int64(session) != db.session {
+		t.Errorf("%d timers and %d sessions with events in session %d, want Tea alone in session %d", timers, sessions, session, db.session)
+	}
+}
@@ -0,0 +1,167 @@
+package manager
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"multi-timer/config"
+	"multi-timer/timer"
+)
+
+// The running timers' progress is saved to the state file every
+// StateInterval and on quit, and put back on launch, so a session that was
+// interrupted carries on where it stopped. Time doesn't pass for the timers
+// while the app isn't running.
+const (
+	StateFile     = "state.json"
+	StateInterval = 10 * time.Second
+)
+
+// TimerSnapshot is the progress of one running timer
+type TimerSnapshot struct {
+	Name            string
+	Stopwatch       bool `json:",omitempty"`
+	Stage           timer.TimerStage
+	IsWork          bool
+	CurrentTime     time.Duration
+	Cycles          int
+	CurrentPhase    int
+	Paused          bool          `json:",omitempty"`
+	CountUp         bool          `json:",omitempty"`
+	CompletedCycles int           `json:",omitempty"`
+	WorkTime        time.Duration `json:",omitempty"`
+
+	MilestoneReached int           `json:",omitempty"`
+	ReminderElapsed  time.Duration `json:",omitempty"`
+	NextActivity     int           `json:",omitempty"`
+
+	// Time the segment has left after the snooze, when snoozed
+	SnoozedTime    time.Duration `json:",omitempty"`
+	SnoozedMessage string        `json:",omitempty"`
+}
+
+func saveState(path string, snapshots []TimerSnapshot) error {
+	data, err := json.MarshalIndent(snapshots, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// LoadState reads the snapshots saved at path, none if there is no file
+func LoadState(path string) ([]TimerSnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var snapshots []TimerSnapshot
+	err = json.Unmarshal(data, &snapshots)
+	return snapshots, err
+}
+
+// snapshot records the progress of the timers worth restoring, which are
+// those with a saved config and stopwatches. Must be called with tm.mu held.
+func (tm *TimerManager) snapshot() []TimerSnapshot {
+	snapshots := make([]TimerSnapshot, 0, len(tm.ActiveTimers))
+	for _, timer := range tm.ActiveTimers {
+		if timer.Ephemeral && !timer.IsStopwatch() {
+			continue
+		}
+		snap := TimerSnapshot{
+			Name:             timer.State.Name,
+			Stopwatch:        timer.IsStopwatch(),
+			Stage:            timer.State.Stage,
+			IsWork:           timer.State.IsWork,
+			CurrentTime:      timer.State.CurrentTime,
+			Cycles:           timer.State.Cycles,
+			CurrentPhase:     timer.State.CurrentPhase,
+			Paused:           timer.IsPaused,
+			CountUp:          timer.CountUp,
+			CompletedCycles:  timer.Stats.CompletedCycles,
+			WorkTime:         timer.Stats.WorkTime,
+			MilestoneReached: timer.MilestoneReached,
+			ReminderElapsed:  timer.ReminderElapsed,
+			NextActivity:     timer.NextActivity,
+		}
+		if timer.Snoozed != nil {
+			snap.SnoozedTime, snap.SnoozedMessage = timer.Snoozed.SegmentTime, timer.Snoozed.Message
+		}
+		snapshots = append(snapshots, snap)
+	}
+	return snapshots
+}
+
+// RestoreState puts the saved progress back on the timers of the same name,
+// in order like PairConfigs, and brings back the stopwatches. Snapshots that
+// no longer fit their timer's phases are dropped. Must be called with tm.mu
+// held.
+func (tm *TimerManager) RestoreState(snapshots []TimerSnapshot) {
+	used := make([]bool, len(tm.ActiveTimers))
+	for _, snap := range snapshots {
+		if snap.Stopwatch {
+			t := timer.NewStopwatch(snap.Name)
+			t.State.CurrentTime = snap.CurrentTime
+			t.IsPaused = snap.Paused
+			t.SetStarted(tm.Now())
+			tm.ActiveTimers = append(tm.ActiveTimers, t)
+			used = append(used, true)
+			continue
+		}
+		for i, t := range tm.ActiveTimers {
+			if used[i] || t.Ephemeral || t.State.Name != snap.Name {
+				continue
+			}
+			used[i] = true
+			if snap.CurrentPhase < 0 || snap.CurrentPhase >= len(t.Phases) || t.Kind == config.TypeAlarm {
+				break // alarms go by the clock, they are already where they should be
+			}
+			t.State.Stage = snap.Stage
+			t.State.IsWork = snap.IsWork
+			t.State.CurrentTime = snap.CurrentTime
+			t.State.Cycles = snap.Cycles
+			t.State.CurrentPhase = snap.CurrentPhase
+			t.IsPaused = snap.Paused
+			t.CountUp = snap.CountUp
+			t.Stats.CompletedCycles = snap.CompletedCycles
+			t.Stats.WorkTime = snap.WorkTime
+			t.MilestoneReached = snap.MilestoneReached
+			t.ReminderElapsed = snap.ReminderElapsed
+			t.NextActivity = snap.NextActivity
+			if snap.SnoozedTime > 0 {
+				t.Snoozed = &timer.Snoozed{SegmentTime: snap.SnoozedTime, Message: snap.SnoozedMessage}
+			}
+			// The phases may have been edited since the snapshot
+			t.FitToPhases()
+
+			// Carrying on, not starting, so no start sound or webhook
+			t.SetStarted(tm.Now())
+			t.Clock = tm.Now
+			break
+		}
+	}
+}
+
+// SaveStateEvery writes the state file every interval until the app exits
+func (tm *TimerManager) SaveStateEvery(interval time.Duration) {
+	go func() {
+		for range time.Tick(interval) {
+			if err := tm.writeState(); err != nil {
+				tm.mu.Lock()
+				tm.ReportError("Error saving timer state", err)
+				tm.mu.Unlock()
+				tm.redraw()
+			}
+		}
+	}()
+}
+
+func (tm *TimerManager) writeState() error {
+	tm.Lock()
+	path, snapshots := tm.StatePath, tm.snapshot()
+	tm.Unlock()
+	return saveState(path, snapshots)
+}
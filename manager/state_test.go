@@ -0,0 +1,100 @@
+package manager
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"multi-timer/config"
+	"multi-timer/timer"
+)
+
+// restarted saves the state of tm and restores it on a fresh manager running
+// timers for configs, as the next launch would
+func restarted(t *testing.T, tm *TimerManager, configs ...config.TimerConfig) *TimerManager {
+	t.Helper()
+	if err := tm.writeState(); err != nil {
+		t.Fatal(err)
+	}
+	snapshots, err := LoadState(tm.StatePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, _ := newTestManager(t)
+	addTimers(next, configs...)
+	next.Lock()
+	next.RestoreState(snapshots)
+	next.Unlock()
+	return next
+}
+
+func TestStateRoundTrip(t *testing.T) {
+	tm, _ := newTestManager(t)
+	configs := []config.TimerConfig{
+		pomodoro("Tea", 25*time.Minute, 5*time.Minute, 4),
+		pomodoro("Read", 30*time.Minute, 10*time.Minute, 4),
+	}
+	timers := addTimers(tm, configs...)
+	tm.Lock()
+	tm.ActiveTimers = append(tm.ActiveTimers, timer.NewStopwatch("Essay"))
+	tm.Unlock()
+	advance(tm, 33*time.Minute) // Tea is on its second work, Read on its break
+	tm.Lock()
+	timers[1].IsPaused = true
+	want := timerStates(tm.ActiveTimers)
+	wantStats := []timer.TimerStats{timers[0].Stats, timers[1].Stats}
+	tm.Unlock()
+
+	next := restarted(t, tm, configs...)
+	next.Lock()
+	defer next.Unlock()
+	if got := timerStates(next.ActiveTimers); !reflect.DeepEqual(got, want) {
+		t.Errorf("restored %+v, want %+v", got, want)
+	}
+	if !next.ActiveTimers[1].IsPaused || next.ActiveTimers[0].IsPaused {
+		t.Error("the restored timers aren't paused the way they were")
+	}
+	if got := []timer.TimerStats{next.ActiveTimers[0].Stats, next.ActiveTimers[1].Stats}; !reflect.DeepEqual(got, wantStats) {
+		t.Errorf("restored stats %+v, want %+v", got, wantStats)
+	}
+	if !next.ActiveTimers[2].IsStopwatch() || next.ActiveTimers[2].State.CurrentTime != 33*time.Minute {
+		t.Errorf("restored %+v, want the stopwatch at 33m", next.ActiveTimers[2].State)
+	}
+}
+
+// Snapshots of timers whose config was edited since are fitted to the new
+// phases
+func TestRestoreFitsEditedPhases(t *testing.T) {
+	tm, _ := newTestManager(t)
+	warmup := pomodoro("Stretch", 10*time.Minute, 2*time.Minute, 4)
+	warmup.WarmupDuration = 5 * time.Minute
+	addTimers(tm,
+		pomodoro("Tea", 25*time.Minute, 5*time.Minute, 4),
+		pomodoro("Read", 30*time.Minute, 10*time.Minute, 4),
+		warmup,
+	)
+	advance(tm, 2*time.Minute)
+	tm.Lock()
+	tm.ActiveTimers[1].State.Cycles = 4
+	tm.Unlock()
+
+	// Tea's work is down to 10m, Read has 2 cycles and Stretch no warmup
+	next := restarted(t, tm,
+		pomodoro("Tea", 10*time.Minute, 5*time.Minute, 4),
+		pomodoro("Read", 30*time.Minute, 10*time.Minute, 2),
+		pomodoro("Stretch", 10*time.Minute, 2*time.Minute, 4),
+	)
+	next.Lock()
+	defer next.Unlock()
+	for i, want := range []timer.TimerState{
+		{Name: "Tea", Stage: timer.StageCycles, IsWork: true, CurrentTime: 10 * time.Minute, Cycles: 1},
+		{Name: "Read", Stage: timer.StageCycles, IsWork: true, CurrentTime: 28 * time.Minute, Cycles: 2},
+		{Name: "Stretch", Stage: timer.StageCycles, IsWork: true, CurrentTime: 10 * time.Minute, Cycles: 1},
+	} {
+		got := next.ActiveTimers[i].State
+		got.NotifText = ""
+		if got != want {
+			t.Errorf("restored %+v, want %+v", got, want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package manager
+
+import (
+	"fmt"
+	"time"
+
+	"multi-timer/timer"
+)
+
+// CombineStats adds up the stats of the timers at the given 1-based indices.
+// Must be called with tm.mu held.
+func (tm *TimerManager) CombineStats(indices []int) (timer.TimerStats, []string, error) {
+	var total timer.TimerStats
+	var names []string
+	if len(indices) == 0 {
+		return total, nil, fmt.Errorf("usage: combine <number> <number>...")
+	}
+	for _, num := range indices {
+		if num <= 0 || num > len(tm.ActiveTimers) {
+			return total, nil, fmt.Errorf("no timer %d", num)
+		}
+		timer := tm.ActiveTimers[num-1]
+		total.CompletedCycles += timer.Stats.CompletedCycles
+		total.WorkTime += timer.Stats.WorkTime
+		names = append(names, timer.State.Name)
+	}
+	return total, names, nil
+}
+
+// periodStats is what a timer got done in a day or week of the history
+type periodStats struct {
+	Cycles int
+	Work   time.Duration
+	Rest   time.Duration
+}
+
+// HistoryStats adds up the history entries at or after since under each of
+// the keys of the entry, keys in the order they first appear
+func HistoryStats(entries []HistoryEntry, since time.Time, keys func(HistoryEntry) []string) ([]string, map[string]periodStats) {
+	var names []string
+	totals := make(map[string]periodStats)
+	for _, entry := range entries {
+		if entry.Time.Before(since) {
+			continue
+		}
+		for _, key := range keys(entry) {
+			total, seen := totals[key]
+			switch {
+			case entry.Event == "cycle":
+				total.Cycles++
+			case entry.Event == "work" && entry.Start != nil:
+				total.Work += entry.duration()
+			case entry.Event == "break" && entry.Start != nil:
+				total.Rest += entry.duration()
+			default:
+				continue
+			}
+			if !seen {
+				names = append(names, key)
+			}
+			totals[key] = total
+		}
+	}
+	return names, totals
+}
+
+// ByTimer puts an entry under the name of its timer
+func ByTimer(entry HistoryEntry) []string { return []string{entry.Timer} }
+
+// ByTag puts an entry under every tag its timer had, so a timer tagged
+// twice counts toward both
+func ByTag(entry HistoryEntry) []string {
+	tags := make([]string, len(entry.Tags))
+	for i, tag := range entry.Tags {
+		tags[i] = "#" + tag
+	}
+	return tags
+}
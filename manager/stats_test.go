@@ -0,0 +1,38 @@
+package manager
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCombineStats(t *testing.T) {
+	tm, _ := newTestManager(t)
+	addTimers(tm,
+		pomodoro("Tea", 2*time.Minute, time.Minute, 4),
+		pomodoro("Read", 3*time.Minute, time.Minute, 4),
+		pomodoro("Walk", time.Minute, time.Minute, 4),
+	)
+	// Tea has done 2 cycles and 4m of work, Read 1 cycle and 5m, Walk 3
+	// cycles and 3m
+	advance(tm, 6*time.Minute)
+
+	tm.Lock()
+	defer tm.Unlock()
+	total, names, err := tm.CombineStats([]int{1, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Tea", "Walk"}; !slices.Equal(names, want) {
+		t.Errorf("combined %v, want %v", names, want)
+	}
+	if total.CompletedCycles != 5 || total.WorkTime != 7*time.Minute {
+		t.Errorf("combined %d cycles and %v of work, want 5 cycles and 7m", total.CompletedCycles, total.WorkTime)
+	}
+
+	for _, indices := range [][]int{nil, {1, 4}, {0}} {
+		if _, _, err := tm.CombineStats(indices); err == nil {
+			t.Errorf("combining %v succeeded", indices)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package manager
+
+import "multi-timer/timer"
+
+// WriteLaps logs the laps of a stopwatch that was stopped, paused, reset or
+// deleted, and forgets them when it starts over. Must be called with tm.mu
+// held.
+func (tm *TimerManager) WriteLaps(t *timer.Timer, clear bool) {
+	for i := t.LapsWritten; i < len(t.Laps); i++ {
+		start := t.Started()
+		if i > 0 {
+			start = t.Laps[i-1].At
+		}
+		tm.logHistory(HistoryEntry{
+			Time:  t.Laps[i].At,
+			Start: &start,
+			Timer: t.State.Name,
+			Tags:  t.Tags,
+			Event: "lap",
+			Cycle: i + 1,
+		})
+		t.LapsWritten = i + 1
+	}
+	if clear {
+		t.Laps, t.LapsWritten = nil, 0
+	}
+}
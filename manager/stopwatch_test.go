@@ -0,0 +1,86 @@
+package manager
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"multi-timer/config"
+	"multi-timer/timer"
+)
+
+func TestStopwatchToCountdown(t *testing.T) {
+	tm, _ := newTestManager(t)
+	stopwatch := timer.NewStopwatch("Essay")
+	tm.Lock()
+	tm.ActiveTimers = append(tm.ActiveTimers, stopwatch)
+	tm.Unlock()
+	advance(tm, 23*time.Minute+20*time.Second)
+
+	tm.Lock()
+	cfg := stopwatch.CountdownConfig("Essay draft")
+	tm.Unlock()
+	want := config.TimerConfig{
+		Name:      "Essay draft",
+		NotifText: "Essay draft",
+		Type:      config.TypeCountdown,
+		Phases:    []config.TimerPhase{{WorkDuration: 23 * time.Minute}},
+		MaxCycles: 1,
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("config = %+v, want %+v", cfg, want)
+	}
+
+	// It's a countdown of the time that was on the stopwatch
+	countdown := timer.TimerFromConfig(cfg)
+	if countdown.IsStopwatch() || countdown.CountUp || countdown.State.CurrentTime != 23*time.Minute {
+		t.Errorf("timer counts up %t from %v, want a 23m countdown", countdown.CountUp, countdown.State.CurrentTime)
+	}
+}
+
+func TestToggleDirectionMidSegment(t *testing.T) {
+	tm, _ := newTestManager(t)
+	timers := addTimers(tm, pomodoro("Call", 25*time.Minute, 5*time.Minute, 4))
+	tr := timers[0]
+	advance(tm, 10*time.Minute)
+
+	tm.Lock()
+	if !tr.ToggleDirection() {
+		t.Fatal("couldn't flip a running countdown")
+	}
+	if !tr.CountUp || tr.State.CurrentTime != 10*time.Minute {
+		t.Errorf("flipped to counting up %t from %v, want up from the 10m spent", tr.CountUp, tr.State.CurrentTime)
+	}
+	tm.Unlock()
+
+	advance(tm, 5*time.Minute)
+	tm.Lock()
+	tr.ToggleDirection()
+	if tr.CountUp || tr.State.CurrentTime != 10*time.Minute || !tr.State.IsWork {
+		t.Errorf("flipped back to counting up %t with %v of work %t left, want 10m of work left",
+			tr.CountUp, tr.State.CurrentTime, tr.State.IsWork)
+	}
+	tm.Unlock()
+
+	// An overrun counts on past the segment's length and leaves nothing
+	// remaining when flipped back, so the break starts at the next tick
+	tm.Lock()
+	tr.ToggleDirection()
+	tm.Unlock()
+	advance(tm, 20*time.Minute)
+	tm.Lock()
+	if got := tr.State.CurrentTime; got != 35*time.Minute || !tr.State.IsWork {
+		t.Errorf("counted up to %v, want an overrun to 35m of work", got)
+	}
+	tr.ToggleDirection()
+	if tr.CountUp || tr.State.CurrentTime != 0 {
+		t.Errorf("flipped back after the overrun with %v left, want none", tr.State.CurrentTime)
+	}
+	tm.Unlock()
+	advance(tm, timer.TickInterval)
+	tm.Lock()
+	defer tm.Unlock()
+	if tr.State.IsWork {
+		t.Error("the break didn't start after flipping back from an overrun")
+	}
+}
@@ -0,0 +1,81 @@
+package manager
+
+import (
+	"fmt"
+
+	"multi-timer/config"
+	"multi-timer/timer"
+)
+
+// PairConfigs matches each active timer with its saved config by name, in
+// order, so timers sharing a name still get one config each. Timers without a
+// config get -1. Must be called with tm.mu held.
+func (tm *TimerManager) PairConfigs() []int {
+	return pairTimers(tm.ActiveTimers, tm.Configs)
+}
+
+// pairTimers is PairConfigs for any list of configs
+func pairTimers(timers []*timer.Timer, configs []config.TimerConfig) []int {
+	used := make([]bool, len(configs))
+	pairs := make([]int, len(timers))
+	for i, timer := range timers {
+		pairs[i] = -1
+		if timer.Ephemeral {
+			continue
+		}
+		for j, config := range configs {
+			if !used[j] && config.Name == timer.State.Name {
+				used[j] = true
+				pairs[i] = j
+				break
+			}
+		}
+	}
+	return pairs
+}
+
+// SyncConfigs repairs timers and configs that drifted apart and returns a
+// line for every problem it fixed. Must be called with tm.mu held.
+func (tm *TimerManager) SyncConfigs() []string {
+	var report []string
+	pairs := tm.PairConfigs()
+	used := make([]bool, len(tm.Configs))
+
+	for i, timer := range tm.ActiveTimers {
+		j := pairs[i]
+		if timer.Ephemeral {
+			continue
+		}
+		if j < 0 {
+			tm.Configs = append(tm.Configs, timer.Config())
+			used = append(used, true)
+			report = append(report, fmt.Sprintf("%s: had no saved config, saved it", timer.State.Name))
+			continue
+		}
+		used[j] = true
+		if !timer.Matches(tm.Configs[j]) {
+			timer.ApplyConfig(tm.Configs[j])
+			report = append(report, fmt.Sprintf("%s: differed from its saved config, reloaded it", timer.State.Name))
+		}
+	}
+
+	// A config without a timer is expected for scheduled and completed timers,
+	// anything else belonged to a timer that was deleted
+	completed := make(map[string]int)
+	for name, n := range tm.completed {
+		completed[name] = n
+	}
+	configs := make([]config.TimerConfig, 0, len(tm.Configs))
+	for j, config := range tm.Configs {
+		if !used[j] && config.StartAt == "" {
+			if completed[config.Name] == 0 {
+				report = append(report, fmt.Sprintf("%s: saved config had no timer, removed it", config.Name))
+				continue
+			}
+			completed[config.Name]--
+		}
+		configs = append(configs, config)
+	}
+	tm.Configs = configs
+	return report
+}
@@ -0,0 +1,68 @@
+package manager
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"multi-timer/config"
+	"multi-timer/timer"
+)
+
+func TestSyncRepairsDrift(t *testing.T) {
+	tm, _ := newTestManager(t)
+	timers := addTimers(tm,
+		pomodoro("Tea", 25*time.Minute, 5*time.Minute, 4),
+		pomodoro("Read", 30*time.Minute, 5*time.Minute, 4),
+		pomodoro("Walk", 10*time.Minute, 2*time.Minute, 4),
+	)
+
+	// What deleting by index used to do: Tea lost its config, Walk its timer
+	// and Read doesn't notify with what its config says
+	tm.Lock()
+	tm.Configs = slices.Delete(tm.Configs, 0, 1)
+	tm.ActiveTimers = slices.DeleteFunc(tm.ActiveTimers, func(t *timer.Timer) bool { return t == timers[2] })
+	timers[1].State.NotifText = "stale"
+	report := tm.SyncConfigs()
+	tm.Unlock()
+
+	want := []string{
+		"Tea: had no saved config, saved it",
+		"Read: differed from its saved config, reloaded it",
+		"Walk: saved config had no timer, removed it",
+	}
+	if !slices.Equal(report, want) {
+		t.Errorf("report:\n%q\nwant:\n%q", report, want)
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+	for i, j := range tm.PairConfigs() {
+		if j < 0 || !tm.ActiveTimers[i].Matches(tm.Configs[j]) {
+			t.Errorf("%s is still out of sync", tm.ActiveTimers[i].State.Name)
+		}
+	}
+	if len(tm.Configs) != 2 {
+		t.Errorf("%d configs left, want 2", len(tm.Configs))
+	}
+	if got := timers[1].State.NotifText; got == "stale" {
+		t.Errorf("Read still notifies with %q", got)
+	}
+	if report := tm.SyncConfigs(); len(report) != 0 {
+		t.Errorf("a second sync still fixed %q", report)
+	}
+}
+
+// Completed and scheduled timers have a config but no timer, that's no drift
+func TestSyncKeepsCompletedAndScheduled(t *testing.T) {
+	tm, _ := newTestManager(t)
+	scheduled := pomodoro("Standup", 15*time.Minute, 0, 1)
+	scheduled.StartAt = "09:30"
+	tm.Lock()
+	defer tm.Unlock()
+	tm.Configs = []config.TimerConfig{pomodoro("Done", 25*time.Minute, 5*time.Minute, 1), scheduled}
+	tm.completed["Done"] = 1
+	if report := tm.SyncConfigs(); len(report) != 0 || len(tm.Configs) != 2 {
+		t.Errorf("sync fixed %q and left %d configs, want nothing fixed", report, len(tm.Configs))
+	}
+}
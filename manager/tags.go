@@ -0,0 +1,43 @@
+package manager
+
+import "multi-timer/timer"
+
+// Shown reports whether the timer passes the display filter. Must be called
+// with tm.mu held.
+func (tm *TimerManager) Shown(t *timer.Timer) bool {
+	return tm.Filter == "" || t.HasTag(tm.Filter)
+}
+
+// Hidden counts the timers the display filter leaves out. Must be called with
+// tm.mu held.
+func (tm *TimerManager) Hidden() int {
+	n := 0
+	for _, timer := range tm.ActiveTimers {
+		if !tm.Shown(timer) {
+			n++
+		}
+	}
+	return n
+}
+
+// PauseTagged pauses the running timers carrying tag, or with pause false
+// resumes the paused ones, and returns the names of those it changed. Deep
+// work cycles aren't paused and a timer isn't resumed while another of its
+// exclusive group runs. Must be called with tm.mu held.
+func (tm *TimerManager) PauseTagged(tag string, pause bool) []string {
+	var names []string
+	for _, timer := range tm.ActiveTimers {
+		if !timer.HasTag(tag) || timer.IsPaused == pause {
+			continue
+		}
+		if pause && !timer.Pauseable() {
+			continue
+		}
+		if !pause && tm.GroupRunning(timer) {
+			continue
+		}
+		timer.IsPaused = pause
+		names = append(names, timer.State.Name)
+	}
+	return names
+}
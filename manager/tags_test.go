@@ -0,0 +1,51 @@
+package manager
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"multi-timer/config"
+)
+
+func TestPauseTaggedOnly(t *testing.T) {
+	tm, _ := newTestManager(t)
+	tagged := func(name string, tags ...string) config.TimerConfig {
+		cfg := pomodoro(name, 25*time.Minute, 5*time.Minute, 4)
+		cfg.Tags = tags
+		return cfg
+	}
+	addTimers(tm,
+		tagged("Tea", "study"),
+		tagged("Read", "study", "deep"),
+		tagged("Walk", "outside"),
+		tagged("Stretch"),
+	)
+	advance(tm, time.Minute)
+
+	tm.Lock()
+	names := tm.PauseTagged("study", true)
+	paused := pausedNames(tm)
+	tm.Unlock()
+	if want := []string{"Tea", "Read"}; !slices.Equal(names, want) || !slices.Equal(paused, want) {
+		t.Errorf("pause-tag study paused %v (now paused %v), want %v", names, paused, want)
+	}
+
+	advance(tm, time.Minute)
+	tm.Lock()
+	for _, timer := range tm.ActiveTimers {
+		want := 23 * time.Minute
+		if timer.HasTag("study") {
+			want = 24 * time.Minute
+		}
+		if got := timer.State.CurrentTime; got != want {
+			t.Errorf("%s has %v left, want %v", timer.State.Name, got, want)
+		}
+	}
+	names = tm.PauseTagged("deep", false)
+	paused = pausedNames(tm)
+	tm.Unlock()
+	if !slices.Equal(names, []string{"Read"}) || !slices.Equal(paused, []string{"Tea"}) {
+		t.Errorf("resume-tag deep resumed %v (still paused %v), want only Read resumed", names, paused)
+	}
+}
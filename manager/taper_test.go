@@ -0,0 +1,53 @@
+package manager
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestTaperAcrossCycles(t *testing.T) {
+	tm, _ := newTestManager(t)
+	config := pomodoro("Taper", 10*time.Minute, 2*time.Minute, 5)
+	config.TaperWorkDuration = 2 * time.Minute
+	config.TaperBreakDuration = time.Minute
+	config.TaperMinWorkDuration = 5 * time.Minute
+	config.TaperMaxBreakDuration = 4 * time.Minute
+	timers := addTimers(tm, config)
+
+	// The length of every segment, read as it starts
+	var work, rest []time.Duration
+	segment := func() (int, bool, bool) {
+		tm.Lock()
+		defer tm.Unlock()
+		return timers[0].State.Cycles, timers[0].State.IsWork, len(tm.ActiveTimers) > 0
+	}
+	cycle, isWork := 0, false
+	for {
+		advance(tm, time.Second)
+		c, w, running := segment()
+		if !running {
+			break
+		}
+		if c == cycle && w == isWork {
+			continue
+		}
+		cycle, isWork = c, w
+		tm.Lock()
+		length := timers[0].SegmentDuration()
+		tm.Unlock()
+		if isWork {
+			work = append(work, length)
+		} else {
+			rest = append(rest, length)
+		}
+	}
+
+	m := time.Minute
+	if want := []time.Duration{10 * m, 8 * m, 6 * m, 5 * m, 5 * m}; !slices.Equal(work, want) {
+		t.Errorf("work by cycle = %v, want %v", work, want)
+	}
+	if want := []time.Duration{2 * m, 3 * m, 4 * m, 4 * m, 4 * m}; !slices.Equal(rest, want) {
+		t.Errorf("breaks by cycle = %v, want %v", rest, want)
+	}
+}
@@ -0,0 +1,157 @@
+package manager
+
+import (
+	"bytes"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"multi-timer/config"
+	"multi-timer/timer"
+)
+
+// How long to wait for a burst of write events to settle before reloading
+const reloadDelay = 200 * time.Millisecond
+
+// WatchConfigFile reloads the active config file whenever it changes on disk
+func (tm *TimerManager) WatchConfigFile() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	tm.mu.Lock()
+	// Watch the directory so editors that save by replacing the file are still seen
+	err = watcher.Add(filepath.Dir(tm.ConfigPath))
+	if err == nil {
+		tm.watcher = watcher
+	}
+	tm.mu.Unlock()
+	if err != nil {
+		watcher.Close()
+		return err
+	}
+
+	go func() {
+		defer watcher.Close()
+		var reload <-chan time.Time
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				tm.mu.Lock()
+				path := tm.ConfigPath
+				tm.mu.Unlock()
+				if filepath.Clean(event.Name) != filepath.Clean(path) {
+					continue
+				}
+				if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) != 0 {
+					reload = time.After(reloadDelay)
+				}
+			case <-reload:
+				reload = nil
+				tm.reloadConfigs()
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				tm.mu.Lock()
+				tm.ReportError("Error watching timer configurations", err)
+				tm.mu.Unlock()
+				tm.redraw()
+			}
+		}
+	}()
+	return nil
+}
+
+func (tm *TimerManager) reloadConfigs() {
+	tm.mu.Lock()
+	path := tm.ConfigPath
+	tm.mu.Unlock()
+
+	configs, err := config.LoadTimerConfigs(path)
+	if err != nil {
+		// Usually a half-written file, the next write event will retry
+		return
+	}
+
+	tm.Lock()
+	// The profile may have been switched while we were reading
+	changed := path == tm.ConfigPath && tm.reconcile(configs)
+	tm.Unlock()
+
+	if changed {
+		select {
+		case tm.DisplayChan <- true:
+		default:
+		}
+	}
+}
+
+// reconcile replaces the saved configs and brings the active timers in line:
+// timers for new configs are started, timers whose config is gone are removed
+// and the rest pick up any edits. Must be called with tm.mu held.
+func (tm *TimerManager) reconcile(configs []config.TimerConfig) bool {
+	// Our own saves end up here too, they match what we already have
+	if tm.savesAs(configs) {
+		return false
+	}
+
+	// How many configs of each name we had, a name saved twice is two timers
+	known := make(map[string]int)
+	for _, config := range tm.Configs {
+		known[config.Name]++
+	}
+
+	timers := make([]*timer.Timer, 0, len(configs))
+	running := make([]bool, len(configs))
+	for i, j := range pairTimers(tm.ActiveTimers, configs) {
+		timer := tm.ActiveTimers[i]
+		if timer.Ephemeral {
+			timers = append(timers, timer)
+			continue
+		}
+		if j < 0 {
+			continue
+		}
+		timer.ApplyConfig(configs[j])
+		timers = append(timers, timer)
+		running[j] = true
+	}
+
+	tm.ActiveTimers = timers
+
+	// Configs we already knew about without a running timer have completed,
+	// only brand new ones get started
+	seen := make(map[string]int)
+	for j, config := range configs {
+		seen[config.Name]++
+		if seen[config.Name] <= known[config.Name] || running[j] || len(config.Phases) == 0 || config.StartAt != "" {
+			continue
+		}
+		t := timer.TimerFromConfig(config)
+		tm.ActiveTimers = append(tm.ActiveTimers, t)
+		if tm.GroupRunning(t) {
+			t.IsPaused = true
+		}
+	}
+
+	tm.Configs = configs
+	return true
+}
+
+// savesAs reports whether the configs would be saved with the same content as
+// the current ones. Comparing the values instead would tell recorded times
+// apart by their location and monotonic clock reading. Must be called with
+// tm.mu held.
+func (tm *TimerManager) savesAs(configs []config.TimerConfig) bool {
+	ours, err := config.MarshalTimerConfigs(tm.ConfigPath, tm.Configs)
+	if err != nil {
+		return false
+	}
+	theirs, err := config.MarshalTimerConfigs(tm.ConfigPath, configs)
+	return err == nil && bytes.Equal(ours, theirs)
+}
@@ -0,0 +1,130 @@
+package manager
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"multi-timer/config"
+)
+
+// waitFor polls cond until it holds, failing the test after a few seconds
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestWatchReconcilesExternalEdit(t *testing.T) {
+	tm, _ := newTestManager(t)
+	timers := addTimers(tm,
+		pomodoro("Tea", 25*time.Minute, 5*time.Minute, 4),
+		pomodoro("Read", 30*time.Minute, 5*time.Minute, 4),
+		pomodoro("Walk", 10*time.Minute, 2*time.Minute, 4),
+	)
+	if err := config.SaveTimerConfigs(tm.ConfigPath, tm.Configs); err != nil {
+		t.Fatal(err)
+	}
+	advance(tm, 3*time.Minute)
+
+	if err := tm.WatchConfigFile(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { tm.watcher.Close() })
+
+	// Tea gets shorter, Walk is deleted and Stretch is new
+	edited := []config.TimerConfig{
+		pomodoro("Tea", 20*time.Minute, 5*time.Minute, 4),
+		pomodoro("Read", 30*time.Minute, 5*time.Minute, 4),
+		pomodoro("Stretch", 5*time.Minute, time.Minute, 2),
+	}
+	if err := config.SaveTimerConfigs(tm.ConfigPath, edited); err != nil {
+		t.Fatal(err)
+	}
+
+	names := func() []string {
+		tm.Lock()
+		defer tm.Unlock()
+		var names []string
+		for _, timer := range tm.ActiveTimers {
+			names = append(names, timer.State.Name)
+		}
+		return names
+	}
+	waitFor(t, "the reload", func() bool { return slices.Contains(names(), "Stretch") })
+
+	if got, want := names(), []string{"Tea", "Read", "Stretch"}; !slices.Equal(got, want) {
+		t.Fatalf("timers = %v, want %v", got, want)
+	}
+	tm.Lock()
+	defer tm.Unlock()
+	if tm.ActiveTimers[0] != timers[0] || tm.ActiveTimers[1] != timers[1] {
+		t.Fatal("the edited and unchanged timers were replaced instead of updated")
+	}
+	// 22 minutes were left of Tea's work, more than the new length
+	if got := timers[0].State.CurrentTime; got != 20*time.Minute {
+		t.Errorf("Tea has %v left, want 20m", got)
+	}
+	if got := timers[1].State.CurrentTime; got != 27*time.Minute {
+		t.Errorf("Read has %v left, want it to carry on at 27m", got)
+	}
+	if got := tm.ActiveTimers[2].State.CurrentTime; got != 5*time.Minute {
+		t.Errorf("Stretch has %v left, want a fresh 5m", got)
+	}
+	if !slices.EqualFunc(tm.Configs, edited, func(a, b config.TimerConfig) bool { return a.Name == b.Name }) {
+		t.Errorf("configs = %v, want the edited ones", tm.Configs)
+	}
+}
+
+func TestReconcileIgnoresOwnSave(t *testing.T) {
+	tm, _ := newTestManager(t)
+	addTimers(tm, pomodoro("Tea", 25*time.Minute, 5*time.Minute, 4))
+	if err := config.SaveTimerConfigs(tm.ConfigPath, tm.Configs); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := config.LoadTimerConfigs(tm.ConfigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tm.Lock()
+	defer tm.Unlock()
+	if tm.reconcile(saved) {
+		t.Error("reloading the configs the app just saved changed the timers")
+	}
+}
+
+func TestReconcileIgnoresOwnSaveWithCompletions(t *testing.T) {
+	for _, ext := range []string{".json", ".yaml", ".toml"} {
+		t.Run(ext, func(t *testing.T) {
+			tm, _ := newTestManager(t)
+			tm.ConfigPath = filepath.Join(t.TempDir(), "timers"+ext)
+			// Completions are recorded on the wall clock
+			tm.Now = time.Now
+			timers := addTimers(tm, pomodoro("Tea", time.Minute, time.Minute, 4))
+			advance(tm, 2*time.Minute)
+			tm.saveCompletions()
+
+			saved, err := config.LoadTimerConfigs(tm.ConfigPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tm.Lock()
+			defer tm.Unlock()
+			if len(saved) != 1 || len(saved[0].RecentCompletions) != 1 {
+				t.Fatalf("saved %+v, want Tea with one completion", saved)
+			}
+			if tm.reconcile(saved) {
+				t.Error("reloading the completion the timer just saved changed the timers")
+			}
+			if len(tm.ActiveTimers) != 1 || tm.ActiveTimers[0] != timers[0] {
+				t.Error("the timer was replaced by its own save")
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package manager
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"multi-timer/timer"
+)
+
+const webhookTimeout = 5 * time.Second
+
+var webhookClient = &http.Client{Timeout: webhookTimeout}
+
+// WebhookPayload is the JSON body posted to webhooks
+type WebhookPayload struct {
+	Event string // work-start, break-start or completed
+	Timer string
+	Phase int `json:",omitempty"` // 1-based like the display
+	Cycle int `json:",omitempty"`
+	Time  time.Time
+}
+
+// callWebhooks posts the payload to the global webhooks and those of the
+// timer. The requests run in the background, failures are shown under the
+// timers. Must be called with tm.mu held.
+func (tm *TimerManager) callWebhooks(t *timer.Timer, payload WebhookPayload) {
+	urls := append(append([]string(nil), tm.Prefs.Webhooks...), t.Webhooks...)
+	if len(urls) == 0 {
+		return
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		tm.ReportError("Error encoding webhook payload", err)
+		return
+	}
+	for _, url := range urls {
+		go func(url string) {
+			if err := postWebhook(url, body); err != nil {
+				tm.mu.Lock()
+				tm.ReportError("Error calling webhook", err)
+				tm.mu.Unlock()
+				tm.redraw()
+			}
+		}(url)
+	}
+}
+
+func postWebhook(url string, body []byte) error {
+	resp, err := webhookClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s answered %s", url, resp.Status)
+	}
+	return nil
+}
+
+// CheckWebhook rejects URLs that can't be posted to
+func CheckWebhook(hook string) error {
+	u, err := url.Parse(hook)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%q is not an http or https URL", hook)
+	}
+	return nil
+}
+
+// TimerWebhooks lists the webhooks of timer num after carrying out the
+// optional add or remove of a URL, which is saved with the timer's config
+func (tm *TimerManager) TimerWebhooks(num int, change ...string) ([]string, error) {
+	if len(change) == 2 && change[0] == "add" {
+		if err := CheckWebhook(change[1]); err != nil {
+			return nil, err
+		}
+	}
+	tm.Lock()
+	if num < 1 || num > len(tm.ActiveTimers) {
+		tm.Unlock()
+		return nil, fmt.Errorf("no timer %d", num)
+	}
+	timer := tm.ActiveTimers[num-1]
+	if len(change) != 2 {
+		hooks := timer.Webhooks
+		tm.Unlock()
+		return hooks, nil
+	}
+
+	hooks := slices.DeleteFunc(slices.Clone(timer.Webhooks), func(url string) bool { return url == change[1] })
+	if change[0] == "add" {
+		hooks = append(hooks, change[1])
+	}
+	if len(hooks) == 0 {
+		hooks = nil
+	}
+	timer.Webhooks = hooks
+	j := tm.PairConfigs()[num-1]
+	if j >= 0 {
+		tm.Configs[j].Webhooks = hooks
+	}
+	tm.Unlock()
+	if j < 0 {
+		return hooks, nil
+	}
+	return hooks, tm.SaveConfigs()
+}
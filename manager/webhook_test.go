@@ -0,0 +1,121 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// webhookServer records the payloads posted to it, by path
+type webhookServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	payloads map[string][]WebhookPayload
+}
+
+func newWebhookServer(t *testing.T, status int) *webhookServer {
+	s := &webhookServer{payloads: make(map[string][]WebhookPayload)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload WebhookPayload
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("webhook called with %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("decoding the payload: %v", err)
+		}
+		s.mu.Lock()
+		s.payloads[r.URL.Path] = append(s.payloads[r.URL.Path], payload)
+		s.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// received waits for n payloads on path and returns them in time order
+func (s *webhookServer) received(t *testing.T, path string, n int) []WebhookPayload {
+	t.Helper()
+	count := func() int {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.payloads[path])
+	}
+	waitFor(t, fmt.Sprintf("%d payloads on %s", n, path), func() bool { return count() >= n })
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	payloads := slices.Clone(s.payloads[path])
+	slices.SortStableFunc(payloads, func(a, b WebhookPayload) int { return a.Time.Compare(b.Time) })
+	return payloads
+}
+
+func TestWebhookPayloads(t *testing.T) {
+	server := newWebhookServer(t, http.StatusOK)
+	tm, _ := newTestManager(t)
+	tm.Prefs.Webhooks = []string{server.URL + "/global"}
+	tea := pomodoro("Tea", time.Minute, time.Minute, 2)
+	tea.Webhooks = []string{server.URL + "/tea"}
+	addTimers(tm, tea, pomodoro("Read", 10*time.Minute, time.Minute, 1))
+	advance(tm, 5*time.Minute)
+
+	at := func(minutes int) time.Time { return time.Date(2024, 3, 4, 9, minutes, 0, 0, time.UTC) }
+	want := []WebhookPayload{
+		// The first tick starts the timers
+		{Event: "work-start", Timer: "Tea", Phase: 1, Cycle: 1, Time: at(0).Add(time.Second)},
+		{Event: "break-start", Timer: "Tea", Phase: 1, Cycle: 1, Time: at(1)},
+		{Event: "work-start", Timer: "Tea", Phase: 1, Cycle: 2, Time: at(2)},
+		{Event: "break-start", Timer: "Tea", Phase: 1, Cycle: 2, Time: at(3)},
+		{Event: "completed", Timer: "Tea", Time: at(4)},
+	}
+	equal := func(a, b WebhookPayload) bool {
+		return a.Event == b.Event && a.Timer == b.Timer && a.Phase == b.Phase && a.Cycle == b.Cycle && a.Time.Equal(b.Time)
+	}
+	if got := server.received(t, "/tea", len(want)); !slices.EqualFunc(got, want, equal) {
+		t.Errorf("Tea's webhook got %+v\nwant %+v", got, want)
+	}
+
+	// The global webhook hears of both timers
+	global := server.received(t, "/global", len(want)+1)
+	var teaPayloads []WebhookPayload
+	for _, payload := range global {
+		if payload.Timer == "Tea" {
+			teaPayloads = append(teaPayloads, payload)
+		}
+	}
+	if !slices.EqualFunc(teaPayloads, want, equal) {
+		t.Errorf("the global webhook got %+v for Tea, want %+v", teaPayloads, want)
+	}
+	read := slices.IndexFunc(global, func(p WebhookPayload) bool { return p.Timer == "Read" })
+	if read < 0 || global[read].Event != "work-start" {
+		t.Errorf("the global webhook got %+v, want Read's start too", global)
+	}
+}
+
+func TestWebhookFailureReported(t *testing.T) {
+	server := newWebhookServer(t, http.StatusInternalServerError)
+	tm, _ := newTestManager(t)
+	tm.Prefs.Webhooks = []string{server.URL + "/down"}
+	addTimers(tm, pomodoro("Tea", time.Minute, time.Minute, 1))
+	advance(tm, 30*time.Second)
+
+	server.received(t, "/down", 1)
+	lastError := func() string {
+		tm.mu.Lock()
+		defer tm.mu.Unlock()
+		return tm.lastError
+	}
+	waitFor(t, "the error", func() bool { return lastError() != "" })
+	if got := lastError(); !strings.Contains(got, "Error calling webhook") || !strings.Contains(got, "500") {
+		t.Errorf("lastError = %q, want the webhook's 500", got)
+	}
+	tm.Lock()
+	defer tm.Unlock()
+	if len(tm.ActiveTimers) != 1 {
+		t.Error("a failing webhook stopped the timer")
+	}
+}
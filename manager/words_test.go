@@ -0,0 +1,22 @@
+package manager
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderWords(t *testing.T) {
+	tm, _ := newTestManager(t)
+	timers := addTimers(tm, pomodoro("Tea", 25*time.Minute, 5*time.Minute, 4))
+	advance(tm, 20*time.Minute)
+	tm.Lock()
+	defer tm.Unlock()
+	got := timers[0].RenderWords()
+	if want := "Tea - five minutes remaining in "; !strings.HasPrefix(got, want) {
+		t.Errorf("RenderWords() = %q, want it to start %q", got, want)
+	}
+	if want := "cycle one of four, phase one of one"; !strings.HasSuffix(got, want) {
+		t.Errorf("RenderWords() = %q, want it to end %q", got, want)
+	}
+}
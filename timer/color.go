@@ -0,0 +1,19 @@
+package timer
+
+import "time"
+
+// EndingSoon reports whether the timer is in the last minute of a segment
+// that counts down
+func (t *Timer) EndingSoon() bool {
+	return !t.IsStopwatch() && !t.CountUp && !t.OnOpenBreak() &&
+		t.State.CurrentTime > 0 && t.State.CurrentTime <= time.Minute
+}
+
+// PhaseColor is the color of the phase the timer is in, warmup and cooldown
+// have none
+func (t *Timer) PhaseColor() string {
+	if t.IsStopwatch() || t.State.Stage != StageCycles {
+		return ""
+	}
+	return t.Phases[t.State.CurrentPhase].PhaseColor
+}
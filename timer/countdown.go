@@ -0,0 +1,37 @@
+package timer
+
+import (
+	"fmt"
+	"time"
+
+	"multi-timer/config"
+)
+
+// oneShot reports whether the timer completes when its first segment runs out
+func (t *Timer) oneShot() bool {
+	return t.Kind == config.TypeCountdown || t.Kind == config.TypeAlarm
+}
+
+// finishOneShot ends a countdown or alarm whose time ran out
+func (t *Timer) finishOneShot() bool {
+	t.EndSegment()
+	t.Stats.CompletedCycles++
+	if t.Kind == config.TypeAlarm {
+		t.notify(fmt.Sprintf("Alarm %s: %s", t.alarmAt, t.State.NotifText))
+	} else {
+		t.notify(fmt.Sprintf("Time's up: %s", t.State.NotifText))
+	}
+	return true
+}
+
+func (t *Timer) renderOneShot(shown time.Duration) string {
+	label := "Countdown"
+	if t.Kind == config.TypeAlarm {
+		label = "Alarm " + t.alarmAt
+	}
+	status := ""
+	if t.CountUp {
+		status = " (counting up)"
+	}
+	return fmt.Sprintf("%s - %s%s: %s", t.State.Name, label, status, ClockTime(shown))
+}
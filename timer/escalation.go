@@ -0,0 +1,31 @@
+package timer
+
+import "time"
+
+// Escalation repeats a timer's notification as critical every after until it
+// is acknowledged, at most repeats times
+type Escalation struct {
+	After   time.Duration
+	Repeats int
+}
+
+// Enabled reports whether unanswered notifications are repeated at all
+func (e Escalation) Enabled() bool {
+	return e.After > 0 && e.Repeats > 0
+}
+
+// PendingAlert is a notification repeated until it is acknowledged
+type PendingAlert struct {
+	Title   string
+	Message string
+	Due     time.Time
+	Sent    int // critical repeats so far
+}
+
+// Acknowledge stops the escalation of a timer's notification and reports
+// whether one was pending
+func (t *Timer) Acknowledge() bool {
+	pending := t.Alert != nil
+	t.Alert = nil
+	return pending
+}
@@ -0,0 +1,65 @@
+package timer
+
+import "time"
+
+// EventKind is what sort of thing happened in a TimerEvent
+type EventKind int
+
+const (
+	PhaseCompleted EventKind = iota
+	Notification
+	SegmentEnded
+	CycleCompleted
+)
+
+// TimerEvent is something that happened during an update which the manager
+// handles once the update is done
+type TimerEvent struct {
+	Kind    EventKind
+	Segment string
+	Phase   int
+	Cycle   int
+	Message string
+	// A work or break segment started, see TextOverride
+	Announce bool
+	// The segment that ended was cut short, see skip
+	Interrupted bool
+	// How long the segment that ended ran, see Timer.active
+	Active time.Duration
+}
+
+// Events are what happened to the timer since the events were last cleared
+func (t *Timer) Events() []TimerEvent {
+	return t.events
+}
+
+// ClearEvents drops the events once the manager has handled them
+func (t *Timer) ClearEvents() {
+	t.events = t.events[:0]
+}
+
+// interruptFrom marks the first segment to end from event n on as cut short,
+// the segments that ended after it ran their course
+func (t *Timer) interruptFrom(n int) {
+	for i := n; i < len(t.events); i++ {
+		if t.events[i].Kind == SegmentEnded {
+			t.events[i].Interrupted = true
+			return
+		}
+	}
+}
+
+// SegmentSound is the sound for the start of the event's segment, the
+// phase's own or else the timer's for work or breaks
+func (t *Timer) SegmentSound(event TimerEvent) string {
+	if sound := t.Phases[event.Phase].PhaseSound; sound != "" {
+		return sound
+	}
+	switch event.Segment {
+	case "Work":
+		return t.workSound
+	case "Break":
+		return t.breakSound
+	}
+	return ""
+}
@@ -0,0 +1,38 @@
+package timer
+
+import "slices"
+
+// Fork returns a new ephemeral timer that carries on from where t is, with
+// the phases t has left. Slices and the snooze are copied rather than shared,
+// so the two evolve independently. The stopwatch laps and the history of the
+// current segment stay with t. The fork keeps the start of the segment, so
+// it isn't announced as a timer that just started.
+func (t *Timer) Fork() *Timer {
+	forked := *t
+	forked.State.Name = t.State.Name + " (fork)"
+	forked.Phases = slices.Clone(t.Phases)
+	// Later rounds of a cycle-major timer go through all phases again
+	if !t.cycleMajor {
+		forked.Phases = slices.Clone(t.Phases[t.State.CurrentPhase:])
+		forked.State.CurrentPhase = 0
+	}
+	forked.Chain = slices.Clone(t.Chain)
+	forked.milestones = slices.Clone(t.milestones)
+	forked.PausesOnWork = slices.Clone(t.PausesOnWork)
+	forked.protectedCycles = slices.Clone(t.protectedCycles)
+	forked.activities = slices.Clone(t.activities)
+	forked.Tags = slices.Clone(t.Tags)
+	forked.Webhooks = slices.Clone(t.Webhooks)
+	if t.Snoozed != nil {
+		snoozed := *t.Snoozed
+		forked.Snoozed = &snoozed
+	}
+	forked.Laps, forked.LapsWritten = nil, 0
+	forked.upcomingCache = upcomingTotals{} // the phases may have been cut
+	forked.Ephemeral = true
+	forked.events = nil
+	forked.Alert = nil
+	forked.Active = 0
+	forked.wake = 0 // not queued until the manager schedules it
+	return &forked
+}
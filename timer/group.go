@@ -0,0 +1,6 @@
+package timer
+
+// LinkedTo reports whether timer is in the linked group of t
+func (t *Timer) LinkedTo(timer *Timer) bool {
+	return t.linked != "" && timer.linked == t.linked
+}
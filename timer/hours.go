@@ -0,0 +1,48 @@
+package timer
+
+import (
+	"fmt"
+	"time"
+
+	"multi-timer/config"
+)
+
+// phaseActive reports whether the phase may run now, phases without active
+// hours always can
+func (t *Timer) phaseActive(phase int) bool {
+	window, err := config.ActiveWindow(t.Phases[phase].ActiveHours)
+	if err != nil || window.Start == "" {
+		return true
+	}
+	now := time.Now
+	if t.Clock != nil {
+		now = t.Clock
+	}
+	return window.Contains(now())
+}
+
+// skipInactive moves on from a phase that is outside its active hours until
+// one that isn't, or the timer completes. When no phase is active the timer
+// runs the one it was about to start rather than going round in circles.
+func (t *Timer) skipInactive(phase, cycle int) (int, int, bool) {
+	startPhase, startCycle := phase, cycle
+	for skipped := 0; skipped < len(t.Phases); skipped++ {
+		if t.phaseActive(phase) {
+			return phase, cycle, false
+		}
+		t.notify(fmt.Sprintf("Skipping phase %d outside its hours: %s", phase+1, t.State.NotifText))
+		var completed bool
+		if t.cycleMajor {
+			phase, cycle, _, completed = t.advance(phase, cycle)
+		} else {
+			phase, cycle, completed = phase+1, 1, phase+1 >= len(t.Phases)
+			if completed {
+				cycle = t.maxCycles + 1
+			}
+		}
+		if completed {
+			return phase, cycle, true
+		}
+	}
+	return startPhase, startCycle, false
+}
@@ -0,0 +1,27 @@
+package timer
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"multi-timer/config"
+)
+
+func TestTotalRemainingSaturates(t *testing.T) {
+	cfg := pomodoro("Huge", config.MaxDuration, config.MaxDuration, config.MaxCycleCount)
+	cfg.WarmupDuration = config.MaxDuration
+	cfg.CooldownDuration = config.MaxDuration
+	timer := TimerFromConfig(cfg)
+	total, ok := timer.TotalRemaining()
+	if !ok || total != math.MaxInt64 {
+		t.Errorf("total remaining = %v, %t, want the largest duration", total, ok)
+	}
+	work, ok := timer.WorkRemaining()
+	if !ok || work != config.MaxDuration*config.MaxCycleCount {
+		t.Errorf("work remaining = %v, %t, want %v", work, ok, config.MaxDuration*config.MaxCycleCount)
+	}
+	if got := HoursMinutes(total); strings.HasPrefix(got, "-") {
+		t.Errorf("hoursMinutes of the largest duration = %q", got)
+	}
+}
@@ -0,0 +1,27 @@
+package timer
+
+import (
+	"fmt"
+	"time"
+)
+
+// checkMilestones notifies once for each milestone the current work segment
+// has passed. Segments shorter than the minimum length are skipped.
+func (t *Timer) checkMilestones() {
+	if len(t.milestones) == 0 || t.State.Stage != StageCycles || !t.State.IsWork || t.Snoozed != nil {
+		return
+	}
+	segment := t.SegmentDuration()
+	if segment <= 0 || segment < t.milestoneMin {
+		return
+	}
+	done := (segment - t.State.CurrentTime) * 100 / segment
+	reached := t.MilestoneReached
+	for _, percent := range t.milestones {
+		if percent > t.MilestoneReached && time.Duration(percent) <= done {
+			t.notify(fmt.Sprintf("%d%% through: %s", percent, t.State.NotifText))
+			reached = max(reached, percent)
+		}
+	}
+	t.MilestoneReached = reached
+}
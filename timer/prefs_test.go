@@ -0,0 +1,71 @@
+package timer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"multi-timer/config"
+)
+
+func TestRoundedRendering(t *testing.T) {
+	for _, tc := range []struct {
+		left time.Duration
+		step time.Duration
+		want string
+	}{
+		{24*time.Minute + 58*time.Second, 5 * time.Second, "25:00"},
+		{24*time.Minute + 57*time.Second, 5 * time.Second, "24:55"},
+		{24*time.Minute + 57*time.Second, 10 * time.Second, "25:00"},
+		{24*time.Minute + 54*time.Second, 10 * time.Second, "24:50"},
+		{24*time.Minute + 57*time.Second, 0, "24:57"},
+		{7 * time.Second, 5 * time.Second, "00:05"},
+		// Inside the last step the exact time shows, never 00:00 with time left
+		{4 * time.Second, 5 * time.Second, "00:04"},
+		{2 * time.Second, 10 * time.Second, "00:02"},
+		{time.Second, 5 * time.Second, "00:01"},
+	} {
+		timer := TimerFromConfig(pomodoro("Tea", 25*time.Minute, 5*time.Minute, 4))
+		timer.State.CurrentTime = tc.left
+		got := timer.Render(config.Preferences{DisplayRounding: tc.step})
+		if !strings.Contains(got, ": "+tc.want+" ") {
+			t.Errorf("%v left rounded to %v renders %q, want %s", tc.left, tc.step, got, tc.want)
+		}
+		if timer.State.CurrentTime != tc.left {
+			t.Errorf("rendering changed the time left from %v to %v", tc.left, timer.State.CurrentTime)
+		}
+	}
+}
+
+func TestCycleDots(t *testing.T) {
+	for _, tc := range []struct {
+		cycles, maxCycles int
+		want              string
+		ok                bool
+	}{
+		{1, 4, "●○○○", true},
+		{2, 4, "●●○○", true},
+		{4, 4, "●●●●", true},
+		{5, 4, "●●●●", true}, // past the last cycle once the timer completes
+		{1, 1, "●", true},
+		{12, 12, "●●●●●●●●●●●●", true},
+		{3, 13, "", false},
+		{3, -1, "●●●…", true},
+		{40, -1, "●●●●●●●●●●●●…", true},
+	} {
+		got, ok := config.CycleDots(tc.cycles, tc.maxCycles)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("CycleDots(%d, %d) = %q, %t, want %q, %t", tc.cycles, tc.maxCycles, got, ok, tc.want, tc.ok)
+		}
+	}
+
+	timer := TimerFromConfig(pomodoro("Tea", 25*time.Minute, 5*time.Minute, 4))
+	timer.State.Cycles = 2
+	if got := timer.Render(config.Preferences{CycleDots: true}); !strings.Contains(got, "(Cycle ●●○○)") {
+		t.Errorf("render with dots = %q", got)
+	}
+	timer = TimerFromConfig(pomodoro("Tea", 25*time.Minute, 5*time.Minute, 20))
+	if got := timer.Render(config.Preferences{CycleDots: true}); !strings.Contains(got, "(Cycle 1/20)") {
+		t.Errorf("render with too many cycles for dots = %q, want the numbers", got)
+	}
+}
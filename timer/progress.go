@@ -0,0 +1,102 @@
+package timer
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"multi-timer/config"
+)
+
+// Columns of the progress indicator shown next to each timer, at least
+// progressWidth and as much of the room the line leaves as maxProgressWidth
+const (
+	progressWidth    = 10
+	maxProgressWidth = 40
+)
+
+// Progress is how far through its current segment the timer is, from 0 to 1.
+// It's false for stopwatches and breaks that wait for resume, which have no end.
+func (t *Timer) Progress() (float64, bool) {
+	if t.IsStopwatch() || t.OnOpenBreak() {
+		return 0, false
+	}
+	total := t.SegmentDuration()
+	if total <= 0 {
+		return 1, true
+	}
+	done := total - t.State.CurrentTime
+	if t.CountUp {
+		done = t.State.CurrentTime
+	}
+	return math.Min(math.Max(float64(done)/float64(total), 0), 1), true
+}
+
+// asciiBar shows the part of the segment that's left as a bar that empties
+func asciiBar(progress float64, width int) string {
+	left := int(math.Round((1 - progress) * float64(width)))
+	return "[" + strings.Repeat("#", left) + strings.Repeat("-", width-left) + "]"
+}
+
+// Braille cells are two dots wide, these are the dots of each column
+const (
+	brailleBlank = '⠀'
+	brailleLeft  = 0x01 | 0x02 | 0x04 | 0x40
+	brailleRight = 0x08 | 0x10 | 0x20 | 0x80
+)
+
+// brailleBar is asciiBar with braille cells, which gives it twice the
+// resolution in the same width
+func brailleBar(progress float64, width int) string {
+	left := int(math.Round((1 - progress) * float64(width*2)))
+	var b strings.Builder
+	for i := 0; i < width; i++ {
+		cell := brailleBlank
+		if left > 2*i {
+			cell |= brailleLeft
+		}
+		if left > 2*i+1 {
+			cell |= brailleRight
+		}
+		b.WriteRune(cell)
+	}
+	return b.String()
+}
+
+// Block elements eighths of a cell wide, for the end of blockBar
+var blockEighths = []rune(" ▏▎▍▌▋▊▉")
+
+// blockBar fills up as the segment goes by, an eighth of a cell at a time
+func blockBar(progress float64, width int) string {
+	eighths := int(math.Round(progress * float64(width*8)))
+	full, part := eighths/8, eighths%8
+	var b strings.Builder
+	b.WriteString("[")
+	b.WriteString(strings.Repeat("█", full))
+	if part > 0 {
+		b.WriteRune(blockEighths[part])
+		full++
+	}
+	b.WriteString(strings.Repeat(" ", width-full))
+	b.WriteString("]")
+	return b.String()
+}
+
+// ProgressIndicator is the bar and percentage shown for the timer in the
+// display, or "". room is how many columns the bar could take.
+func (t *Timer) ProgressIndicator(prefs config.Preferences, room int) string {
+	progress, ok := t.Progress()
+	if !ok {
+		return ""
+	}
+	width := min(max(room, progressWidth), maxProgressWidth)
+	// Rounded down so 100% means done
+	percent := fmt.Sprintf(" %d%%", int(progress*100))
+	switch {
+	case prefs.Braille:
+		return brailleBar(progress, width) + percent
+	case prefs.ASCIIBars:
+		return asciiBar(progress, width) + percent
+	}
+	return blockBar(progress, width) + percent
+}
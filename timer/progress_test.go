@@ -0,0 +1,49 @@
+package timer
+
+import (
+	"testing"
+	"time"
+
+	"multi-timer/config"
+)
+
+func TestBrailleBar(t *testing.T) {
+	for _, tc := range []struct {
+		progress float64
+		want     string
+	}{
+		{0, "⣿⣿⣿⣿"},
+		{0.25, "⣿⣿⣿⠀"},
+		{0.5, "⣿⣿⠀⠀"},
+		{0.625, "⣿⡇⠀⠀"},
+		{0.9, "⡇⠀⠀⠀"},
+		{0.99, "⠀⠀⠀⠀"},
+		{1, "⠀⠀⠀⠀"},
+	} {
+		if got := brailleBar(tc.progress, 4); got != tc.want {
+			t.Errorf("brailleBar(%g) = %q, want %q", tc.progress, got, tc.want)
+		}
+	}
+}
+
+func TestProgressIndicator(t *testing.T) {
+	timer := TimerFromConfig(pomodoro("Tea", 20*time.Minute, 5*time.Minute, 4))
+	timer.State.CurrentTime = 15 * time.Minute
+	for _, tc := range []struct {
+		prefs config.Preferences
+		want  string
+	}{
+		{config.Preferences{Braille: true}, "⣿⣿⣿⣿⣿⣿⣿⡇⠀⠀ 25%"},
+		{config.Preferences{ASCIIBars: true}, "[########--] 25%"},
+		{config.Preferences{}, "[██▌       ] 25%"},
+	} {
+		if got := timer.ProgressIndicator(tc.prefs, 0); got != tc.want {
+			t.Errorf("indicator with %+v = %q, want %q", tc.prefs, got, tc.want)
+		}
+	}
+
+	stopwatch := NewStopwatch("Lap")
+	if got := stopwatch.ProgressIndicator(config.Preferences{Braille: true}, 0); got != "" {
+		t.Errorf("stopwatch indicator = %q, want none", got)
+	}
+}
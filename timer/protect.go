@@ -0,0 +1,15 @@
+package timer
+
+import "slices"
+
+// Protected reports whether the timer is in one of its deep work cycles,
+// which can't be paused, skipped or have their time changed
+func (t *Timer) Protected() bool {
+	return t.State.Stage == StageCycles && slices.Contains(t.protectedCycles, t.State.Cycles)
+}
+
+// Pauseable is false only for running timers in a deep work cycle, a paused
+// one can always be resumed
+func (t *Timer) Pauseable() bool {
+	return t.IsPaused || !t.Protected()
+}
@@ -0,0 +1,75 @@
+package timer
+
+import "time"
+
+// Wake is 1 + the index of the timer's entry in the manager's wakeups, 0 when
+// it isn't queued
+func (t *Timer) Wake() int {
+	return t.wake
+}
+
+// SetWake records where the timer's wakeup moved to in the manager's queue
+func (t *Timer) SetWake(wake int) {
+	t.wake = wake
+}
+
+// CaughtUp is the part of the time the manager saved up that the timer
+// already got
+func (t *Timer) CaughtUp() time.Duration {
+	return t.caughtUp
+}
+
+// CatchUp gives the timer the part of the pending time it hasn't had yet and
+// reports whether it completed
+func (t *Timer) CatchUp(pending time.Duration) bool {
+	elapsed := pending - t.caughtUp
+	t.caughtUp = pending
+	return t.Update(elapsed)
+}
+
+// Settle catches the timer up on the pending time, which the manager then
+// starts saving afresh
+func (t *Timer) Settle(pending time.Duration) {
+	t.CatchUp(pending)
+	t.caughtUp = 0
+}
+
+// NextBoundary is how much timer time can pass before the timer needs a full
+// update. It's false for timers that only change when someone acts on them:
+// paused ones, and stopwatches, count-ups and breaks that wait for resume
+// unless they have a reminder.
+func (t *Timer) NextBoundary() (time.Duration, bool) {
+	if t.started.IsZero() {
+		return 0, true // the first tick plays the start sound
+	}
+	if t.IsPaused {
+		return 0, false
+	}
+	reminder, reminds := t.untilReminder()
+	if t.CountUp || t.OnOpenBreak() {
+		return reminder, reminds
+	}
+	next := max(t.State.CurrentTime, 0)
+	if reminds {
+		next = min(next, reminder)
+	}
+	if len(t.milestones) == 0 || t.State.Stage != StageCycles || !t.State.IsWork || t.Snoozed != nil {
+		return next, true
+	}
+	segment := t.SegmentDuration()
+	if segment <= 0 || segment < t.milestoneMin {
+		return next, true
+	}
+	for _, percent := range t.milestones {
+		if percent <= t.MilestoneReached {
+			continue
+		}
+		// checkMilestones fires once (segment-CurrentTime)*100/segment >= percent
+		at := segment - (time.Duration(percent)*segment+99)/100
+		if t.State.CurrentTime > at {
+			next = min(next, t.State.CurrentTime-at)
+		}
+		break
+	}
+	return next, true
+}
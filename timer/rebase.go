@@ -0,0 +1,25 @@
+package timer
+
+import (
+	"time"
+
+	"multi-timer/config"
+)
+
+// Rebase changes the timer's base duration and recomputes its relative
+// phases, keeping its progress as far as the new lengths allow. It fails for
+// timers without relative phases.
+func (t *Timer) Rebase(base time.Duration) bool {
+	relative := false
+	for _, phase := range t.Phases {
+		relative = relative || phase.WorkScale > 0 || phase.BreakScale > 0
+	}
+	if !relative {
+		return false
+	}
+	cfg := t.Config()
+	cfg.BaseDuration = base
+	cfg.Phases = config.ResolvePhases(t.Phases, base)
+	t.ApplyConfig(cfg)
+	return true
+}
@@ -0,0 +1,158 @@
+package timer
+
+import (
+	"fmt"
+	"time"
+
+	"multi-timer/config"
+)
+
+// Segment is one work or break stretch of a timer's schedule, or its warmup
+// or cooldown
+type Segment struct {
+	Stage    TimerStage
+	Phase    int
+	Cycle    int
+	IsWork   bool
+	Duration time.Duration
+}
+
+// UpcomingSegments lists the segments that follow the current one until the
+// timer completes. Unlimited timers never complete so at most limit segments
+// are returned.
+func (t *Timer) UpcomingSegments(limit int) []Segment {
+	var segments []Segment
+	t.eachUpcoming(limit, func(seg Segment) {
+		segments = append(segments, seg)
+	})
+	return segments
+}
+
+// eachUpcoming calls do with the segments UpcomingSegments lists, without
+// collecting them
+func (t *Timer) eachUpcoming(limit int, do func(Segment)) {
+	if t.CountUp || t.State.Stage == StageCooldown {
+		return
+	}
+	n := 0
+	emit := func(seg Segment) {
+		do(seg)
+		n++
+	}
+	phase, cycle, isWork := t.State.CurrentPhase, t.State.Cycles, t.State.IsWork
+	if t.State.Stage == StageWarmup {
+		emit(Segment{StageCycles, 0, 1, true, t.workLength(0, 1)})
+	}
+	for n < limit {
+		if isWork {
+			isWork = false
+			emit(Segment{StageCycles, phase, cycle, false, t.breakLength(phase, cycle)})
+			continue
+		}
+		var completed bool
+		phase, cycle, _, completed = t.advance(phase, cycle)
+		if completed {
+			if t.cooldown > 0 {
+				emit(Segment{StageCooldown, len(t.Phases) - 1, 0, true, t.cooldown})
+			}
+			break
+		}
+		isWork = true
+		emit(Segment{StageCycles, phase, cycle, true, t.workLength(phase, cycle)})
+	}
+}
+
+// upcomingTotals caches the sums of the segments after the current one. They
+// only change when the timer moves to another segment or gets a new config,
+// so the header's redraw every second doesn't walk thousands of cycles.
+type upcomingTotals struct {
+	valid  bool
+	stage  TimerStage
+	phase  int
+	cycle  int
+	isWork bool
+
+	total time.Duration // every segment, or
+	open  bool          // one of them is a break that waits for resume
+	work  time.Duration // the work of the cycles alone
+}
+
+// upcoming returns the totals of the segments after the current one for a
+// timer that completes
+func (t *Timer) upcoming() upcomingTotals {
+	u := &t.upcomingCache
+	if u.valid && u.stage == t.State.Stage && u.phase == t.State.CurrentPhase && u.cycle == t.State.Cycles && u.isWork == t.State.IsWork {
+		return *u
+	}
+	*u = upcomingTotals{valid: true, stage: t.State.Stage, phase: t.State.CurrentPhase, cycle: t.State.Cycles, isWork: t.State.IsWork}
+	t.eachUpcoming(len(t.Phases)*(t.maxCycles+1)*2+2, func(seg Segment) {
+		if seg.Duration == config.UntilResumed {
+			u.open = true
+			return
+		}
+		u.total = config.AddDurations(u.total, seg.Duration)
+		if seg.Stage == StageCycles && seg.IsWork {
+			u.work = config.AddDurations(u.work, seg.Duration)
+		}
+	})
+	return *u
+}
+
+// TotalRemaining is the time until the timer completes. It's false for
+// timers that don't complete on their own: unlimited ones, stopwatches and
+// those with a break that waits for resume.
+func (t *Timer) TotalRemaining() (time.Duration, bool) {
+	if t.CountUp || t.maxCycles == -1 || t.OnOpenBreak() {
+		return 0, false
+	}
+	u := t.upcoming()
+	if u.open {
+		return 0, false
+	}
+	return config.AddDurations(t.State.CurrentTime, u.total), true
+}
+
+// WorkRemaining is the work time left until the timer completes, not
+// counting breaks, warmup or cooldown. It's false when TotalRemaining is.
+func (t *Timer) WorkRemaining() (time.Duration, bool) {
+	if _, ok := t.TotalRemaining(); !ok {
+		return 0, false
+	}
+	total := t.upcoming().work
+	if t.State.Stage == StageCycles && t.State.IsWork {
+		total = config.AddDurations(total, t.State.CurrentTime)
+	}
+	return total, true
+}
+
+// HoursMinutes formats d like "2h14m", rounding up so it reads 0m only when
+// nothing is left
+func HoursMinutes(d time.Duration) string {
+	minutes := int(d / time.Minute)
+	if d%time.Minute > 0 {
+		minutes++
+	}
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh%02dm", minutes/60, minutes%60)
+}
+
+// Unlimited timers only get this many segments charted
+const GanttPreview = 16
+
+// ScheduleSegments lists every segment of the config from the start, capped
+// at GanttPreview for unlimited timers. The bool is false when it was capped.
+func ScheduleSegments(cfg config.TimerConfig) ([]Segment, bool) {
+	fresh := TimerFromConfig(cfg)
+	first := Segment{StageCycles, 0, 1, true, fresh.workLength(0, 1)}
+	if fresh.State.Stage == StageWarmup {
+		first = Segment{StageWarmup, 0, 0, true, cfg.WarmupDuration}
+	}
+	limit := GanttPreview
+	if cfg.MaxCycles != -1 {
+		limit = len(cfg.Phases)*cfg.MaxCycles*2 + 2
+	}
+	segments := append([]Segment{first}, fresh.UpcomingSegments(limit-1)...)
+	return segments, cfg.MaxCycles != -1
+}
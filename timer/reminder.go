@@ -0,0 +1,25 @@
+package timer
+
+import "time"
+
+// remind counts active time towards the timer's recurring reminder and
+// notifies every time another interval is complete. It doesn't care what
+// segment the timer is in.
+func (t *Timer) remind(elapsed time.Duration) {
+	if t.ReminderEvery <= 0 {
+		return
+	}
+	t.ReminderElapsed += elapsed
+	for t.ReminderElapsed >= t.ReminderEvery {
+		t.ReminderElapsed -= t.ReminderEvery
+		t.notify(t.ReminderText)
+	}
+}
+
+// untilReminder is the active time left until the next reminder
+func (t *Timer) untilReminder() (time.Duration, bool) {
+	if t.ReminderEvery <= 0 {
+		return 0, false
+	}
+	return t.ReminderEvery - t.ReminderElapsed, true
+}
@@ -0,0 +1,39 @@
+package timer
+
+import "slices"
+
+// SkipSegment ends the current segment now and moves on as if its time was
+// up, with the notification of the next one, and logs it as interrupted. It
+// returns true once the timer has completed.
+func (t *Timer) SkipSegment() bool {
+	t.Snoozed = nil // the segment held back is the one skipped
+	n := len(t.events)
+	completed := t.runOut()
+	t.interruptFrom(n)
+	if t.CountUp && !completed {
+		t.State.CurrentTime = 0
+	}
+	return completed
+}
+
+// SkipCycle ends what is left of the current cycle, its work if that is
+// running and its break, and starts the next one. Outside the cycles it
+// skips the warmup or cooldown.
+func (t *Timer) SkipCycle() bool {
+	if t.State.Stage != StageCycles || !t.State.IsWork || t.oneShot() {
+		return t.SkipSegment()
+	}
+	t.Snoozed = nil
+	skipped := len(t.events)
+	t.EndSegment()
+	t.State.IsWork = false
+	n := len(t.events)
+	completed := t.EndBreak()
+	// The break never started, so it has nothing to log
+	t.events = slices.Delete(t.events, n, n+1)
+	t.interruptFrom(skipped)
+	if t.CountUp && !completed {
+		t.State.CurrentTime = 0
+	}
+	return completed
+}
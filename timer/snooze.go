@@ -0,0 +1,42 @@
+package timer
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultSnooze is how long snooze holds a segment back without a duration
+const DefaultSnooze = 5 * time.Minute
+
+// Snoozed holds back a work or break segment that just started: the timer
+// counts the snooze down in its CurrentTime and then announces the segment
+// again and starts it with the time it had left
+type Snoozed struct {
+	SegmentTime time.Duration
+	Message     string
+}
+
+// Snooze holds the current segment back by d, a timer already snoozed gets
+// d from now instead. It fails for timers without a work or break to hold
+// back.
+func (t *Timer) Snooze(d time.Duration) bool {
+	if t.IsStopwatch() || t.oneShot() || t.CountUp || t.OnOpenBreak() || t.State.Stage != StageCycles {
+		return false
+	}
+	if t.Snoozed == nil {
+		t.Snoozed = &Snoozed{SegmentTime: t.State.CurrentTime, Message: t.lastAnnounce}
+	}
+	t.State.CurrentTime = d
+	return true
+}
+
+// endSnooze starts the segment that was held back
+func (t *Timer) endSnooze() {
+	t.State.CurrentTime = t.Snoozed.SegmentTime
+	message := t.Snoozed.Message
+	t.Snoozed = nil
+	if message == "" {
+		message = t.State.NotifText
+	}
+	t.announce(fmt.Sprintf("Snooze over: %s", message))
+}
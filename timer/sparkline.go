@@ -0,0 +1,56 @@
+package timer
+
+import (
+	"strings"
+	"time"
+)
+
+// How many samples of the remaining time a timer keeps for its sparkline
+const sparkSamples = 20
+
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+// sampleRing keeps the most recent sparkSamples values, oldest overwritten
+type sampleRing struct {
+	values [sparkSamples]time.Duration
+	next   int
+	count  int
+	Last   time.Time // when the last sample was taken
+}
+
+// Add keeps d, dropping the oldest sample once the ring is full
+func (r *sampleRing) Add(d time.Duration) {
+	r.values[r.next] = d
+	r.next = (r.next + 1) % sparkSamples
+	r.count = min(r.count+1, sparkSamples)
+}
+
+// Series is the samples oldest first
+func (r *sampleRing) Series() []time.Duration {
+	series := make([]time.Duration, 0, r.count)
+	for i := 0; i < r.count; i++ {
+		series = append(series, r.values[(r.next-r.count+i+sparkSamples)%sparkSamples])
+	}
+	return series
+}
+
+// Sparkline draws the series with one bar per value, scaled from the
+// lowest to the highest. A flat series is drawn at the bottom.
+func Sparkline(series []time.Duration) string {
+	if len(series) == 0 {
+		return ""
+	}
+	low, high := series[0], series[0]
+	for _, d := range series {
+		low, high = min(low, d), max(high, d)
+	}
+	var b strings.Builder
+	for _, d := range series {
+		level := 0
+		if high > low {
+			level = int(int64(d-low) * int64(len(sparkBars)-1) / int64(high-low))
+		}
+		b.WriteRune(sparkBars[level])
+	}
+	return b.String()
+}
@@ -0,0 +1,40 @@
+package timer
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSparkline(t *testing.T) {
+	s := time.Second
+	for _, test := range []struct {
+		series []time.Duration
+		want   string
+	}{
+		{nil, ""},
+		{[]time.Duration{5 * s}, "▁"},
+		{[]time.Duration{5 * s, 5 * s, 5 * s}, "▁▁▁"},
+		{[]time.Duration{7 * s, 6 * s, 5 * s, 4 * s, 3 * s, 2 * s, s, 0}, "█▇▆▅▄▃▂▁"},
+		{[]time.Duration{3 * s, 2 * s, s, 0, 25 * time.Minute, 25*time.Minute - s}, "▁▁▁▁█▇"}, // a new segment started
+		{[]time.Duration{0, 14 * s, 7 * s}, "▁█▄"},
+	} {
+		if got := Sparkline(test.series); got != test.want {
+			t.Errorf("Sparkline(%v) = %q, want %q", test.series, got, test.want)
+		}
+	}
+}
+
+func TestSampleRingKeepsLatest(t *testing.T) {
+	var ring sampleRing
+	for i := range sparkSamples + 5 {
+		ring.Add(time.Duration(i) * time.Second)
+	}
+	series := ring.Series()
+	if len(series) != sparkSamples || series[0] != 5*time.Second || series[len(series)-1] != (sparkSamples+4)*time.Second {
+		t.Errorf("series = %v, want the last %d samples oldest first", series, sparkSamples)
+	}
+	if !slices.IsSorted(series) {
+		t.Errorf("series = %v, want it oldest first", series)
+	}
+}
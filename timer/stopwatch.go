@@ -0,0 +1,89 @@
+package timer
+
+import (
+	"fmt"
+	"time"
+
+	"multi-timer/config"
+)
+
+// IsStopwatch reports whether the timer only counts up, with no phases
+func (t *Timer) IsStopwatch() bool {
+	return len(t.Phases) == 0
+}
+
+// ToggleDirection flips between counting down what's left of the segment and
+// counting up the time spent in it. Flipping back after an overrun leaves
+// nothing remaining, so the timer moves on at the next tick, and flipping a
+// countdown set past the segment length starts the count-up at zero.
+func (t *Timer) ToggleDirection() bool {
+	if t.IsStopwatch() || t.OnOpenBreak() {
+		return false
+	}
+	total := t.SegmentDuration()
+	t.State.CurrentTime = max(total-t.State.CurrentTime, 0)
+	t.CountUp = !t.CountUp
+	return true
+}
+
+// NewStopwatch is a timer that counts up from zero until it is stopped
+func NewStopwatch(name string) *Timer {
+	return &Timer{
+		State: TimerState{
+			IsWork: true,
+			Name:   name,
+		},
+		maxCycles: -1,
+		CountUp:   true,
+		Ephemeral: true,
+	}
+}
+
+// CountdownConfig turns the time on a stopwatch into a single countdown of
+// the same length, rounded to the minute
+func (t *Timer) CountdownConfig(name string) config.TimerConfig {
+	work := t.State.CurrentTime.Round(time.Minute)
+	if work < time.Minute {
+		work = time.Minute
+	}
+	return config.CountdownConfig(name, work)
+}
+
+// Lap is a lap of a stopwatch, Split is the stopwatch time when it ended
+type Lap struct {
+	Split time.Duration
+	At    time.Time
+}
+
+// Laps shown under a stopwatch, the earlier ones are only in the history
+const shownLaps = 5
+
+// AddLap ends the current lap of a stopwatch
+func (t *Timer) AddLap(now time.Time) bool {
+	if !t.IsStopwatch() {
+		return false
+	}
+	t.Laps = append(t.Laps, Lap{Split: t.State.CurrentTime, At: now})
+	return true
+}
+
+// LapLines lists the latest laps with their length and split
+func (t *Timer) LapLines() []string {
+	var lines []string
+	for i := max(len(t.Laps)-shownLaps, 0); i < len(t.Laps); i++ {
+		length := t.Laps[i].Split
+		if i > 0 {
+			length -= t.Laps[i-1].Split
+		}
+		lines = append(lines, fmt.Sprintf("   Lap %d: %s (%s)", i+1, ClockTime(length), ClockTime(t.Laps[i].Split)))
+	}
+	return lines
+}
+
+// ClockTime shows d as MM:SS, or H:MM:SS from an hour on
+func ClockTime(d time.Duration) string {
+	if d >= time.Hour {
+		return fmt.Sprintf("%d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+	}
+	return fmt.Sprintf("%02d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
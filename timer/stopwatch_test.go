@@ -0,0 +1,25 @@
+package timer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestShortStopwatchKeepsAMinute(t *testing.T) {
+	stopwatch := NewStopwatch("Tea")
+	stopwatch.State.CurrentTime = 20 * time.Second
+	if got := stopwatch.CountdownConfig("Tea").Phases[0].WorkDuration; got != time.Minute {
+		t.Errorf("work of %v, want at least a minute", got)
+	}
+}
+
+// A countdown set past its segment's length flips to a count-up from zero,
+// never a negative time spent
+func TestToggleDirectionClampsAtZero(t *testing.T) {
+	timer := TimerFromConfig(pomodoro("Call", 25*time.Minute, 5*time.Minute, 4))
+	timer.SetRemaining(40*time.Minute, true)
+	timer.ToggleDirection()
+	if timer.State.CurrentTime != 0 {
+		t.Errorf("count-up starts at %v, want 0", timer.State.CurrentTime)
+	}
+}
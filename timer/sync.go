@@ -0,0 +1,108 @@
+package timer
+
+import (
+	"reflect"
+
+	"multi-timer/config"
+)
+
+// Config rebuilds the saved config of a running timer
+func (t *Timer) Config() config.TimerConfig {
+	return config.TimerConfig{
+		Name:      t.State.Name,
+		NotifText: t.State.NotifText,
+		Type:      t.Kind,
+		AlarmAt:   t.alarmAt,
+		Phases:    t.configPhases(),
+		MaxCycles: t.maxCycles,
+
+		WarmupDuration:   t.warmup,
+		CooldownDuration: t.cooldown,
+		ExclusiveGroup:   t.Group,
+		LinkedGroup:      t.linked,
+		NextTimer:        t.Next,
+		PausesOnWork:     t.PausesOnWork,
+		StartSound:       t.StartSound,
+		CompleteSound:    t.CompleteSound,
+		WorkSound:        t.workSound,
+		BreakSound:       t.breakSound,
+		BaseDuration:     t.Base,
+		EscalateDuration: t.Escalate.After,
+		EscalateRepeats:  t.Escalate.Repeats,
+
+		FinalCycleWorkDuration:  t.finalWork,
+		FinalCycleBreakDuration: t.finalBreak,
+
+		Milestones:           t.milestones,
+		MilestoneMinDuration: t.milestoneMin,
+
+		ProtectedCycles: t.protectedCycles,
+
+		ReminderText:     t.ReminderText,
+		ReminderDuration: t.ReminderEvery,
+
+		BreakActivities: t.activities,
+
+		Tags: t.Tags,
+
+		TaperWorkDuration:     t.taper.workStep,
+		TaperBreakDuration:    t.taper.breakStep,
+		TaperMinWorkDuration:  t.taper.minWork,
+		TaperMaxBreakDuration: t.taper.maxBreak,
+
+		Webhooks: t.Webhooks,
+
+		Order: t.order(),
+
+		AutoPauseOnIdle: t.AutoPause,
+	}
+}
+
+// configPhases are the phases as saved, an alarm's time left isn't
+func (t *Timer) configPhases() []config.TimerPhase {
+	if t.Kind == config.TypeAlarm {
+		return []config.TimerPhase{{}}
+	}
+	return t.Phases
+}
+
+func (t *Timer) order() string {
+	if t.cycleMajor {
+		return config.OrderCycleMajor
+	}
+	return ""
+}
+
+// Matches reports whether the timer already runs as cfg describes
+func (t *Timer) Matches(cfg config.TimerConfig) bool {
+	return t.State.NotifText == cfg.NotifText &&
+		t.maxCycles == cfg.MaxCycles &&
+		t.warmup == cfg.WarmupDuration &&
+		t.cooldown == cfg.CooldownDuration &&
+		t.Group == cfg.ExclusiveGroup &&
+		t.linked == cfg.LinkedGroup &&
+		t.Next == cfg.NextTimer &&
+		reflect.DeepEqual(t.PausesOnWork, cfg.PausesOnWork) &&
+		t.Base == cfg.BaseDuration &&
+		t.finalWork == cfg.FinalCycleWorkDuration &&
+		t.finalBreak == cfg.FinalCycleBreakDuration &&
+		t.StartSound == cfg.StartSound &&
+		t.CompleteSound == cfg.CompleteSound &&
+		t.workSound == cfg.WorkSound &&
+		t.breakSound == cfg.BreakSound &&
+		t.ReminderText == cfg.ReminderText &&
+		t.ReminderEvery == cfg.ReminderDuration &&
+		t.Escalate == Escalation{After: cfg.EscalateDuration, Repeats: cfg.EscalateRepeats} &&
+		t.milestoneMin == cfg.MilestoneMinDuration &&
+		reflect.DeepEqual(t.milestones, cfg.Milestones) &&
+		reflect.DeepEqual(t.protectedCycles, cfg.ProtectedCycles) &&
+		reflect.DeepEqual(t.activities, cfg.BreakActivities) &&
+		reflect.DeepEqual(t.Tags, cfg.Tags) &&
+		t.taper == configTaper(cfg) &&
+		reflect.DeepEqual(t.Webhooks, cfg.Webhooks) &&
+		t.cycleMajor == (cfg.Order == config.OrderCycleMajor) &&
+		t.AutoPause == cfg.AutoPauseOnIdle &&
+		t.Kind == cfg.Type &&
+		t.alarmAt == cfg.AlarmAt &&
+		reflect.DeepEqual(t.configPhases(), cfg.Phases)
+}
@@ -0,0 +1,8 @@
+package timer
+
+import "slices"
+
+// HasTag reports whether the timer is tagged tag
+func (t *Timer) HasTag(tag string) bool {
+	return slices.Contains(t.Tags, tag)
+}
@@ -0,0 +1,53 @@
+package timer
+
+import (
+	"time"
+
+	"multi-timer/config"
+)
+
+// taper shortens work and lengthens breaks by a step every cycle, work
+// never below minWork and breaks never above maxBreak (0 for no limit)
+type taper struct {
+	workStep  time.Duration
+	breakStep time.Duration
+	minWork   time.Duration
+	maxBreak  time.Duration
+}
+
+func configTaper(cfg config.TimerConfig) taper {
+	return taper{
+		workStep:  cfg.TaperWorkDuration,
+		breakStep: cfg.TaperBreakDuration,
+		minWork:   cfg.TaperMinWorkDuration,
+		maxBreak:  cfg.TaperMaxBreakDuration,
+	}
+}
+
+func (tp taper) work(d time.Duration, cycle int) time.Duration {
+	if tp.workStep <= 0 || d <= tp.minWork {
+		return d
+	}
+	floor := max(tp.minWork, TickInterval)
+	if steps := time.Duration(cycle - 1); steps > 0 && tp.workStep > (d-floor)/steps {
+		return floor
+	}
+	return d - time.Duration(cycle-1)*tp.workStep
+}
+
+func (tp taper) rest(d time.Duration, cycle int) time.Duration {
+	if tp.breakStep <= 0 || d == config.UntilResumed {
+		return d
+	}
+	limit := config.MaxDuration
+	if tp.maxBreak > 0 {
+		limit = tp.maxBreak
+	}
+	if d >= limit {
+		return d
+	}
+	if steps := time.Duration(cycle - 1); steps > 0 && tp.breakStep > (limit-d)/steps {
+		return limit
+	}
+	return d + time.Duration(cycle-1)*tp.breakStep
+}
@@ -0,0 +1,16 @@
+package timer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTaperOff(t *testing.T) {
+	var tp taper
+	if got := tp.work(25*time.Minute, 3); got != 25*time.Minute {
+		t.Errorf("work without a taper = %v, want 25m", got)
+	}
+	if got := tp.rest(5*time.Minute, 3); got != 5*time.Minute {
+		t.Errorf("break without a taper = %v, want 5m", got)
+	}
+}
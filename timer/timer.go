@@ -0,0 +1,644 @@
+// Package timer is a single timer: its segments of work and break and how
+// they advance with each tick, and how it is rendered.
+package timer
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"multi-timer/config"
+)
+
+// TimerStage is the part of its run a timer is in
+type TimerStage int
+
+const (
+	StageCycles TimerStage = iota
+	StageWarmup
+	StageCooldown
+)
+
+// TimerState is where a timer is in its run
+type TimerState struct {
+	Stage        TimerStage
+	IsWork       bool
+	CurrentTime  time.Duration
+	Cycles       int
+	CurrentPhase int
+	Name         string
+	NotifText    string
+}
+
+// TimerStats are the totals of a timer since it started
+type TimerStats struct {
+	CompletedCycles int
+	WorkTime        time.Duration // time spent in work segments
+}
+
+// Timer is a running timer: its config and how far it got
+type Timer struct {
+	State     TimerState
+	Stats     TimerStats
+	Phases    []config.TimerPhase
+	maxCycles int // -1 for unlimited
+	warmup    time.Duration
+	cooldown  time.Duration
+	Group     string   // exclusive group
+	linked    string   // linked group
+	Next      string   // timer to start on completion
+	Chain     []string // timers that ran before this one in its chain
+	Escalate  Escalation
+	Alert     *PendingAlert // last notification, until acknowledged
+	IsPaused  bool
+	started   time.Time     // when the current segment began, kept by the manager
+	Active    time.Duration // timer time the current segment ran, pauses and snoozes left out
+	wake      int           // 1 + the index of its entry in the manager's wakeups, 0 for none
+	caughtUp  time.Duration // the part of the manager's saved up time it already got, see CatchUp
+	CountUp   bool          // CurrentTime is the elapsed time of the segment, always set for stopwatches
+	Ephemeral bool          // not backed by a saved config
+	Kind      string        // the config's Type
+	alarmAt   string
+	events    []TimerEvent
+
+	// Percentages of a work segment to notify at, the highest one that
+	// fired in the current segment
+	milestones       []int
+	milestoneMin     time.Duration
+	MilestoneReached int
+
+	StartSound    string
+	CompleteSound string
+	workSound     string
+	breakSound    string
+
+	Base time.Duration // for phases with a WorkScale or BreakScale
+
+	// Lengths for the last cycle of each phase, 0 for the usual ones
+	finalWork  time.Duration
+	finalBreak time.Duration
+
+	PausesOnWork []string // timers to pause whenever this one starts work
+
+	protectedCycles []int // deep work cycles, see protected
+
+	// Recurring reminder every ReminderEvery of active time, see remind
+	ReminderText    string
+	ReminderEvery   time.Duration
+	ReminderElapsed time.Duration
+
+	activities   []string // suggested in turn at the start of each break
+	NextActivity int
+
+	Tags []string
+
+	taper taper
+
+	Webhooks []string
+
+	cycleMajor bool // one cycle of every phase per round, see advance
+
+	Clock func() time.Time // for the phases' ActiveHours, set by the manager
+
+	Samples sampleRing // recent remaining times for the sparkline
+
+	Laps        []Lap // of a stopwatch, see AddLap
+	LapsWritten int   // how many of the laps are in the history
+
+	Snoozed      *Snoozed // see snooze
+	lastAnnounce string   // message of the last segment start, repeated after a snooze
+
+	AutoPause    bool // pause work while the user is away
+	PausedByIdle bool // paused by that, to be resumed on return
+
+	upcomingCache upcomingTotals // see upcoming
+}
+
+// TickInterval is how much time passes between updates of the timers
+const TickInterval = time.Second
+
+// notify queues a notification for the manager to send after the update,
+// called once the timer has moved on to the segment it announces
+func (t *Timer) notify(message string) {
+	t.events = append(t.events, TimerEvent{
+		Kind:    Notification,
+		Segment: t.SegmentLabel(),
+		Phase:   min(t.State.CurrentPhase, len(t.Phases)-1),
+		Cycle:   t.State.Cycles,
+		Message: message,
+	})
+}
+
+// announce notifies that a work or break segment just started, the text can
+// be overridden for all timers
+func (t *Timer) announce(message string) {
+	t.notify(message)
+	t.lastAnnounce = message
+	t.events[len(t.events)-1].Announce = true
+}
+
+// EndSegment records that the current segment is over, before the timer
+// moves on to the next one
+func (t *Timer) EndSegment() {
+	t.MilestoneReached = 0
+	t.Snoozed = nil
+	t.events = append(t.events, TimerEvent{
+		Kind:    SegmentEnded,
+		Segment: t.SegmentLabel(),
+		Phase:   t.State.CurrentPhase,
+		Cycle:   t.State.Cycles,
+		Active:  t.Active,
+	})
+	t.Active = 0
+}
+
+// Started is when the current segment began, zero until the first update
+func (t *Timer) Started() time.Time {
+	return t.started
+}
+
+// SetStarted records when the current segment began
+func (t *Timer) SetStarted(at time.Time) {
+	t.started = at
+}
+
+// SegmentLabel names the kind of segment the timer is in
+func (t *Timer) SegmentLabel() string {
+	switch {
+	case t.State.Stage == StageWarmup:
+		return "Warmup"
+	case t.State.Stage == StageCooldown:
+		return "Cooldown"
+	case !t.State.IsWork:
+		return "Break"
+	}
+	return "Work"
+}
+
+// String is the timer the way the display shows it by default
+func (t *Timer) String() string {
+	return t.Render(config.Preferences{})
+}
+
+// Render formats the timer for the display using the display preferences
+func (t *Timer) Render(prefs config.Preferences) string {
+	if prefs.Words {
+		return t.RenderWords()
+	}
+	if t.IsStopwatch() {
+		return fmt.Sprintf("%s - Stopwatch: %s", t.State.Name, ClockTime(t.State.CurrentTime))
+	}
+
+	state := t.SegmentLabel()
+	sign := ""
+	shown := config.RoundForDisplay(t.State.CurrentTime, prefs.DisplayRounding)
+	if t.oneShot() {
+		return t.renderOneShot(shown)
+	}
+	if t.OnOpenBreak() {
+		// CurrentTime counts up while waiting for a resume
+		state = "Break (until resumed)"
+		sign = "+"
+		shown = t.State.CurrentTime
+	} else if t.CountUp {
+		state += " (counting up)"
+		sign = "+"
+		shown = t.State.CurrentTime
+	} else if t.Snoozed != nil {
+		state += " (snoozed)"
+	}
+
+	cycleStr := fmt.Sprintf("%d", t.State.Cycles)
+	if t.maxCycles == -1 {
+		cycleStr += " (∞)"
+	} else {
+		cycleStr += fmt.Sprintf("/%d", t.maxCycles)
+	}
+	if prefs.CycleDots {
+		if dots, ok := config.CycleDots(t.State.Cycles, t.maxCycles); ok {
+			cycleStr = dots
+		}
+	}
+
+	phaseStr := fmt.Sprintf("Phase %d/%d", t.State.CurrentPhase+1, len(t.Phases))
+
+	return fmt.Sprintf("%s - %s: %s%s (Cycle %s) %s",
+		t.State.Name, state, sign, ClockTime(shown), cycleStr, phaseStr)
+}
+
+// WriteDebug prints the raw state machine data for troubleshooting
+func (t *Timer) WriteDebug(w io.Writer) {
+	fmt.Fprintf(w, "--- %s ---\n", t.State.Name)
+	fmt.Fprintf(w, "isWork:       %t\n", t.State.IsWork)
+	fmt.Fprintf(w, "currentTime:  %s\n", t.State.CurrentTime)
+	fmt.Fprintf(w, "cycles:       %d\n", t.State.Cycles)
+	fmt.Fprintf(w, "currentPhase: %d\n", t.State.CurrentPhase)
+	fmt.Fprintf(w, "maxCycles:    %d\n", t.maxCycles)
+	fmt.Fprintf(w, "isPaused:     %t\n", t.IsPaused)
+	fmt.Fprintf(w, "notifText:    %q\n", t.State.NotifText)
+	fmt.Fprintf(w, "phases:\n")
+	for i, phase := range t.Phases {
+		fmt.Fprintf(w, "  %d. work %s, break %s\n", i, phase.WorkDuration, phase.BreakDuration)
+	}
+}
+
+// Update handles the timer state update, elapsed is how much of the current
+// segment the tick consumed
+func (t *Timer) Update(elapsed time.Duration) bool {
+	if t.IsPaused {
+		return false
+	}
+	t.remind(elapsed)
+
+	if t.CountUp || t.OnOpenBreak() {
+		if t.CountUp && !t.IsStopwatch() && t.State.Stage == StageCycles && t.State.IsWork {
+			t.Stats.WorkTime += elapsed
+		}
+		t.State.CurrentTime += elapsed
+		t.Active += elapsed
+		return false
+	}
+
+	if t.State.CurrentTime <= 0 {
+		return t.runOut()
+	}
+	if t.State.Stage == StageCycles && t.State.IsWork && t.Snoozed == nil {
+		t.Stats.WorkTime += min(elapsed, t.State.CurrentTime)
+	}
+	if t.Snoozed == nil {
+		t.Active += min(elapsed, t.State.CurrentTime)
+	}
+	overshoot := elapsed - t.State.CurrentTime
+	t.State.CurrentTime -= elapsed
+	if t.State.CurrentTime < 0 {
+		t.State.CurrentTime = 0
+	}
+	t.checkMilestones()
+	if overshoot < 0 {
+		return false
+	}
+	// Move on right away and count the rest of the tick against the next
+	// segment, so no time is lost at the boundary
+	completed := t.runOut()
+	if !completed && !t.CountUp && !t.OnOpenBreak() {
+		if t.Snoozed == nil {
+			t.Active += min(overshoot, t.State.CurrentTime)
+		}
+		t.State.CurrentTime = max(t.State.CurrentTime-overshoot, 0)
+	}
+	return completed
+}
+
+// runOut moves the timer on from a segment whose time is up, returning true
+// once the timer has completed
+func (t *Timer) runOut() bool {
+	if t.Snoozed != nil {
+		t.endSnooze()
+		return false
+	}
+	switch t.State.Stage {
+	case StageWarmup:
+		t.EndSegment()
+		t.State.Stage = StageCycles
+		t.State.CurrentTime = t.workLength(0, 1)
+		t.announce(t.State.NotifText)
+		return false
+	case StageCooldown:
+		t.EndSegment()
+		t.notify(fmt.Sprintf("All phases completed: %s", t.State.NotifText))
+		return true // Timer completed
+	}
+	if t.oneShot() {
+		return t.finishOneShot()
+	}
+	if t.State.IsWork {
+		t.EndSegment()
+		t.StartBreak()
+		message := fmt.Sprintf("b %s", t.State.NotifText)
+		if activity := t.breakActivity(); activity != "" {
+			message += fmt.Sprintf(" - try: %s", activity)
+		}
+		t.announce(message)
+		return false
+	}
+	return t.EndBreak()
+}
+
+// Reset starts the timer over from its first phase and cycle, warmup
+// included. With keepCycles only the current segment starts over, keeping
+// the cycles done so far.
+func (t *Timer) Reset(keepCycles bool) {
+	if !keepCycles && !t.IsStopwatch() {
+		t.State.IsWork = true
+		t.State.Cycles = 1
+		t.State.CurrentPhase = 0
+		t.State.Stage = StageCycles
+		if t.warmup > 0 {
+			t.State.Stage = StageWarmup
+		}
+	}
+	t.MilestoneReached = 0
+	t.Snoozed = nil
+	t.Active = 0
+	t.State.CurrentTime = t.SegmentDuration()
+	if t.CountUp || t.OnOpenBreak() {
+		t.State.CurrentTime = 0
+	}
+}
+
+// AddTime lengthens (or with a negative d shortens) the current segment,
+// never below zero
+func (t *Timer) AddTime(d time.Duration) {
+	t.State.CurrentTime = min(t.State.CurrentTime+d, config.MaxDuration)
+	if t.State.CurrentTime < 0 {
+		t.State.CurrentTime = 0
+	}
+}
+
+// SetRemaining sets what's left of the current segment, no more than the
+// segment's length unless overtime is allowed. Stopwatches get their elapsed
+// time set instead. It fails on breaks that wait for resume.
+func (t *Timer) SetRemaining(d time.Duration, overtime bool) bool {
+	if d < 0 || t.OnOpenBreak() {
+		return false
+	}
+	if t.IsStopwatch() {
+		t.State.CurrentTime = d
+		return true
+	}
+	total := t.SegmentDuration()
+	if !overtime {
+		d = min(d, total)
+	}
+	if t.CountUp {
+		// Counting up shows the time spent, which can't go below zero
+		t.State.CurrentTime = max(total-d, 0)
+		return true
+	}
+	t.State.CurrentTime = d
+	return true
+}
+
+// StartBreak moves the timer into the break of its current phase
+func (t *Timer) StartBreak() {
+	t.State.IsWork = false
+	t.State.CurrentTime = t.breakLength(t.State.CurrentPhase, t.State.Cycles)
+	if t.State.CurrentTime == config.UntilResumed {
+		t.State.CurrentTime = 0
+	}
+}
+
+// breakActivity is the activity to suggest for the break starting now, the
+// next break gets the one after it
+func (t *Timer) breakActivity() string {
+	if len(t.activities) == 0 {
+		return ""
+	}
+	activity := t.activities[t.NextActivity%len(t.activities)]
+	t.NextActivity = (t.NextActivity + 1) % len(t.activities)
+	return activity
+}
+
+// EndBreak moves on to the next cycle or phase, returning true once the timer
+// has completed
+func (t *Timer) EndBreak() bool {
+	t.EndSegment()
+	t.events = append(t.events, TimerEvent{Kind: CycleCompleted, Phase: t.State.CurrentPhase, Cycle: t.State.Cycles})
+	t.Stats.CompletedCycles++
+	phase, cycle, phaseDone, completed := t.advance(t.State.CurrentPhase, t.State.Cycles)
+	if phaseDone {
+		t.events = append(t.events, TimerEvent{Kind: PhaseCompleted, Phase: t.State.CurrentPhase})
+	}
+	if !completed && phase != t.State.CurrentPhase {
+		phase, cycle, completed = t.skipInactive(phase, cycle)
+	}
+	t.State.CurrentPhase, t.State.Cycles = phase, cycle
+	if completed {
+		if t.cooldown > 0 {
+			t.State.CurrentPhase = len(t.Phases) - 1
+			t.State.Stage = StageCooldown
+			t.State.IsWork = true
+			t.State.CurrentTime = t.cooldown
+			t.notify(fmt.Sprintf("Cooldown: %s", t.State.NotifText))
+			return false
+		}
+		t.notify(fmt.Sprintf("All phases completed: %s", t.State.NotifText))
+		return true // Timer completed
+	}
+	t.State.IsWork = true
+	t.State.CurrentTime = t.workLength(t.State.CurrentPhase, t.State.Cycles)
+	t.announce(t.State.NotifText)
+	return false
+}
+
+// advance is the phase and cycle that follow a cycle of a phase, in the
+// timer's order. phaseDone is set when that was the last cycle of the phase
+// and completed when it was the last cycle of the timer, past its phases.
+func (t *Timer) advance(phase, cycle int) (nextPhase, nextCycle int, phaseDone, completed bool) {
+	if !t.cycleMajor {
+		if t.maxCycles == -1 || cycle < t.maxCycles {
+			return phase, cycle + 1, false, false
+		}
+		if phase+1 >= len(t.Phases) {
+			return phase + 1, cycle + 1, true, true
+		}
+		return phase + 1, 1, true, false
+	}
+
+	phaseDone = t.maxCycles != -1 && cycle >= t.maxCycles
+	if phase+1 < len(t.Phases) {
+		return phase + 1, cycle, phaseDone, false
+	}
+	completed = t.maxCycles != -1 && cycle >= t.maxCycles
+	if completed {
+		return len(t.Phases), cycle + 1, phaseDone, true
+	}
+	return 0, cycle + 1, phaseDone, false
+}
+
+// OnOpenBreak reports whether the timer is on a break that waits for resume
+func (t *Timer) OnOpenBreak() bool {
+	return !t.IsStopwatch() && t.State.Stage == StageCycles && !t.State.IsWork &&
+		t.breakLength(t.State.CurrentPhase, t.State.Cycles) == config.UntilResumed
+}
+
+// TimerFromConfig is a fresh timer for cfg, at the start of its warmup or
+// else its first work segment
+func TimerFromConfig(cfg config.TimerConfig) *Timer {
+	cfg.Phases = config.ResolvePhases(cfg.Phases, cfg.BaseDuration)
+	timer := &Timer{
+		State: TimerState{
+			IsWork:       true,
+			Cycles:       1,
+			CurrentPhase: 0,
+			Name:         cfg.Name,
+			NotifText:    cfg.NotifText,
+		},
+		Phases:    cfg.Phases,
+		maxCycles: cfg.MaxCycles,
+		warmup:    cfg.WarmupDuration,
+		cooldown:  cfg.CooldownDuration,
+		Group:     cfg.ExclusiveGroup,
+		linked:    cfg.LinkedGroup,
+		Next:      cfg.NextTimer,
+		Escalate:  Escalation{After: cfg.EscalateDuration, Repeats: cfg.EscalateRepeats},
+		IsPaused:  false,
+
+		milestones:   cfg.Milestones,
+		milestoneMin: cfg.MilestoneMinDuration,
+
+		StartSound:    cfg.StartSound,
+		CompleteSound: cfg.CompleteSound,
+		workSound:     cfg.WorkSound,
+		breakSound:    cfg.BreakSound,
+
+		Base:       cfg.BaseDuration,
+		finalWork:  cfg.FinalCycleWorkDuration,
+		finalBreak: cfg.FinalCycleBreakDuration,
+
+		PausesOnWork: cfg.PausesOnWork,
+
+		protectedCycles: cfg.ProtectedCycles,
+
+		ReminderText:  cfg.ReminderText,
+		ReminderEvery: cfg.ReminderDuration,
+
+		activities: cfg.BreakActivities,
+
+		Tags: cfg.Tags,
+
+		taper: configTaper(cfg),
+
+		Webhooks: cfg.Webhooks,
+
+		cycleMajor: cfg.Order == config.OrderCycleMajor,
+
+		AutoPause: cfg.AutoPauseOnIdle,
+
+		Kind:    cfg.Type,
+		alarmAt: cfg.AlarmAt,
+	}
+	if timer.Kind == config.TypeAlarm {
+		timer.Phases = config.AlarmPhases(cfg.AlarmAt, time.Now())
+	}
+	timer.State.CurrentTime = timer.workLength(0, 1)
+	if timer.warmup > 0 {
+		timer.State.Stage = StageWarmup
+		timer.State.CurrentTime = timer.warmup
+	}
+	return timer
+}
+
+// ApplyConfig updates a running timer after its config was edited, keeping
+// its progress as far as the new phases allow
+func (t *Timer) ApplyConfig(cfg config.TimerConfig) {
+	if len(cfg.Phases) == 0 {
+		return
+	}
+	t.upcomingCache = upcomingTotals{}
+	t.State.NotifText = cfg.NotifText
+	switch {
+	case cfg.Type != config.TypeAlarm:
+		t.Phases = config.ResolvePhases(cfg.Phases, cfg.BaseDuration)
+	case t.Kind != config.TypeAlarm || t.alarmAt != cfg.AlarmAt:
+		t.Phases = config.AlarmPhases(cfg.AlarmAt, time.Now())
+		t.State.CurrentTime = t.Phases[0].WorkDuration
+	}
+	t.Kind = cfg.Type
+	t.alarmAt = cfg.AlarmAt
+	t.Base = cfg.BaseDuration
+	t.finalWork = cfg.FinalCycleWorkDuration
+	t.finalBreak = cfg.FinalCycleBreakDuration
+	t.maxCycles = cfg.MaxCycles
+	t.warmup = cfg.WarmupDuration
+	t.cooldown = cfg.CooldownDuration
+	t.Group = cfg.ExclusiveGroup
+	t.linked = cfg.LinkedGroup
+	t.Next = cfg.NextTimer
+	t.PausesOnWork = cfg.PausesOnWork
+	t.protectedCycles = cfg.ProtectedCycles
+	t.ReminderText = cfg.ReminderText
+	t.ReminderEvery = cfg.ReminderDuration
+	t.activities = cfg.BreakActivities
+	t.Tags = cfg.Tags
+	t.taper = configTaper(cfg)
+	t.Webhooks = cfg.Webhooks
+	t.cycleMajor = cfg.Order == config.OrderCycleMajor
+	t.AutoPause = cfg.AutoPauseOnIdle
+	t.StartSound = cfg.StartSound
+	t.CompleteSound = cfg.CompleteSound
+	t.workSound = cfg.WorkSound
+	t.breakSound = cfg.BreakSound
+	t.Escalate = Escalation{After: cfg.EscalateDuration, Repeats: cfg.EscalateRepeats}
+	t.milestones = cfg.Milestones
+	t.milestoneMin = cfg.MilestoneMinDuration
+	t.FitToPhases()
+}
+
+// FitToPhases brings the timer's place back within its phases after they or
+// the place changed: out of a warmup that is gone, to the last phase and
+// cycle there are, and to no more time than its segment has
+func (t *Timer) FitToPhases() {
+	switch t.State.Stage {
+	case StageCycles, StageCooldown:
+	case StageWarmup:
+		if t.warmup > 0 {
+			break
+		}
+		fallthrough
+	default:
+		t.State.Stage = StageCycles
+		t.State.IsWork = true
+		t.State.CurrentPhase = 0
+		t.State.Cycles = 1
+		t.State.CurrentTime = t.workLength(0, 1)
+	}
+	t.State.CurrentPhase = min(max(t.State.CurrentPhase, 0), len(t.Phases)-1)
+	t.State.Cycles = max(t.State.Cycles, 1)
+	if t.maxCycles > 0 {
+		t.State.Cycles = min(t.State.Cycles, t.maxCycles)
+	}
+	// A snooze holds the segment's time aside until it is over
+	left := &t.State.CurrentTime
+	if t.Snoozed != nil {
+		left = &t.Snoozed.SegmentTime
+	}
+	segment := t.SegmentDuration()
+	if !t.CountUp && segment != config.UntilResumed && *left > segment {
+		*left = segment
+	}
+	*left = max(*left, 0)
+}
+
+// SegmentDuration is the full length of the segment the timer is in
+func (t *Timer) SegmentDuration() time.Duration {
+	switch {
+	case t.State.Stage == StageWarmup:
+		return t.warmup
+	case t.State.Stage == StageCooldown:
+		return t.cooldown
+	case !t.State.IsWork:
+		return t.breakLength(t.State.CurrentPhase, t.State.Cycles)
+	}
+	return t.workLength(t.State.CurrentPhase, t.State.Cycles)
+}
+
+func (t *Timer) finalCycle(cycle int) bool {
+	return t.maxCycles != -1 && cycle == t.maxCycles
+}
+
+// workLength is the work time of a cycle of a phase
+func (t *Timer) workLength(phase, cycle int) time.Duration {
+	if t.finalWork > 0 && t.finalCycle(cycle) {
+		return t.finalWork
+	}
+	return t.taper.work(t.Phases[phase].WorkDuration, cycle)
+}
+
+// breakLength is the break time of a cycle of a phase
+func (t *Timer) breakLength(phase, cycle int) time.Duration {
+	if t.finalBreak > 0 && t.finalCycle(cycle) {
+		return t.finalBreak
+	}
+	return t.taper.rest(t.Phases[phase].BreakDuration, cycle)
+}
@@ -0,0 +1,53 @@
+package timer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"multi-timer/config"
+)
+
+// pomodoro is a config of cycles of work and break, -1 for unlimited
+func pomodoro(name string, work, rest time.Duration, cycles int) config.TimerConfig {
+	return config.TimerConfig{
+		Name:      name,
+		Phases:    []config.TimerPhase{{WorkDuration: work, BreakDuration: rest}},
+		MaxCycles: cycles,
+	}
+}
+
+func TestWriteDebug(t *testing.T) {
+	timer := TimerFromConfig(config.TimerConfig{
+		Name:      "Tea",
+		NotifText: "Tea time",
+		Phases: []config.TimerPhase{
+			{WorkDuration: 25 * time.Minute, BreakDuration: 5 * time.Minute},
+			{WorkDuration: 50 * time.Minute, BreakDuration: 15 * time.Minute},
+		},
+		MaxCycles: 3,
+	})
+	timer.State.IsWork = false
+	timer.State.CurrentTime = 90 * time.Second
+	timer.State.Cycles = 2
+	timer.State.CurrentPhase = 1
+	timer.IsPaused = true
+
+	var b strings.Builder
+	timer.WriteDebug(&b)
+	want := `--- Tea ---
+isWork:       false
+currentTime:  1m30s
+cycles:       2
+currentPhase: 1
+maxCycles:    3
+isPaused:     true
+notifText:    "Tea time"
+phases:
+  0. work 25m0s, break 5m0s
+  1. work 50m0s, break 15m0s
+`
+	if b.String() != want {
+		t.Errorf("debug block:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
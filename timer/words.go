@@ -0,0 +1,91 @@
+package timer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	smallNumbers = []string{
+		"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine",
+		"ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen",
+		"seventeen", "eighteen", "nineteen",
+	}
+	tens = []string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+)
+
+// numberWords spells out n, e.g. 142 is "one hundred forty-two"
+func numberWords(n int) string {
+	switch {
+	case n < 0:
+		return "minus " + numberWords(-n)
+	case n < 20:
+		return smallNumbers[n]
+	case n < 100:
+		if n%10 == 0 {
+			return tens[n/10]
+		}
+		return tens[n/10] + "-" + smallNumbers[n%10]
+	case n < 1000:
+		if n%100 == 0 {
+			return smallNumbers[n/100] + " hundred"
+		}
+		return smallNumbers[n/100] + " hundred " + numberWords(n%100)
+	}
+	if n%1000 == 0 {
+		return numberWords(n/1000) + " thousand"
+	}
+	return numberWords(n/1000) + " thousand " + numberWords(n%1000)
+}
+
+func unitWords(n int, unit string) string {
+	if n == 1 {
+		return "one " + unit
+	}
+	return numberWords(n) + " " + unit + "s"
+}
+
+// DurationWords phrases d for screen readers like "one hour five minutes",
+// to the second and "zero seconds" for nothing
+func DurationWords(d time.Duration) string {
+	d = d.Truncate(time.Second)
+	if d <= 0 {
+		return "zero seconds"
+	}
+	hours, minutes, seconds := int(d/time.Hour), int(d%time.Hour/time.Minute), int(d%time.Minute/time.Second)
+	var parts []string
+	if hours > 0 {
+		parts = append(parts, unitWords(hours, "hour"))
+	}
+	if minutes > 0 {
+		parts = append(parts, unitWords(minutes, "minute"))
+	}
+	if seconds > 0 {
+		parts = append(parts, unitWords(seconds, "second"))
+	}
+	return strings.Join(parts, " ")
+}
+
+// RenderWords is render for prefs.Words, with the time spelled out
+func (t *Timer) RenderWords() string {
+	if t.IsStopwatch() {
+		return fmt.Sprintf("%s - stopwatch at %s", t.State.Name, DurationWords(t.State.CurrentTime))
+	}
+	segment := strings.ToLower(t.SegmentLabel())
+	var state string
+	switch {
+	case t.OnOpenBreak():
+		state = fmt.Sprintf("%s into a break until resumed", DurationWords(t.State.CurrentTime))
+	case t.CountUp:
+		state = fmt.Sprintf("%s elapsed in %s", DurationWords(t.State.CurrentTime), segment)
+	default:
+		state = fmt.Sprintf("%s remaining in %s", DurationWords(t.State.CurrentTime), segment)
+	}
+	cycles := "cycle " + numberWords(t.State.Cycles)
+	if t.maxCycles != -1 {
+		cycles += " of " + numberWords(t.maxCycles)
+	}
+	return fmt.Sprintf("%s - %s, %s, phase %s of %s", t.State.Name, state, cycles,
+		numberWords(t.State.CurrentPhase+1), numberWords(len(t.Phases)))
+}
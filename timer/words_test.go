@@ -0,0 +1,27 @@
+package timer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDurationWords(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		0:                            "zero seconds",
+		-time.Minute:                 "zero seconds",
+		500 * time.Millisecond:       "zero seconds",
+		time.Second:                  "one second",
+		45 * time.Second:             "forty-five seconds",
+		time.Minute:                  "one minute",
+		5 * time.Minute:              "five minutes",
+		21*time.Minute + time.Second: "twenty-one minutes one second",
+		time.Hour + 5*time.Minute:    "one hour five minutes",
+		2*time.Hour + 30*time.Second: "two hours thirty seconds",
+		142 * time.Hour:              "one hundred forty-two hours",
+		1000*time.Hour + 59*time.Minute + 999*time.Millisecond: "one thousand hours fifty-nine minutes",
+	} {
+		if got := DurationWords(d); got != want {
+			t.Errorf("DurationWords(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
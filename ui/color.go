@@ -0,0 +1,147 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+
+	"multi-timer/config"
+	"multi-timer/timer"
+)
+
+const (
+	resetColor = "\033[0m"
+	blinkCode  = "\033[5m"
+)
+
+// colorEnabled is off when the output isn't a terminal or NO_COLOR is set,
+// see https://no-color.org
+var colorEnabled = os.Getenv("NO_COLOR") == "" && term.IsTerminal(os.Stdout.Fd())
+
+// colors are the names a phase can use for PhaseColor
+var colors = map[string]string{
+	"red":     "\033[31m",
+	"green":   "\033[32m",
+	"yellow":  "\033[33m",
+	"blue":    "\033[34m",
+	"magenta": "\033[35m",
+	"cyan":    "\033[36m",
+	"white":   "\033[37m",
+	"gray":    "\033[90m",
+}
+
+// theme picks the colors of the display, phases with a PhaseColor keep theirs
+type theme struct {
+	work     string
+	rest     string
+	paused   string
+	ending   string // a segment's last minute
+	blink    bool   // blink in the last minute too
+	selected string // the marker of the timer +/- act on
+}
+
+// Preferences.Theme picks one of these or of Preferences.Themes, "off" none
+// and empty defaultTheme
+const defaultTheme = "default"
+
+var themes = map[string]theme{
+	defaultTheme: {work: "green", rest: "blue", paused: "gray", ending: "red", blink: true, selected: "yellow"},
+	"dark":       {work: "green", rest: "cyan", paused: "gray", ending: "red", selected: "yellow"},
+	"light":      {work: "blue", rest: "magenta", paused: "gray", ending: "red", selected: "red"},
+	"solarized":  {work: "yellow", rest: "cyan", paused: "gray", ending: "red", selected: "magenta"},
+}
+
+// customTheme is the theme of colors from the preferences
+func customTheme(c config.ThemeColors) theme {
+	return theme{work: c.Work, rest: c.Break, paused: c.Paused, ending: c.Ending, blink: c.Blink, selected: c.Selected}
+}
+
+// themeProblem names the first color of the theme that isn't one
+func themeProblem(c config.ThemeColors) error {
+	for field, name := range map[string]string{"Work": c.Work, "Break": c.Break, "Paused": c.Paused, "Ending": c.Ending, "Selected": c.Selected} {
+		if !validColor(name) {
+			return fmt.Errorf("%s: unknown color %q, use one of %s or 0 to 255", field, name, colorNames())
+		}
+	}
+	return nil
+}
+
+// theme is the theme chosen in the preferences, its own themes first
+func (tm *Terminal) theme() theme {
+	name := tm.Prefs.Theme
+	if name == "" {
+		name = defaultTheme
+	}
+	if custom, ok := tm.Prefs.Themes[name]; ok {
+		return customTheme(custom)
+	}
+	return themes[name]
+}
+
+// themeNames lists the built in themes and those of the preferences
+func themeNames(prefs config.Preferences) string {
+	var names []string
+	for name := range themes {
+		names = append(names, name)
+	}
+	for name := range prefs.Themes {
+		if _, ok := themes[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func colorNames() string {
+	var names []string
+	for name := range colors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func validColor(name string) bool {
+	_, ok := colorCode(name)
+	return name == "" || ok
+}
+
+// colorCode is the escape code of a color name or 256-color number
+func colorCode(name string) (string, bool) {
+	if code, ok := colors[name]; ok {
+		return code, true
+	}
+	if n, err := strconv.Atoi(name); err == nil && n >= 0 && n <= 255 {
+		return fmt.Sprintf("\033[38;5;%dm", n), true
+	}
+	return "", false
+}
+
+// colorize shows text in the named color, unknown names leave it plain
+func colorize(text, name string) string {
+	code, ok := colorCode(name)
+	if !ok || !colorEnabled {
+		return text
+	}
+	return code + text + resetColor
+}
+
+// displayColor is the color the timer is shown in under the theme
+func displayColor(t *timer.Timer, th theme) string {
+	switch {
+	case t.IsPaused && th.paused != "":
+		return th.paused
+	case !t.IsPaused && th.ending != "" && t.EndingSoon():
+		return th.ending
+	case t.PhaseColor() != "":
+		return t.PhaseColor()
+	case t.State.IsWork || t.IsStopwatch():
+		return th.work
+	}
+	return th.rest
+}
@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"multi-timer/config"
+)
+
+// withColor turns colored output on for the test
+func withColor(t *testing.T) {
+	saved := colorEnabled
+	colorEnabled = true
+	t.Cleanup(func() { colorEnabled = saved })
+}
+
+func TestPhaseColorAndSound(t *testing.T) {
+	withColor(t)
+	tm, notifier := newTestTerminal(t)
+	timers := addTimers(tm, config.TimerConfig{
+		Name: "Study",
+		Phases: []config.TimerPhase{
+			{WorkDuration: 10 * time.Minute, BreakDuration: 5 * time.Minute, PhaseColor: "magenta", PhaseSound: "bell"},
+			{WorkDuration: 10 * time.Minute, BreakDuration: 5 * time.Minute, PhaseColor: "cyan"},
+		},
+		MaxCycles: 1,
+		WorkSound: "tick",
+	})
+	line := func() string {
+		tm.Lock()
+		defer tm.Unlock()
+		return tm.timerLine(0, timers[0])
+	}
+
+	advance(tm, 5*time.Minute)
+	if got := line(); !strings.Contains(got, colors["magenta"]+"Study - Work") {
+		t.Errorf("line in phase 1 = %q, want it magenta", got)
+	}
+	advance(tm, 10*time.Minute)
+	if got := line(); !strings.Contains(got, colors["cyan"]+"Study - Work") {
+		t.Errorf("line in phase 2 = %q, want it cyan", got)
+	}
+	// Phase 1's break plays its sound, phase 2 has none and falls back on
+	// the timer's work sound
+	if want := []string{"bell", "tick"}; !slices.Equal(notifier.sounds, want) {
+		t.Errorf("sounds = %v, want %v", notifier.sounds, want)
+	}
+
+	// The last minute of a segment shows in the theme's ending color
+	advance(tm, 9*time.Minute+30*time.Second)
+	if got := line(); !strings.Contains(got, colors["red"]+"Study - Work") {
+		t.Errorf("line in the last minute = %q, want the ending color", got)
+	}
+}
+
+func TestThemeColorsRendered(t *testing.T) {
+	withColor(t)
+	tm, _ := newTestTerminal(t)
+	timers := addTimers(tm,
+		pomodoro("Tea", 25*time.Minute, 5*time.Minute, 4),
+		pomodoro("Read", 2*time.Minute, 10*time.Minute, 4),
+		pomodoro("Walk", 10*time.Minute, 5*time.Minute, 4),
+		pomodoro("Stretch", 5*time.Minute+30*time.Second, 5*time.Minute, 4),
+	)
+	tm.Prefs.Themes = map[string]config.ThemeColors{"mine": {Work: "208", Break: "white", Paused: "blue", Ending: "magenta", Selected: "cyan"}}
+	advance(tm, 5*time.Minute)
+	tm.Lock()
+	timers[2].IsPaused = true
+	tm.selected = 1
+	tm.Unlock()
+
+	var out strings.Builder
+	tm.Out = &out
+	for name, want := range map[string][]string{
+		"solarized": {
+			colors["magenta"] + ">" + resetColor + " 1. " + colors["yellow"] + "Tea - Work",
+			colors["cyan"] + "Read - Break",
+			colors["gray"] + "Walk - Work",
+			colors["red"] + "Stretch - Work",
+		},
+		"mine": {
+			colors["cyan"] + ">" + resetColor + " 1. \033[38;5;208mTea - Work",
+			colors["white"] + "Read - Break",
+			colors["blue"] + "Walk - Work",
+			colors["magenta"] + "Stretch - Work",
+		},
+	} {
+		tm.Lock()
+		tm.Prefs.Theme = name
+		tm.Unlock()
+		out.Reset()
+		tm.displayTimers(false)
+		for _, colored := range want {
+			if !strings.Contains(out.String(), colored) {
+				t.Errorf("theme %s: display has no %q:\n%q", name, colored, out.String())
+			}
+		}
+	}
+
+	tm.Lock()
+	tm.Prefs.Theme = "off"
+	tm.Unlock()
+	out.Reset()
+	tm.displayTimers(false)
+	for _, code := range colors {
+		if strings.Contains(out.String(), code) {
+			t.Errorf("display with the theme off has color %q:\n%q", code, out.String())
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"multi-timer/config"
+	"multi-timer/manager"
+)
+
+// diagnose checks every config for values out of range and references to
+// sounds, colors and timers that don't exist, one line per problem
+func diagnose(configs []config.TimerConfig) []string {
+	names := make(map[string]bool)
+	for _, config := range configs {
+		if len(config.Phases) > 0 {
+			names[config.Name] = true
+		}
+	}
+	next := make(map[string]string)
+	for _, config := range configs {
+		next[config.Name] = config.NextTimer
+	}
+
+	var report []string
+	for _, cfg := range configs {
+		problem := func(format string, args ...any) {
+			report = append(report, cfg.Name+": "+fmt.Sprintf(format, args...))
+		}
+		if err := config.CheckLimits(cfg); err != nil {
+			problem("%v", err)
+		}
+		if !config.ValidType(cfg.Type) {
+			problem("Type: unknown type %q, use %s, %s or leave it empty", cfg.Type, config.TypeCountdown, config.TypeAlarm)
+		}
+		if cfg.Type == config.TypeAlarm {
+			if _, err := config.UntilAlarm(cfg.AlarmAt, time.Now()); err != nil {
+				problem("AlarmAt: %v", err)
+			}
+		}
+		for field, sound := range map[string]string{
+			"StartSound":    cfg.StartSound,
+			"CompleteSound": cfg.CompleteSound,
+			"WorkSound":     cfg.WorkSound,
+			"BreakSound":    cfg.BreakSound,
+		} {
+			if msg := soundProblem(sound); msg != "" {
+				problem("%s: %s", field, msg)
+			}
+		}
+		for i, phase := range cfg.Phases {
+			if msg := soundProblem(phase.PhaseSound); msg != "" {
+				problem("phase %d PhaseSound: %s", i+1, msg)
+			}
+			if !validColor(phase.PhaseColor) {
+				problem("phase %d PhaseColor: unknown color %q, use one of %s", i+1, phase.PhaseColor, colorNames())
+			}
+		}
+		if cfg.NextTimer != "" && !names[cfg.NextTimer] {
+			problem("NextTimer: there is no timer named %q", cfg.NextTimer)
+		}
+		for _, name := range cfg.PausesOnWork {
+			if !names[name] {
+				problem("PausesOnWork: there is no timer named %q", name)
+			}
+		}
+		// Follow the chain the way startNext does
+		chain := []string{cfg.Name}
+		for name := cfg.NextTimer; name != "" && names[name]; name = next[name] {
+			if name == cfg.Name {
+				problem("NextTimer: the chain %s loops back to it", chainText(append(chain, name)))
+				break
+			}
+			if slices.Contains(chain, name) {
+				break // a loop further on, reported for the timers in it
+			}
+			chain = append(chain, name)
+		}
+	}
+	slices.Sort(report)
+	return report
+}
+
+func chainText(chain []string) string {
+	text := chain[0]
+	for _, name := range chain[1:] {
+		text += " -> " + name
+	}
+	return text
+}
+
+// soundProblem describes what's wrong with a sound setting, empty for nothing
+func soundProblem(sound string) string {
+	if !manager.ValidSound(sound) {
+		return fmt.Sprintf("unknown sound %q, use one of %s or an audio file", sound, manager.SoundNames())
+	}
+	if !manager.IsAudioFile(sound) {
+		return ""
+	}
+	if _, err := os.Stat(sound); err != nil {
+		return fmt.Sprintf("can't read audio file %q", sound)
+	}
+	return ""
+}
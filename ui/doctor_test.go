@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"multi-timer/config"
+)
+
+func sampleConfigs() []config.TimerConfig {
+	return []config.TimerConfig{
+		{
+			Name:      "Tea",
+			NotifText: "Tea time",
+			Phases: []config.TimerPhase{
+				{WorkDuration: 25 * time.Minute, BreakDuration: 5 * time.Minute, Outcome: "draft", PhaseColor: "green"},
+				{WorkDuration: 50 * time.Minute, BreakDuration: config.UntilResumed, WorkScale: 2},
+			},
+			MaxCycles:         4,
+			BaseDuration:      25 * time.Minute,
+			WarmupDuration:    90 * time.Second,
+			Tags:              []string{"study", "deep"},
+			Milestones:        []int{25, 50},
+			AutoPauseOnIdle:   true,
+			RecentCompletions: []time.Time{time.Date(2024, 3, 4, 9, 30, 0, 0, time.UTC)},
+		},
+		{
+			Name:      "Stretch",
+			NotifText: "Stretch",
+			Phases:    []config.TimerPhase{{WorkDuration: time.Hour + 30*time.Minute, BreakDuration: 10 * time.Minute}},
+			MaxCycles: -1,
+		},
+	}
+}
+
+func TestDiagnoseHealthy(t *testing.T) {
+	if report := diagnose(sampleConfigs()); len(report) != 0 {
+		t.Errorf("report for good configs = %q, want none", report)
+	}
+}
+
+func TestDiagnoseBrokenReferences(t *testing.T) {
+	dir := t.TempDir()
+	present := filepath.Join(dir, "bell.wav")
+	if err := os.WriteFile(present, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := func(name string) config.TimerConfig { return pomodoro(name, 25*time.Minute, 5*time.Minute, 4) }
+
+	missingSound := cfg("Tea")
+	missingSound.CompleteSound = filepath.Join(dir, "gong.wav")
+	missingSound.StartSound = present
+	unknownSound := cfg("Read")
+	unknownSound.WorkSound = "kazoo"
+	badColor := cfg("Walk")
+	badColor.Phases[0].PhaseColor = "chartreuse"
+	dangling := cfg("Stretch")
+	dangling.NextTimer = "Nap"
+	dangling.PausesOnWork = []string{"Music"}
+	loopA, loopB := cfg("A"), cfg("B")
+	loopA.NextTimer, loopB.NextTimer = "B", "A"
+
+	report := diagnose([]config.TimerConfig{missingSound, unknownSound, badColor, dangling, loopA, loopB})
+	want := []string{
+		"A: NextTimer: the chain A -> B -> A loops back to it",
+		"B: NextTimer: the chain B -> A -> B loops back to it",
+		"Read: WorkSound: unknown sound \"kazoo\"",
+		"Stretch: NextTimer: there is no timer named \"Nap\"",
+		"Stretch: PausesOnWork: there is no timer named \"Music\"",
+		"Tea: CompleteSound: can't read audio file",
+		"Walk: phase 1 PhaseColor: unknown color \"chartreuse\"",
+	}
+	if len(report) != len(want) {
+		t.Fatalf("report = %q, want %d problems", report, len(want))
+	}
+	for i, problem := range want {
+		if !strings.HasPrefix(report[i], problem) {
+			t.Errorf("problem %d = %q, want %q", i+1, report[i], problem)
+		}
+	}
+	if slices.ContainsFunc(report, func(line string) bool { return strings.Contains(line, "StartSound") }) {
+		t.Errorf("report = %q, want the audio file that exists left out", report)
+	}
+}
@@ -0,0 +1,244 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"multi-timer/config"
+	"multi-timer/timer"
+)
+
+// editTimer asks for a timer's new name, notification text, phase lengths and
+// cycles, empty answers keep what it has, and applies them to the running
+// timer and its saved config
+func (tm *Terminal) editTimer(num int) error {
+	tm.Lock()
+	if num < 1 || num > len(tm.ActiveTimers) {
+		tm.Unlock()
+		return fmt.Errorf("no timer %d", num)
+	}
+	timer := tm.ActiveTimers[num-1]
+	cfg := tm.editableConfig(timer)
+	stopwatch := timer.IsStopwatch()
+	tm.Unlock()
+
+	// No lock while asking, the display keeps redrawing meanwhile
+	if name := tm.readLine(fmt.Sprintf("Name (empty keeps %s): ", cfg.Name)); name != "" {
+		cfg.Name = name
+	}
+	if !stopwatch {
+		if err := tm.editConfig(&cfg); err != nil {
+			return err
+		}
+		if err := config.CheckLimits(cfg); err != nil {
+			return err
+		}
+	}
+
+	return tm.applyEdit(timer, cfg)
+}
+
+// applyEdit puts an edited config on the timer and in its place among the
+// saved configs, and saves them
+func (tm *Terminal) applyEdit(t *timer.Timer, cfg config.TimerConfig) error {
+	tm.Lock()
+	// The timer may have completed or been deleted while it was edited
+	num := slices.Index(tm.ActiveTimers, t) + 1
+	if num == 0 {
+		tm.Unlock()
+		return fmt.Errorf("%s is gone", t.State.Name)
+	}
+	oldName := t.State.Name
+	j := tm.PairConfigs()[num-1]
+	t.State.Name = cfg.Name
+	if !t.IsStopwatch() {
+		t.ApplyConfig(cfg)
+	}
+	if j >= 0 {
+		tm.Configs[j] = cfg
+	}
+	if cfg.Name != oldName {
+		tm.renameReferences(oldName, cfg.Name)
+	}
+	tm.Unlock()
+	return tm.SaveConfigs()
+}
+
+// editableConfig is the saved config of the timer, or one made from the
+// timer when it has none. Must be called with tm locked.
+func (tm *Terminal) editableConfig(t *timer.Timer) config.TimerConfig {
+	num := slices.Index(tm.ActiveTimers, t) + 1
+	if num > 0 {
+		if j := tm.PairConfigs()[num-1]; j >= 0 {
+			return tm.Configs[j]
+		}
+	}
+	return t.Config()
+}
+
+// phaseList writes the work and break lengths of the phases like
+// "25:00/05:00, 50:00/10:00", the form parsePhaseList reads. Countdowns only
+// have the work length.
+func phaseList(cfg config.TimerConfig) string {
+	parts := make([]string, len(cfg.Phases))
+	for i, phase := range cfg.Phases {
+		parts[i] = timer.ClockTime(phase.WorkDuration)
+		switch {
+		case cfg.Type == config.TypeCountdown:
+		case phase.BreakDuration == config.UntilResumed:
+			parts[i] += "/o"
+		default:
+			parts[i] += "/" + timer.ClockTime(phase.BreakDuration)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// parsePhaseList sets the phases of the config from a list phaseList wrote.
+// Phases that were there keep their other settings, a break of o waits for
+// resume.
+func parsePhaseList(cfg config.TimerConfig, input string) (config.TimerConfig, error) {
+	var phases []config.TimerPhase
+	for i, part := range strings.Split(input, ",") {
+		work, rest, hasBreak := strings.Cut(strings.TrimSpace(part), "/")
+		var phase config.TimerPhase
+		if i < len(cfg.Phases) {
+			phase = cfg.Phases[i]
+		}
+		d, err := config.ParseDuration(strings.TrimSpace(work))
+		if err != nil || d <= 0 {
+			return cfg, fmt.Errorf("phase %d work: %s", i+1, config.DurationProblem(err))
+		}
+		phase.WorkDuration, phase.WorkScale = d, 0
+		switch rest = strings.TrimSpace(rest); {
+		case cfg.Type == config.TypeCountdown:
+			if hasBreak {
+				return cfg, fmt.Errorf("phase %d: a countdown has no breaks", i+1)
+			}
+		case !hasBreak:
+			return cfg, fmt.Errorf("phase %d: use work/break like 25:00/05:00", i+1)
+		case strings.EqualFold(rest, "o"):
+			phase.BreakDuration, phase.BreakScale = config.UntilResumed, 0
+		default:
+			d, err := config.ParseDuration(rest)
+			if err != nil || d < 0 {
+				return cfg, fmt.Errorf("phase %d break: %s", i+1, config.DurationProblem(err))
+			}
+			phase.BreakDuration, phase.BreakScale = d, 0
+		}
+		phases = append(phases, phase)
+	}
+	cfg.Phases = phases
+	return cfg, config.CheckLimits(cfg)
+}
+
+// editConfig asks for the notification text, phases and cycles of the config
+func (tm *Terminal) editConfig(cfg *config.TimerConfig) error {
+	if text := tm.readLine(fmt.Sprintf("Notification text (empty keeps %s): ", cfg.NotifText)); text != "" {
+		cfg.NotifText = text
+	}
+
+	if cfg.Type == config.TypeAlarm {
+		if at := tm.readLine(fmt.Sprintf("Alarm time (HH:MM, empty keeps %s): ", cfg.AlarmAt)); at != "" {
+			if _, err := config.UntilAlarm(at, tm.Now()); err != nil {
+				return err
+			}
+			cfg.AlarmAt = at
+		}
+		return nil
+	}
+
+	phases := make([]config.TimerPhase, len(cfg.Phases))
+	copy(phases, cfg.Phases)
+	for i := range phases {
+		phase := &phases[i]
+		work, err := editDuration(tm.readLine(fmt.Sprintf("Phase %d work (MM:SS, HH:MM:SS or minutes, empty keeps %s): ", i+1, timer.ClockTime(phase.WorkDuration))))
+		if err != nil {
+			return err
+		}
+		if work > 0 {
+			phase.WorkDuration, phase.WorkScale = work, 0
+		}
+		if cfg.Type == config.TypeCountdown {
+			continue
+		}
+		current := "until resumed"
+		if phase.BreakDuration != config.UntilResumed {
+			current = timer.ClockTime(phase.BreakDuration)
+		}
+		input := tm.readLine(fmt.Sprintf("Phase %d break (MM:SS, HH:MM:SS or minutes, o to wait for resume, empty keeps %s): ", i+1, current))
+		if strings.ToLower(input) == "o" {
+			phase.BreakDuration, phase.BreakScale = config.UntilResumed, 0
+			continue
+		}
+		rest, err := editDuration(input)
+		if err != nil {
+			return err
+		}
+		if input != "" {
+			phase.BreakDuration, phase.BreakScale = rest, 0
+		}
+	}
+	cfg.Phases = phases
+
+	if cfg.Type == config.TypeCountdown {
+		return nil
+	}
+	current := "unlimited"
+	if cfg.MaxCycles >= 0 {
+		current = strconv.Itoa(cfg.MaxCycles)
+	}
+	switch input := tm.readLine(fmt.Sprintf("Cycles (u for unlimited, number for fixed cycles, empty keeps %s): ", current)); input {
+	case "":
+	case "u":
+		cfg.MaxCycles = -1
+	default:
+		cycles, err := strconv.Atoi(input)
+		if err != nil || cycles < 1 {
+			return fmt.Errorf("invalid number of cycles %q", input)
+		}
+		cfg.MaxCycles = cycles
+	}
+	return nil
+}
+
+// editDuration reads an answer to a duration question, 0 for an empty one
+func editDuration(input string) (time.Duration, error) {
+	if input == "" {
+		return 0, nil
+	}
+	d, err := config.ParseDuration(input)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("%s", config.DurationProblem(err))
+	}
+	return d, nil
+}
+
+// renameReferences points the configs that start or pause the renamed timer
+// at its new name. Must be called with tm locked.
+func (tm *Terminal) renameReferences(oldName, newName string) {
+	for i := range tm.Configs {
+		config := &tm.Configs[i]
+		if config.NextTimer == oldName {
+			config.NextTimer = newName
+		}
+		for k, name := range config.PausesOnWork {
+			if name == oldName {
+				config.PausesOnWork[k] = newName
+			}
+		}
+	}
+	for _, timer := range tm.ActiveTimers {
+		if timer.Next == oldName {
+			timer.Next = newName
+		}
+		for k, name := range timer.PausesOnWork {
+			if name == oldName {
+				timer.PausesOnWork[k] = newName
+			}
+		}
+	}
+}
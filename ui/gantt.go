@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+
+	"multi-timer/config"
+	"multi-timer/timer"
+)
+
+// terminalWidth is the width of the terminal the output goes to, else as the
+// shell reports it, or 80
+func terminalWidth() int {
+	if n, _, err := term.GetSize(os.Stdout.Fd()); err == nil && n > 20 {
+		return n
+	}
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 20 {
+		return n
+	}
+	return 80
+}
+
+func ganttRow(seg timer.Segment) string {
+	switch {
+	case seg.Stage == timer.StageWarmup:
+		return "Warmup"
+	case seg.Stage == timer.StageCooldown:
+		return "Cooldown"
+	case seg.IsWork:
+		return fmt.Sprintf("P%d work", seg.Phase+1)
+	default:
+		return fmt.Sprintf("P%d break", seg.Phase+1)
+	}
+}
+
+// renderGantt charts the schedule of a config with one row per kind of
+// segment and widths proportional to the durations, width columns in all
+func renderGantt(cfg config.TimerConfig, width int) string {
+	if len(cfg.Phases) == 0 {
+		return "No phases to chart.\n"
+	}
+	segments, complete := timer.ScheduleSegments(cfg)
+
+	var rows []string
+	rowOf := make(map[string]int)
+	var total time.Duration
+	for _, seg := range segments {
+		label := ganttRow(seg)
+		if _, ok := rowOf[label]; !ok {
+			rowOf[label] = len(rows)
+			rows = append(rows, label)
+		}
+		if seg.Duration > 0 {
+			total += seg.Duration
+		}
+	}
+	labelWidth := 0
+	for _, label := range rows {
+		labelWidth = max(labelWidth, len(label))
+	}
+	barWidth := max(width-labelWidth-3, 10)
+
+	bars := make([][]rune, len(rows))
+	for i := range bars {
+		bars[i] = []rune(strings.Repeat(" ", barWidth))
+	}
+	var elapsed time.Duration
+	for _, seg := range segments {
+		bar := bars[rowOf[ganttRow(seg)]]
+		from := 0
+		if total > 0 {
+			from = int(int64(elapsed) * int64(barWidth) / int64(total))
+		}
+		if seg.Duration == config.UntilResumed {
+			// No length until it's resumed, just mark where it happens
+			if from < barWidth {
+				bar[from] = '?'
+			}
+			continue
+		}
+		elapsed += seg.Duration
+		to := int(int64(elapsed) * int64(barWidth) / int64(total))
+		mark := '█'
+		if !seg.IsWork {
+			mark = '░'
+		}
+		if to == from && seg.Duration > 0 {
+			to++ // keep short segments visible
+		}
+		for c := from; c < to && c < barWidth; c++ {
+			bar[c] = mark
+		}
+	}
+
+	var b strings.Builder
+	for i, label := range rows {
+		fmt.Fprintf(&b, "%-*s |%s|\n", labelWidth, label, string(bars[i]))
+	}
+	end := timer.HoursMinutes(total)
+	if !complete {
+		end += " and repeating"
+	}
+	fmt.Fprintf(&b, "%-*s  0m%*s\n", labelWidth, "", barWidth-2, end)
+	fmt.Fprintf(&b, "%-*s  █ work  ░ break  ? until resumed\n", labelWidth, "")
+	return b.String()
+}
@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"multi-timer/config"
+	"multi-timer/timer"
+)
+
+func TestGanttLayout(t *testing.T) {
+	cfg := config.TimerConfig{
+		Name: "Study",
+		Phases: []config.TimerPhase{
+			{WorkDuration: 20 * time.Minute, BreakDuration: 10 * time.Minute},
+			{WorkDuration: 40 * time.Minute, BreakDuration: 20 * time.Minute},
+		},
+		MaxCycles:        1,
+		WarmupDuration:   10 * time.Minute,
+		CooldownDuration: 20 * time.Minute,
+	}
+	// 2h in 24 columns of 5 minutes
+	got := renderGantt(cfg, len("Cooldown")+3+24)
+	want := strings.Join([]string{
+		"Warmup   |██                      |",
+		"P1 work  |  ████                  |",
+		"P1 break |      ░░                |",
+		"P2 work  |        ████████        |",
+		"P2 break |                ░░░░    |",
+		"Cooldown |                    ████|",
+		"          0m                 2h00m",
+		"          █ work  ░ break  ? until resumed",
+		"",
+	}, "\n")
+	if got != want {
+		t.Errorf("chart =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGanttPreviewsUnlimited(t *testing.T) {
+	config := pomodoro("Tea", 25*time.Minute, 5*time.Minute, -1)
+	segments, complete := timer.ScheduleSegments(config)
+	if complete || len(segments) != timer.GanttPreview {
+		t.Errorf("%d segments, complete %t, want a preview of %d", len(segments), complete, timer.GanttPreview)
+	}
+	// 8 cycles of 30m
+	if got := renderGantt(config, 40); !strings.Contains(got, "4h00m and repeating") {
+		t.Errorf("chart of an unlimited timer =\n%s", got)
+	}
+}
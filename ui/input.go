@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The terminal is read on a goroutine of its own that hands over whole
+// lines, so while a command or the answers of the add dialog are typed the
+// main loop is free to redraw the timers whenever the update loop asks.
+
+// readLines sends every line read from r, trimmed, and closes the channel once
+// the input ends
+func readLines(r io.Reader) <-chan string {
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			lines <- strings.TrimSpace(scanner.Text())
+		}
+	}()
+	return lines
+}
+
+// nextLine waits for the next line of input, redrawing the timers meanwhile.
+// It's false once the input has ended.
+func (tm *Terminal) nextLine() (string, bool) {
+	for {
+		select {
+		case line, ok := <-tm.input:
+			return line, ok
+		case <-tm.DisplayChan:
+			tm.displayTimers(true)
+		}
+	}
+}
+
+// readLine prompts for one answer, empty once the input has ended
+func (tm *Terminal) readLine(prompt string) string {
+	fmt.Print(prompt)
+	line, _ := tm.nextLine()
+	return line
+}
@@ -0,0 +1,22 @@
+package ui
+
+import (
+	"strings"
+	"time"
+)
+
+// keyAdjustment maps a key press to the change it makes to the selected timer
+func keyAdjustment(key rune, step time.Duration) (time.Duration, bool) {
+	switch key {
+	case '+':
+		return step, true
+	case '-':
+		return -step, true
+	}
+	return 0, false
+}
+
+// isAdjustKeys reports whether the input is only +/- presses, e.g. "++"
+func isAdjustKeys(input string) bool {
+	return input != "" && strings.Trim(input, "+-") == ""
+}
@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"multi-timer/timer"
+)
+
+func TestKeyAdjustment(t *testing.T) {
+	step := 2 * time.Minute
+	for key, want := range map[rune]time.Duration{'+': step, '-': -step} {
+		if got, ok := keyAdjustment(key, step); !ok || got != want {
+			t.Errorf("keyAdjustment(%q) = %v, %t, want %v", key, got, ok, want)
+		}
+	}
+	for _, key := range "x0= " {
+		if _, ok := keyAdjustment(key, step); ok {
+			t.Errorf("keyAdjustment(%q) adjusts", key)
+		}
+	}
+}
+
+func TestIsAdjustKeys(t *testing.T) {
+	for input, want := range map[string]bool{"+": true, "--": true, "+-+": true, "": false, "+1": false, "a": false} {
+		if got := isAdjustKeys(input); got != want {
+			t.Errorf("isAdjustKeys(%q) = %t, want %t", input, got, want)
+		}
+	}
+}
+
+func TestAdjustClampsAtZero(t *testing.T) {
+	tr := timer.TimerFromConfig(pomodoro("Tea", 25*time.Minute, 5*time.Minute, 4))
+	tr.State.CurrentTime = 90 * time.Second
+	for _, key := range "---" {
+		d, _ := keyAdjustment(key, time.Minute)
+		tr.AddTime(d)
+	}
+	if tr.State.CurrentTime != 0 {
+		t.Errorf("%v left after taking 3m off 1m30s, want 0", tr.State.CurrentTime)
+	}
+	d, _ := keyAdjustment('+', time.Minute)
+	tr.AddTime(d)
+	if tr.State.CurrentTime != time.Minute {
+		t.Errorf("%v left after adding a minute to nothing, want 1m", tr.State.CurrentTime)
+	}
+}
@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"multi-timer/manager"
+)
+
+// How many notifications the pinned pane shows
+const paneRows = 5
+
+// renderPane lists rows notifications oldest first, ending offset entries
+// before the most recent one
+func renderPane(log []manager.SentNotification, offset, rows int) []string {
+	end := max(len(log)-offset, 0)
+	start := max(end-rows, 0)
+	lines := make([]string, 0, end-start)
+	for _, n := range log[start:end] {
+		lines = append(lines, fmt.Sprintf("%s %s - %s", n.At.Format("15:04:05"), n.Timer, n.Message))
+	}
+	return lines
+}
+
+// isScrollKeys reports whether the input is only < and > presses, which
+// scroll the pane to older and newer notifications
+func isScrollKeys(input string) bool {
+	return input != "" && strings.Trim(input, "<>") == ""
+}
+
+// scrollPane moves the pane one notification back for every < and forward
+// for every >. Must be called with tm locked.
+func (tm *Terminal) scrollPane(keys string) {
+	n := 0
+	for _, key := range keys {
+		if key == '<' {
+			n++
+		} else {
+			n--
+		}
+	}
+	tm.ScrollPane(n, paneRows)
+}
@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"multi-timer/manager"
+)
+
+func TestLastNotificationUpdates(t *testing.T) {
+	tm, notifier := newTestTerminal(t)
+	addTimers(tm,
+		pomodoro("Tea", 2*time.Minute, time.Minute, 4),
+		pomodoro("Read", 10*time.Minute, time.Minute, 4),
+	)
+	var out strings.Builder
+	tm.Out = &out
+
+	last := func() manager.SentNotification {
+		tm.Lock()
+		defer tm.Unlock()
+		sent := tm.Notifications()
+		if len(sent) == 0 {
+			t.Fatal("no notification was logged")
+		}
+		return sent[len(sent)-1]
+	}
+	check := func(at string) {
+		t.Helper()
+		n := last()
+		sent := notifier.notifications[len(notifier.notifications)-1]
+		if got := n.Timer + ": " + n.Message; got != sent {
+			t.Errorf("last notification = %q, want %q", got, sent)
+		}
+		if got := n.At.Format("15:04:05"); got != at {
+			t.Errorf("last notification at %s, want %s", got, at)
+		}
+		out.Reset()
+		tm.displayTimers(false)
+		want := "Last notification: Tea at " + at + " - " + n.Message
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("display has no %q:\n%s", want, out.String())
+		}
+	}
+
+	advance(tm, 2*time.Minute)
+	check("09:02:00")
+	advance(tm, time.Minute)
+	check("09:03:00")
+}
+
+func TestPaneShowsMostRecent(t *testing.T) {
+	tm, _ := newTestTerminal(t)
+	tm.Prefs.PinnedPane = true
+	addTimers(tm, pomodoro("Tea", time.Minute, time.Minute, -1))
+	// A notification a minute, 09:01 to 09:08
+	advance(tm, 8*time.Minute)
+
+	var out strings.Builder
+	tm.Out = &out
+	tm.displayTimers(false)
+	_, pane, found := strings.Cut(out.String(), "--- Notifications (< older, > newer) ---\n")
+	if !found {
+		t.Fatalf("display has no pane:\n%s", out.String())
+	}
+	var times []string
+	for _, line := range strings.Split(strings.TrimSpace(pane), "\n") {
+		line = strings.TrimPrefix(line, clearLine)
+		if at, _, ok := strings.Cut(line, " Tea - "); ok {
+			times = append(times, at)
+		}
+	}
+	want := []string{"09:04:00", "09:05:00", "09:06:00", "09:07:00", "09:08:00"}
+	if !slices.Equal(times, want) {
+		t.Errorf("pane shows notifications at %v, want %v:\n%s", times, want, pane)
+	}
+
+	tm.Lock()
+	tm.scrollPane("<<")
+	lines := renderPane(tm.Notifications(), tm.PaneOffset(), paneRows)
+	tm.Unlock()
+	if len(lines) != paneRows || !strings.HasPrefix(lines[0], "09:02:00") || !strings.HasPrefix(lines[4], "09:06:00") {
+		t.Errorf("pane scrolled back two = %q, want 09:02 to 09:06", lines)
+	}
+}
@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTotalBreaksToggle(t *testing.T) {
+	tm, _ := newTestTerminal(t)
+	addTimers(tm, pomodoro("Tea", 25*time.Minute, 5*time.Minute, 2))
+	advance(tm, 27*time.Minute) // 2m into the first break
+
+	aggregate := func() (time.Duration, string) {
+		tm.Lock()
+		defer tm.Unlock()
+		left, _ := tm.AggregateRemaining()
+		return left, tm.header()
+	}
+	includeBreaks := func(on bool) {
+		tm.Lock()
+		tm.Prefs.IncludeBreaks = on
+		tm.Unlock()
+	}
+
+	// Only the second cycle's work
+	if left, header := aggregate(); left != 25*time.Minute || !strings.HasSuffix(header, " 25m of work left") {
+		t.Errorf("without breaks: %v, header %q, want 25m of work", left, header)
+	}
+	includeBreaks(true)
+	// The rest of this break, the work and the last break
+	if left, header := aggregate(); left != 33*time.Minute || !strings.HasSuffix(header, " 33m left with breaks") {
+		t.Errorf("with breaks: %v, header %q, want 33m", left, header)
+	}
+	includeBreaks(false)
+	if left, _ := aggregate(); left != 25*time.Minute {
+		t.Errorf("switched back off: %v, want 25m of work", left)
+	}
+}
@@ -0,0 +1,26 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"multi-timer/config"
+	"multi-timer/timer"
+)
+
+// PresetLine describes a preset for the presets command
+func PresetLine(preset config.TimerConfig) string {
+	var phases []string
+	for _, phase := range preset.Phases {
+		rest := "until resumed"
+		if phase.BreakDuration != config.UntilResumed {
+			rest = timer.HoursMinutes(phase.BreakDuration)
+		}
+		phases = append(phases, timer.HoursMinutes(phase.WorkDuration)+"/"+rest)
+	}
+	cycles := "unlimited cycles"
+	if preset.MaxCycles >= 0 {
+		cycles = fmt.Sprintf("%d cycles", preset.MaxCycles)
+	}
+	return fmt.Sprintf("%s: %s, %s", preset.Name, strings.Join(phases, ", "), cycles)
+}
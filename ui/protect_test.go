@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPauseBlockedInDeepWork(t *testing.T) {
+	tm, _ := newTestTerminal(t)
+	config := pomodoro("Essay", 10*time.Minute, 5*time.Minute, 4)
+	config.ProtectedCycles = []int{1, 2}
+	timers := addTimers(tm, config)
+	timer := timers[0]
+	advance(tm, 5*time.Minute)
+
+	tm.Lock()
+	if tm.TogglePause(timer) || timer.IsPaused {
+		t.Error("paused a deep work cycle")
+	}
+	if got := tm.timerLine(0, timer); !strings.HasSuffix(got, " (DEEP WORK)") {
+		t.Errorf("line = %q, want it marked deep work", got)
+	}
+	tm.Unlock()
+	if err := tm.Extend(1, 5*time.Minute); err == nil || !strings.Contains(err.Error(), "deep work") {
+		t.Errorf("adding time to a deep work cycle: error %v, want one about deep work", err)
+	}
+
+	// Cycle 3 is an ordinary one
+	advance(tm, 25*time.Minute)
+	tm.Lock()
+	defer tm.Unlock()
+	if timer.State.Cycles != 3 {
+		t.Fatalf("timer at cycle %d, want 3", timer.State.Cycles)
+	}
+	if !tm.TogglePause(timer) || !timer.IsPaused {
+		t.Error("couldn't pause outside the deep work cycles")
+	}
+	if got := tm.timerLine(0, timer); strings.Contains(got, "DEEP WORK") {
+		t.Errorf("line = %q, want no deep work mark", got)
+	}
+}
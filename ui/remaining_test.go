@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"multi-timer/timer"
+)
+
+func TestAggregateRemaining(t *testing.T) {
+	tm, _ := newTestTerminal(t)
+	timers := addTimers(tm,
+		pomodoro("Tea", 25*time.Minute, 5*time.Minute, 2),
+		pomodoro("Walk", 10*time.Minute, 5*time.Minute, 1),
+		pomodoro("Paused", 30*time.Minute, 5*time.Minute, 1),
+		pomodoro("Forever", 30*time.Minute, 5*time.Minute, -1),
+	)
+	tm.Lock()
+	timers[2].IsPaused = true
+	tm.ActiveTimers = append(tm.ActiveTimers, timer.NewStopwatch("Call"))
+	tm.Unlock()
+	advance(tm, 5*time.Minute)
+
+	tm.Lock()
+	defer tm.Unlock()
+	// Tea has 20m of its first cycle's work and 25m of the second's, Walk 5m
+	if left, ok := tm.AggregateRemaining(); !ok || left != 50*time.Minute {
+		t.Errorf("aggregate = %v, %t, want 50m of work", left, ok)
+	}
+	if got := tm.header(); !strings.HasSuffix(got, " 50m of work left") {
+		t.Errorf("header = %q, want the 50m of work left", got)
+	}
+
+	// Tea's two breaks and Walk's
+	tm.Prefs.IncludeBreaks = true
+	if left, ok := tm.AggregateRemaining(); !ok || left != time.Hour+5*time.Minute {
+		t.Errorf("aggregate with breaks = %v, %t, want 1h05m", left, ok)
+	}
+	if got := tm.header(); !strings.HasSuffix(got, " 1h05m left with breaks") {
+		t.Errorf("header = %q, want the 1h05m left with breaks", got)
+	}
+}
+
+func TestNoAggregateWithoutFixedTimers(t *testing.T) {
+	tm, _ := newTestTerminal(t)
+	addTimers(tm, pomodoro("Forever", 30*time.Minute, 5*time.Minute, -1))
+	tm.Lock()
+	defer tm.Unlock()
+	if _, ok := tm.AggregateRemaining(); ok {
+		t.Error("an unlimited timer counted toward the aggregate")
+	}
+	if got := tm.header(); strings.Contains(got, "left") {
+		t.Errorf("header = %q, want no time left", got)
+	}
+}
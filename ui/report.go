@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"multi-timer/manager"
+	"multi-timer/timer"
+)
+
+// renderReport lays out the rows with a total for the bounded timers
+func renderReport(rows []manager.ReportRow) string {
+	var b strings.Builder
+	var total manager.ReportRow
+	total.Name = "Overall"
+	total.Bounded = true
+	for _, row := range rows {
+		b.WriteString(reportLine(row))
+		if row.Bounded {
+			total.Planned += row.Planned
+			total.Done += row.Done
+			total.Actual += row.Actual
+		}
+	}
+	if len(rows) > 1 {
+		b.WriteString(reportLine(total))
+	}
+	return b.String()
+}
+
+func reportLine(row manager.ReportRow) string {
+	planned := "unlimited"
+	if row.Bounded {
+		planned = timer.HoursMinutes(row.Planned)
+	}
+	line := fmt.Sprintf("%s: planned %s, served %s, took %s", row.Name, planned, timer.HoursMinutes(row.Done), timer.HoursMinutes(row.Actual))
+	if over := row.Overrun(); row.Bounded && over >= time.Minute {
+		line += fmt.Sprintf(" - %s over (pauses and extra time)", timer.HoursMinutes(over))
+	}
+	return line + "\n"
+}
@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"multi-timer/manager"
+)
+
+func TestSessionReport(t *testing.T) {
+	at := func(minutes int) *time.Time {
+		t := time.Date(2024, 3, 4, 9, minutes, 0, 0, time.UTC)
+		return &t
+	}
+	block := func(timer string, start, end int) manager.HistoryEntry {
+		return manager.HistoryEntry{Time: *at(end), Start: at(start), Timer: timer, Event: "work"}
+	}
+	plans := []manager.SessionPlan{
+		// 20m served of an hour, 5m into the current segment
+		{Name: "Tea", Planned: time.Hour, Bounded: true, Remaining: 40 * time.Minute, Current: 5 * time.Minute},
+		{Name: "Read", Planned: 30 * time.Minute, Bounded: true},
+		{Name: "Stretch", Bounded: false, Current: 3 * time.Minute},
+	}
+	history := []manager.HistoryEntry{
+		block("Tea", 0, 10),
+		block("Tea", 12, 25), // paused 3m of this block
+		block("Read", 0, 30),
+		block("Walk", 0, 10), // not in the plans
+		{Time: *at(30), Timer: "Read", Event: "complete"},
+	}
+
+	rows := manager.SessionReport(plans, history)
+	want := []manager.ReportRow{
+		{Name: "Tea", Planned: time.Hour, Bounded: true, Done: 20 * time.Minute, Actual: 28 * time.Minute},
+		{Name: "Read", Planned: 30 * time.Minute, Bounded: true, Done: 30 * time.Minute, Actual: 30 * time.Minute},
+		{Name: "Stretch", Actual: 3 * time.Minute},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("rows = %+v, want %+v", rows, want)
+	}
+	for i := range want {
+		if rows[i] != want[i] {
+			t.Errorf("row %d = %+v, want %+v", i, rows[i], want[i])
+		}
+	}
+	if got := rows[0].Overrun(); got != 8*time.Minute {
+		t.Errorf("Tea overrun = %v, want 8m", got)
+	}
+	if got := rows[1].Overrun(); got != 0 {
+		t.Errorf("Read overrun = %v, want none", got)
+	}
+
+	report := renderReport(rows)
+	for _, line := range []string{
+		"Tea: planned 1h00m, served 20m, took 28m - 8m over (pauses and extra time)\n",
+		"Read: planned 30m, served 30m, took 30m\n",
+		"Stretch: planned unlimited, served 0m, took 3m\n",
+		"Overall: planned 1h30m, served 50m, took 58m - 8m over (pauses and extra time)\n",
+	} {
+		if !strings.Contains(report, line) {
+			t.Errorf("report has no line %q:\n%s", line, report)
+		}
+	}
+}
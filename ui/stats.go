@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"multi-timer/manager"
+	"multi-timer/timer"
+)
+
+// renderStats lists today's and this week's totals per timer and then per
+// tag, weeks start on Monday
+func renderStats(entries []manager.HistoryEntry, now time.Time) string {
+	year, month, day := now.Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+	week := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+
+	var b strings.Builder
+	for _, period := range []struct {
+		title string
+		since time.Time
+	}{{"Today", today}, {"This week", week}} {
+		fmt.Fprintf(&b, "%s:\n", period.title)
+		names, _ := manager.HistoryStats(entries, period.since, manager.ByTimer)
+		if len(names) == 0 {
+			b.WriteString("  Nothing recorded.\n")
+		}
+		for _, keys := range []func(manager.HistoryEntry) []string{manager.ByTimer, manager.ByTag} {
+			names, totals := manager.HistoryStats(entries, period.since, keys)
+			for _, name := range names {
+				total := totals[name]
+				fmt.Fprintf(&b, "  %s: %d cycles, %s work, %s break\n",
+					name, total.Cycles, timer.HoursMinutes(total.Work), timer.HoursMinutes(total.Rest))
+			}
+		}
+	}
+	return b.String()
+}
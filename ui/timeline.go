@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"multi-timer/manager"
+)
+
+const timelineWidth = 60
+
+var timelineMarks = map[string]rune{
+	"work":     '█',
+	"break":    '░',
+	"warmup":   '▒',
+	"cooldown": '▒',
+	"lap":      '▓',
+}
+
+type timelineBlock struct {
+	event      string
+	start, end time.Time
+}
+
+// renderTimeline draws the work and break blocks of the day as one row per
+// timer, scaled so the day's activity fills width columns. Blocks of the same
+// timer that overlap go on extra rows.
+func renderTimeline(entries []manager.HistoryEntry, day time.Time, width int) string {
+	year, month, date := day.Date()
+	byTimer := make(map[string][]timelineBlock)
+	var names []string
+	var first, last time.Time
+	for _, entry := range entries {
+		if entry.Start == nil {
+			continue
+		}
+		if y, m, d := entry.Start.In(day.Location()).Date(); y != year || m != month || d != date {
+			continue
+		}
+		if _, ok := byTimer[entry.Timer]; !ok {
+			names = append(names, entry.Timer)
+		}
+		byTimer[entry.Timer] = append(byTimer[entry.Timer], timelineBlock{entry.Event, *entry.Start, entry.Time})
+		if first.IsZero() || entry.Start.Before(first) {
+			first = *entry.Start
+		}
+		if entry.Time.After(last) {
+			last = entry.Time
+		}
+	}
+	if len(names) == 0 {
+		return "Nothing recorded today.\n"
+	}
+
+	first = first.Truncate(time.Hour)
+	last = last.Truncate(time.Hour).Add(time.Hour)
+	span := last.Sub(first)
+	column := func(t time.Time) int {
+		return int(int64(t.Sub(first)) * int64(width) / int64(span))
+	}
+
+	labelWidth := 0
+	for _, name := range names {
+		labelWidth = max(labelWidth, len([]rune(name)))
+	}
+
+	var b strings.Builder
+	for _, name := range names {
+		blocks := byTimer[name]
+		sort.Slice(blocks, func(i, j int) bool { return blocks[i].start.Before(blocks[j].start) })
+
+		var rows [][]rune
+		var rowEnds []time.Time
+		for _, block := range blocks {
+			row := -1
+			for i, end := range rowEnds {
+				if !block.start.Before(end) {
+					row = i
+					break
+				}
+			}
+			if row < 0 {
+				rows = append(rows, []rune(strings.Repeat(" ", width)))
+				rowEnds = append(rowEnds, time.Time{})
+				row = len(rows) - 1
+			}
+			rowEnds[row] = block.end
+			mark, ok := timelineMarks[block.event]
+			if !ok {
+				mark = '?'
+			}
+			from, to := column(block.start), column(block.end)
+			if to == from {
+				to++ // keep short blocks visible
+			}
+			for c := from; c < to && c < width; c++ {
+				rows[row][c] = mark
+			}
+		}
+		for i, row := range rows {
+			label := name
+			if i > 0 {
+				label = ""
+			}
+			fmt.Fprintf(&b, "%-*s |%s|\n", labelWidth, label, string(row))
+		}
+	}
+	startLabel, endLabel := first.Format("15:04"), last.Format("15:04")
+	fmt.Fprintf(&b, "%-*s  %s%*s\n", labelWidth, "", startLabel, width-len(startLabel), endLabel)
+	fmt.Fprintf(&b, "%-*s  █ work  ░ break  ▒ warmup/cooldown\n", labelWidth, "")
+	return b.String()
+}
@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"multi-timer/manager"
+)
+
+func TestTimelineLayout(t *testing.T) {
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	block := func(timer, event string, from, to string) manager.HistoryEntry {
+		at := func(clock string) time.Time {
+			t, err := time.Parse("15:04", clock)
+			if err != nil {
+				panic(err)
+			}
+			return day.Add(time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute)
+		}
+		start := at(from)
+		return manager.HistoryEntry{Time: at(to), Start: &start, Timer: timer, Event: event}
+	}
+	yesterday := block("Tea", "work", "08:00", "08:25")
+	*yesterday.Start = yesterday.Start.AddDate(0, 0, -1)
+	yesterday.Time = yesterday.Time.AddDate(0, 0, -1)
+	entries := []manager.HistoryEntry{
+		yesterday,
+		block("Tea", "work", "09:00", "09:25"),
+		block("Read", "work", "09:10", "10:00"),
+		{Time: day.Add(9*time.Hour + 25*time.Minute), Timer: "Tea", Event: "outcome"},
+		block("Tea", "break", "09:25", "09:30"),
+		block("Tea", "work", "09:30", "09:55"),
+		// A second Read timer overlapping the first
+		block("Read", "work", "09:40", "10:30"),
+	}
+
+	// 09:00 to 11:00 in 24 columns of 5 minutes
+	got := renderTimeline(entries, day, 24)
+	want := strings.Join([]string{
+		"Tea  |█████░█████             |",
+		"Read |  ██████████            |",
+		"     |        ██████████      |",
+		"      09:00              11:00",
+		"      █ work  ░ break  ▒ warmup/cooldown",
+		"",
+	}, "\n")
+	if got != want {
+		t.Errorf("timeline =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := renderTimeline(entries[:1], day, 24); got != "Nothing recorded today.\n" {
+		t.Errorf("timeline of a day without blocks =\n%s", got)
+	}
+}
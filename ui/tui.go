@@ -0,0 +1,261 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"multi-timer/config"
+	"multi-timer/timer"
+)
+
+// The full screen interface, started with -tui. It draws the same lines as
+// displayTimers but redraws the whole screen at once, so nothing flickers and
+// typing is never mixed up with the countdown. The arrow keys pick a timer and
+// single keys act on it, e edits its time left in place, n its name and f its
+// phases.
+
+const tuiHelp = "↑/↓ select  p pause  r reset  +/- adjust  e edit time  n rename  f edit phases  q quit"
+
+type refreshMsg struct{}
+
+// editField is what is being typed in place, editNone when nothing is
+type editField int
+
+const (
+	editNone editField = iota
+	editTime
+	editName
+	editPhases
+)
+
+var editPrompts = map[editField]string{
+	editTime:   "Time left",
+	editName:   "Name",
+	editPhases: "Phases (work/break, ...)",
+}
+
+type tuiModel struct {
+	tm      *Terminal
+	editing editField
+	input   []rune // what is typed while editing
+	status  string // the outcome of the last key
+}
+
+func (m tuiModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil // refreshMsg and resizes only redraw
+	}
+	tm := m.tm
+	tm.Lock()
+	tm.NoteInput()
+	tm.Unlock()
+
+	if key.String() == "ctrl+c" || (key.String() == "q" && m.editing == editNone) {
+		tm.Quit()
+		return m, tea.Quit
+	}
+	if m.editing != editNone {
+		return m.edit(key), nil
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+	m.status = ""
+	timer := tm.selectedTimer()
+	switch key.String() {
+	case "up", "k":
+		tm.selected = max(tm.selected-1, 1)
+	case "down", "j":
+		tm.selected = min(tm.selected+1, len(tm.ActiveTimers))
+	case "p", " ":
+		if timer != nil && !tm.TogglePause(timer) {
+			m.status = "Deep work cycle, it can't be paused."
+		}
+	case "r":
+		if timer != nil {
+			tm.ResetTimer(timer)
+		}
+	case "+", "-":
+		if timer == nil {
+			break
+		}
+		if timer.Protected() {
+			m.status = "Deep work cycle, its time can't be changed."
+			break
+		}
+		d, _ := keyAdjustment(rune(key.String()[0]), tm.adjustStep)
+		timer.AddTime(d)
+	case "e", "enter":
+		if timer != nil {
+			m.editing, m.input = editTime, nil
+		}
+	case "n":
+		if timer != nil {
+			// Start from the current name, most renames are small changes
+			m.editing, m.input = editName, []rune(timer.State.Name)
+		}
+	case "f":
+		switch {
+		case timer == nil:
+		case timer.IsStopwatch() || timer.Kind == config.TypeAlarm:
+			m.status = "Only timers with phases have phases to edit."
+		default:
+			m.editing, m.input = editPhases, []rune(phaseList(tm.editableConfig(timer)))
+		}
+	}
+	return m, nil
+}
+
+// edit handles a key while a field of the selected timer is typed
+func (m tuiModel) edit(key tea.KeyMsg) tuiModel {
+	switch key.Type {
+	case tea.KeyEsc:
+		m.editing = editNone
+	case tea.KeyBackspace:
+		if len(m.input) > 0 {
+			m.input = m.input[:len(m.input)-1]
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.input = append(m.input, key.Runes...)
+	case tea.KeyEnter:
+		field := m.editing
+		m.editing = editNone
+		switch field {
+		case editTime:
+			m.status = m.setTimeLeft(string(m.input))
+		case editName, editPhases:
+			m.status = m.editConfig(field, string(m.input))
+		}
+	}
+	return m
+}
+
+// setTimeLeft sets the time left of the selected timer and returns what went
+// wrong, if anything
+func (m tuiModel) setTimeLeft(input string) string {
+	d, err := config.ParseDuration(input)
+	if err != nil || d < 0 {
+		return config.DurationProblem(err)
+	}
+	tm := m.tm
+	tm.Lock()
+	defer tm.Unlock()
+	timer := tm.selectedTimer()
+	switch {
+	case timer == nil:
+	case timer.Protected():
+		return "Deep work cycle, its time can't be changed."
+	case !timer.SetRemaining(d, false):
+		return "A break that waits for resume has no time to set."
+	}
+	return ""
+}
+
+// editConfig renames the selected timer or sets its phases, saving the change
+// like e <number> at the prompt does
+func (m tuiModel) editConfig(field editField, input string) string {
+	tm := m.tm
+	tm.Lock()
+	timer := tm.selectedTimer()
+	if timer == nil {
+		tm.Unlock()
+		return ""
+	}
+	config := tm.editableConfig(timer)
+	tm.Unlock()
+
+	if field == editName {
+		if input = strings.TrimSpace(input); input == "" {
+			return "A timer needs a name."
+		}
+		config.Name = input
+	} else {
+		var err error
+		if config, err = parsePhaseList(config, input); err != nil {
+			return "Invalid phases: " + err.Error()
+		}
+	}
+	if err := tm.applyEdit(timer, config); err != nil {
+		return "Error editing timer: " + err.Error()
+	}
+	return ""
+}
+
+func (m tuiModel) View() string {
+	tm := m.tm
+	var b strings.Builder
+	tm.Lock()
+	b.WriteString(tm.header() + "\n")
+	if tm.Prefs.Sparkline {
+		tm.SampleRemaining(tm.Now())
+	}
+	for i, timer := range tm.ActiveTimers {
+		if !tm.Shown(timer) {
+			continue
+		}
+		line := tm.timerLine(i, timer)
+		if i+1 != tm.selected {
+			line = "  " + line // line up with the marker of the selected one
+		}
+		b.WriteString(line + "\n")
+	}
+	if len(tm.ActiveTimers) == 0 {
+		b.WriteString("No timers running.\n")
+	}
+	if sent := tm.Notifications(); len(sent) > 0 {
+		last := sent[len(sent)-1]
+		fmt.Fprintf(&b, "\nLast notification: %s at %s - %s\n", last.Timer, last.At.Format("15:04:05"), last.Message)
+	}
+	if err := tm.LastError(); err != "" {
+		b.WriteString(err + "\n")
+	}
+	tm.Unlock()
+
+	b.WriteString("\n")
+	switch {
+	case m.editing != editNone:
+		fmt.Fprintf(&b, "%s: %s█  (enter to set, esc to cancel)\n", editPrompts[m.editing], string(m.input))
+	case m.status != "":
+		b.WriteString(m.status + "\n")
+	default:
+		b.WriteString("\n")
+	}
+	b.WriteString(tuiHelp + "\n")
+	return b.String()
+}
+
+// selectedTimer is the timer the keys act on, the first one until another is
+// picked. Must be called with tm locked.
+func (tm *Terminal) selectedTimer() *timer.Timer {
+	if len(tm.ActiveTimers) == 0 {
+		return nil
+	}
+	tm.selected = min(max(tm.selected, 1), len(tm.ActiveTimers))
+	return tm.ActiveTimers[tm.selected-1]
+}
+
+// RunTUI shows the timers full screen until q is pressed
+func (tm *Terminal) RunTUI() error {
+	tm.Lock()
+	if len(tm.ActiveTimers) > 0 {
+		tm.selected = max(tm.selected, 1)
+	}
+	tm.Unlock()
+
+	p := tea.NewProgram(tuiModel{tm: tm}, tea.WithAltScreen())
+	tm.Exit = func(int) { p.Quit() }
+	go func() {
+		for range tm.DisplayChan {
+			p.Send(refreshMsg{})
+		}
+	}()
+	_, err := p.Run()
+	return err
+}
@@ -0,0 +1,2119 @@
+// Package ui is the terminal interface: the timer display with its command
+// prompt and the full screen view.
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"multi-timer/config"
+	"multi-timer/manager"
+	"multi-timer/timer"
+)
+
+const (
+	clearScreen   = "\033[2J"
+	moveToTop     = "\033[H"
+	clearLine     = "\033[K"
+	saveCursor    = "\033[s"
+	restoreCursor = "\033[u"
+	clearToBottom = "\033[J"
+)
+
+// Terminal is the interface on the terminal: the display of the timers with
+// the command prompt below, or the full screen view of -tui. It carries
+// what only the interface needs next to the TimerManager it shows.
+type Terminal struct {
+	*manager.TimerManager
+	selected   int           // 1-based index of the timer +/- keys act on, 0 for none
+	input      <-chan string // lines typed at the prompt, see input.go
+	adjustStep time.Duration // how much the +/- keys add or take off
+}
+
+// NewTerminal is the interface on the terminal for tm
+func NewTerminal(tm *manager.TimerManager) *Terminal {
+	return &Terminal{TimerManager: tm, adjustStep: time.Minute}
+}
+
+func clearDisplay() {
+	fmt.Print(clearScreen, moveToTop)
+}
+
+// header is the title line of the display. Must be called with tm locked.
+func (tm *Terminal) header() string {
+	header := "=== Active Timers ==="
+	if tm.Profile != "" {
+		header += fmt.Sprintf(" [%s]", tm.Profile)
+	}
+	if tm.Speed != 1 {
+		header += fmt.Sprintf(" (speed %gx)", tm.Speed)
+	}
+	if tm.Filter != "" {
+		header += fmt.Sprintf(" (#%s, %d hidden)", tm.Filter, tm.Hidden())
+	}
+	if left, ok := tm.AggregateRemaining(); ok {
+		if tm.Prefs.IncludeBreaks {
+			header += fmt.Sprintf(" %s left with breaks", timer.HoursMinutes(left))
+		} else {
+			header += fmt.Sprintf(" %s of work left", timer.HoursMinutes(left))
+		}
+	}
+	return header
+}
+
+// timerLine is the display line of the i-th timer. Must be called with tm
+// locked.
+func (tm *Terminal) timerLine(i int, t *timer.Timer) string {
+	status := ""
+	if t.IsPaused && t.PausedByIdle {
+		status = " (AWAY)"
+	} else if t.IsPaused {
+		status = " (PAUSED)"
+	} else if t.Protected() {
+		status = " (DEEP WORK)"
+	}
+	th := tm.theme()
+	marker := ""
+	if i+1 == tm.selected {
+		marker = colorize(">", th.selected) + " "
+	}
+	if len(t.Tags) > 0 {
+		status += " #" + strings.Join(t.Tags, " #")
+	}
+	text := colorize(t.Render(tm.Prefs), displayColor(t, th))
+	if th.blink && colorEnabled && !t.IsPaused && t.EndingSoon() {
+		text = blinkCode + text + resetColor
+	}
+	line := fmt.Sprintf("%s%d. %s", marker, i+1, text)
+	spark := ""
+	if s := timer.Sparkline(t.Samples.Series()); tm.Prefs.Sparkline && s != "" {
+		spark = " " + s
+	}
+
+	// The bar takes the room the rest of the line leaves
+	room := terminalWidth() - ansi.StringWidth(line+spark+status) - len(" [] 100%")
+	if bar := t.ProgressIndicator(tm.Prefs, room); bar != "" {
+		spark += " " + bar
+	}
+	return line + spark + status
+}
+
+func (tm *Terminal) displayTimers(preserveCommandLine bool) {
+	w := tm.Out
+	if preserveCommandLine {
+		fmt.Fprint(w, saveCursor, moveToTop)
+	} else {
+		fmt.Fprint(w, clearScreen, moveToTop)
+	}
+
+	tm.Lock()
+	fmt.Fprint(w, clearLine, tm.header(), "\n")
+
+	if tm.Prefs.Sparkline {
+		tm.SampleRemaining(tm.Now())
+	}
+	for i, timer := range tm.ActiveTimers {
+		if !tm.Shown(timer) {
+			continue
+		}
+		fmt.Fprint(w, clearLine, tm.timerLine(i, timer), "\n")
+		for _, line := range timer.LapLines() {
+			fmt.Fprint(w, clearLine, line, "\n")
+		}
+	}
+	for _, cfg := range tm.Configs {
+		if cfg.StartAt != "" {
+			fmt.Fprint(w, clearLine, "Scheduled: ", config.ScheduleString(cfg), "\n")
+		}
+	}
+	if tm.Prefs.PinnedPane {
+		fmt.Fprint(w, clearLine, "\n--- Notifications (< older, > newer) ---\n")
+		for _, line := range renderPane(tm.Notifications(), tm.PaneOffset(), paneRows) {
+			fmt.Fprint(w, clearLine, line, "\n")
+		}
+	} else if sent := tm.Notifications(); len(sent) > 0 {
+		last := sent[len(sent)-1]
+		fmt.Fprintf(w, "%sLast notification: %s at %s - %s\n",
+			clearLine, last.Timer, last.At.Format("15:04:05"), last.Message)
+	}
+	if err := tm.LastError(); err != "" {
+		fmt.Fprint(w, clearLine, err, "\n")
+	}
+	if review, ok := tm.NextReview(); ok {
+		fmt.Fprintf(w, "%s\n%s phase %d: did you %q? (outcome y/n)\n",
+			clearLine, review.Timer, review.Phase+1, review.Outcome)
+	}
+	themeList := themeNames(tm.Prefs)
+	tm.Unlock()
+
+	fmt.Fprint(w, clearLine, "\nCommands:\n")
+	fmt.Fprint(w, clearLine, "a - Add new timer\n")
+	fmt.Fprint(w, clearLine, "e <number> - Edit a timer's name, notification text, phase lengths and cycles\n")
+	fmt.Fprint(w, clearLine, "p <number> - Pause/Resume timer\n")
+	fmt.Fprint(w, clearLine, "pa - Pause every running timer, pa again resumes them\n")
+	fmt.Fprint(w, clearLine, "focus <on|off> - Have starting or resuming a timer pause all the others\n")
+	fmt.Fprint(w, clearLine, "auto-pause <number> <on|off> - Pause the timer's work while the screen is locked or you are away\n")
+	fmt.Fprint(w, clearLine, "r <number> - Reset timer\n")
+	fmt.Fprint(w, clearLine, "n <number> - End the current work or break now and go on to the next\n")
+	fmt.Fprint(w, clearLine, "N <number> - Skip the rest of the current cycle, its break included\n")
+	fmt.Fprint(w, clearLine, "reset-mode <full|segment> - Have r start timers over from cycle 1 or only restart the current segment\n")
+	fmt.Fprint(w, clearLine, "d <number> - Delete timer\n")
+	fmt.Fprint(w, clearLine, "sel <number> - Select a timer, then + or - adjusts it by the key step\n")
+	fmt.Fprint(w, clearLine, "keystep <duration> - Set how much + and - adjust (default 1 minute)\n")
+	fmt.Fprint(w, clearLine, "sw <name> - Start a stopwatch\n")
+	fmt.Fprint(w, clearLine, "l <number> - Record a lap of a stopwatch, logged when it's paused, reset or deleted\n")
+	fmt.Fprint(w, clearLine, "cd <duration> [name] - Start a countdown that notifies once, without breaks\n")
+	fmt.Fprint(w, clearLine, "alarm <HH:MM> [name] - Notify when the clock reaches the time\n")
+	fmt.Fprint(w, clearLine, "keep <number> [name] - Save a stopwatch's time as a countdown timer\n")
+	fmt.Fprint(w, clearLine, "presets - List the presets timers can be started from\n")
+	fmt.Fprint(w, clearLine, "save-preset <number> <name> - Save a timer's setup as a preset\n")
+	fmt.Fprint(w, clearLine, "start <preset> [name] - Start a timer from a preset\n")
+	fmt.Fprint(w, clearLine, "msg <number> <text> - Change a timer's notification text\n")
+	fmt.Fprint(w, clearLine, "remind <number> <every> <text> - Notify every so often while a timer runs (remind <number> off to stop)\n")
+	fmt.Fprint(w, clearLine, "set <number> <duration> [over] - Set the time left in the current segment, over allows more than its length\n")
+	fmt.Fprint(w, clearLine, "rebase <number> <duration> - Change the base duration and recompute the phases relative to it\n")
+	fmt.Fprint(w, clearLine, "fork <number> - Start a copy of a timer that continues its remaining phases\n")
+	fmt.Fprint(w, clearLine, "flip <number> - Switch a timer between counting down and counting up\n")
+	fmt.Fprint(w, clearLine, "resume <number> - End a break that waits for resume\n")
+	fmt.Fprint(w, clearLine, "ack [number] - Acknowledge notifications so they don't escalate (all timers without a number)\n")
+	fmt.Fprint(w, clearLine, "outcome <y|n> - Record whether a completed phase achieved its outcome\n")
+	fmt.Fprint(w, clearLine, "override <work text> | <break text> - Use the same notification text for every timer (override off to clear)\n")
+	fmt.Fprint(w, clearLine, "quiet [HH:MM-HH:MM [days] | off] - Mute notifications in a daily window, list windows or clear them\n")
+	fmt.Fprint(w, clearLine, "braille <on|off> - Draw the progress bars with braille for terminals that have it\n")
+	fmt.Fprint(w, clearLine, "ascii-bars <on|off> - Draw the progress bars with # and - for terminals without block characters\n")
+	fmt.Fprint(w, clearLine, "webhook [add|remove <url>] - List the webhooks every timer calls, or add or remove one\n")
+	fmt.Fprint(w, clearLine, "webhook <number> [add|remove <url>] - The same for the webhooks only that timer calls\n")
+	fmt.Fprint(w, clearLine, "pin <on|off> - Keep the recent notifications in a pane below the timers, < and > scroll it\n")
+	fmt.Fprintf(w, "%stheme <%s|off> - Color the display with a theme\n", clearLine, strings.ReplaceAll(themeList, ", ", "|"))
+	fmt.Fprint(w, clearLine, "doctor - Check the configs for unknown sounds, colors and timers\n")
+	fmt.Fprint(w, clearLine, "database <file|off> - Also write the history to an SQLite database\n")
+	fmt.Fprint(w, clearLine, "mute <on|off> - Silence every timer's sounds\n")
+	fmt.Fprint(w, clearLine, "spark <on|off> - Show a sparkline of each timer's time left over the last seconds\n")
+	fmt.Fprint(w, clearLine, "words <on|off> - Spell out times in the display and notifications for screen readers\n")
+	fmt.Fprint(w, clearLine, "dots <on|off> - Show cycles as dots like ●●○○ instead of numbers\n")
+	fmt.Fprint(w, clearLine, "total-breaks <on|off> - Include breaks in the time left shown in the header\n")
+	fmt.Fprint(w, clearLine, "unique-titles <on|off> - Add cycle and phase to notification titles so they don't stack\n")
+	fmt.Fprint(w, clearLine, "round <seconds> - Round the displayed time to 5 or 10 seconds (0 for exact)\n")
+	fmt.Fprint(w, clearLine, "refresh <seconds> - Redraw the timers less often to save power (1 for every second)\n")
+	fmt.Fprint(w, clearLine, "idle-quit <duration|off> - Save and quit after this long without input or running timers\n")
+	fmt.Fprint(w, clearLine, "gantt <number> - Chart a timer's phases and cycles\n")
+	fmt.Fprint(w, clearLine, "energy [HOUR=LEVEL,... | reset] - List upcoming transitions with the expected focus, or change the curve\n")
+	fmt.Fprint(w, clearLine, "report - Compare the planned time of this session's timers with the time they took\n")
+	fmt.Fprint(w, clearLine, "timeline - Show today's work and break blocks\n")
+	fmt.Fprint(w, clearLine, "stats - Show the cycles, work and break time of each timer and tag today and this week\n")
+	fmt.Fprint(w, clearLine, "combine <number>... - Show the total work time and cycles of several timers\n")
+	fmt.Fprint(w, clearLine, "ending <duration> - List timers that complete within the duration\n")
+	fmt.Fprint(w, clearLine, "calendar <file.ics> [window] - Count down to the calendar events starting within the window (default 24h)\n")
+	fmt.Fprint(w, clearLine, "export [--format csv|json] [--from date] [--to date] [--output file] - Write the sessions of the history for spreadsheets or scripts\n")
+	fmt.Fprint(w, clearLine, "export-ics <file.ics> [within] - Save the work sessions as calendar events, or only those within the window like 168h\n")
+	fmt.Fprint(w, clearLine, "bundle export <file> - Save the timers, preferences and templates to one file\n")
+	fmt.Fprint(w, clearLine, "bundle import <file> [prefs] - Add the timers and templates of a bundle, prefs also takes its preferences\n")
+	fmt.Fprint(w, clearLine, "plan <budget> into <n> tasks [weights] - Create n timers splitting the budget, weights like 2,1,1\n")
+	fmt.Fprint(w, clearLine, "import-external <file> - Import timers exported by another Pomodoro app\n")
+	fmt.Fprint(w, clearLine, "sync - Repair timers that drifted from the saved configs\n")
+	fmt.Fprint(w, clearLine, "debug <number> - Show the raw timer state\n")
+	fmt.Fprint(w, clearLine, "filter [tag] - Show only the timers with the tag, without one show them all again\n")
+	fmt.Fprint(w, clearLine, "pause-tag <tag> - Pause every running timer with the tag\n")
+	fmt.Fprint(w, clearLine, "resume-tag <tag> - Resume every paused timer with the tag\n")
+	fmt.Fprint(w, clearLine, "break-all - Put every working timer on break, except in deep work cycles\n")
+	fmt.Fprint(w, clearLine, "s <number> [minutes] - Hold back the segment that just started and announce it again after the snooze (default 5)\n")
+	fmt.Fprint(w, clearLine, "+ <number> <duration> - Add time to the running segment of a timer, - takes it off\n")
+	fmt.Fprint(w, clearLine, "snooze-all <duration> - Push back the end of every running timer's current segment, except in deep work cycles\n")
+	fmt.Fprint(w, clearLine, "profile <name> - Switch to another set of timers (default for the main one)\n")
+	fmt.Fprint(w, clearLine, "profile clone <src> <dst> - Copy a profile's timers to a new profile\n")
+	fmt.Fprint(w, clearLine, "speed <factor> - Run timers faster for demos (speed 1 to reset)\n")
+	if tm.Stepping {
+		fmt.Fprint(w, clearLine, "step [count] - Advance every timer by one tick, or count ticks\n")
+	}
+	fmt.Fprint(w, clearLine, "q - Quit\n")
+
+	if preserveCommandLine {
+		fmt.Fprint(w, restoreCursor)
+	}
+}
+
+func (tm *Terminal) createTimer() (*timer.Timer, *config.TimerConfig) {
+	name := tm.readLine("Enter timer name: ")
+	notifText := tm.readLine("Enter notification text: ")
+
+	var base time.Duration
+	for {
+		input := tm.readLine("Base duration phases can be multiples of, e.g. x2 (MM:SS, HH:MM:SS or minutes, empty for none): ")
+		if input == "" {
+			break
+		}
+		d, err := config.ParseDuration(input)
+		if err != nil || d <= 0 {
+			fmt.Println(config.DurationProblem(err), "Try again.")
+			continue
+		}
+		base = d
+		break
+	}
+
+	var phases []config.TimerPhase
+	for {
+		fmt.Println("\nPhase", len(phases)+1)
+		fmt.Println("Enter work time (MM:SS, HH:MM:SS or just minutes): ")
+		workStr := tm.readLine("")
+		workDur, workScale, err := config.PhaseDuration(workStr, base)
+		if err != nil {
+			fmt.Println(config.DurationProblem(err), "Try again.")
+			continue
+		}
+
+		fmt.Println("Enter break time (MM:SS, HH:MM:SS or just minutes, o to wait for resume): ")
+		breakStr := tm.readLine("")
+		breakDur := config.UntilResumed
+		var breakScale float64
+		if strings.ToLower(breakStr) != "o" {
+			breakDur, breakScale, err = config.PhaseDuration(breakStr, base)
+			if err != nil {
+				fmt.Println(config.DurationProblem(err), "Try again.")
+				continue
+			}
+		}
+
+		outcome := tm.readLine("Expected outcome of this phase (optional): ")
+
+		color := tm.readLine(fmt.Sprintf("Color of this phase (%s, optional): ", colorNames()))
+		sound := tm.readLine(fmt.Sprintf("Sound when its work or break starts (%s, optional): ", manager.SoundNames()))
+		if !validColor(color) || !manager.ValidSound(sound) {
+			fmt.Println("Unknown color or sound. Try again.")
+			continue
+		}
+
+		hours := tm.readLine("Only run this phase between (HH:MM-HH:MM, empty for any time): ")
+		if _, err := config.ActiveWindow(hours); err != nil {
+			fmt.Println(err, "Try again.")
+			continue
+		}
+
+		phases = append(phases, config.TimerPhase{
+			WorkDuration:  workDur,
+			BreakDuration: breakDur,
+			Outcome:       outcome,
+			PhaseColor:    color,
+			PhaseSound:    sound,
+			WorkScale:     workScale,
+			BreakScale:    breakScale,
+			ActiveHours:   hours,
+		})
+
+		fmt.Print("Add another phase? (y/n): ")
+		if strings.ToLower(tm.readLine("")) != "y" {
+			break
+		}
+	}
+
+	var warmup, cooldown time.Duration
+	for {
+		input := tm.readLine("Warmup before the first cycle (MM:SS, HH:MM:SS or minutes, empty for none): ")
+		if input == "" {
+			break
+		}
+		d, err := config.ParseDuration(input)
+		if err != nil {
+			fmt.Println(config.DurationProblem(err), "Try again.")
+			continue
+		}
+		warmup = d
+		break
+	}
+	for {
+		input := tm.readLine("Cooldown after the last cycle (MM:SS, HH:MM:SS or minutes, empty for none): ")
+		if input == "" {
+			break
+		}
+		d, err := config.ParseDuration(input)
+		if err != nil {
+			fmt.Println(config.DurationProblem(err), "Try again.")
+			continue
+		}
+		cooldown = d
+		break
+	}
+
+	group := tm.readLine("Exclusive group, only one of its timers runs at a time (optional): ")
+	linked := tm.readLine("Linked group, its timers pause and resume together (optional): ")
+	tags := config.ParseTags(tm.readLine("Tags (separated by commas, optional): "))
+	next := tm.readLine("Timer to start when this one completes (name, optional): ")
+
+	var pausesOnWork []string
+	for _, name := range strings.Split(tm.readLine("Timers to pause when this one starts work (names separated by commas, optional): "), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			pausesOnWork = append(pausesOnWork, name)
+		}
+	}
+
+	var webhooks []string
+	for _, url := range strings.Split(tm.readLine("Webhook URLs to call on its events (separated by commas, optional): "), ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			webhooks = append(webhooks, url)
+		}
+	}
+
+	var activities []string
+	for _, activity := range strings.Split(tm.readLine("Break activities to suggest in turn (separated by commas, optional): "), ",") {
+		if activity = strings.TrimSpace(activity); activity != "" {
+			activities = append(activities, activity)
+		}
+	}
+
+	var reminderText string
+	var reminderEvery time.Duration
+	for {
+		input := tm.readLine("Recurring reminder every (MM:SS, HH:MM:SS or minutes, empty for none): ")
+		if input == "" {
+			break
+		}
+		d, err := config.ParseDuration(input)
+		if err != nil || d <= 0 {
+			fmt.Println(config.DurationProblem(err), "Try again.")
+			continue
+		}
+		reminderEvery = d
+		reminderText = tm.readLine("Reminder text: ")
+		break
+	}
+
+	var startSound, completeSound, workSound, breakSound string
+	for {
+		startSound = tm.readLine(fmt.Sprintf("Sound when the timer starts (%s or an audio file, empty for none): ", manager.SoundNames()))
+		completeSound = tm.readLine(fmt.Sprintf("Sound when the timer completes (%s or an audio file, empty for none): ", manager.SoundNames()))
+		workSound = tm.readLine("Sound when work starts (empty for none): ")
+		breakSound = tm.readLine("Sound when a break starts (empty for none): ")
+		if manager.ValidSound(startSound) && manager.ValidSound(completeSound) && manager.ValidSound(workSound) && manager.ValidSound(breakSound) {
+			break
+		}
+		fmt.Println("Unknown sound. Try again.")
+	}
+
+	var escalate timer.Escalation
+	for {
+		input := tm.readLine("Escalate unacknowledged notifications after (MM:SS, HH:MM:SS or minutes, empty for never): ")
+		if input == "" {
+			break
+		}
+		d, err := config.ParseDuration(input)
+		if err != nil || d <= 0 {
+			fmt.Println(config.DurationProblem(err), "Try again.")
+			continue
+		}
+		repeats, err := strconv.Atoi(tm.readLine("Maximum critical repeats: "))
+		if err != nil || repeats < 1 {
+			fmt.Println("Invalid number of repeats. Try again.")
+			continue
+		}
+		escalate = timer.Escalation{After: d, Repeats: repeats}
+		break
+	}
+
+	var milestones []int
+	var milestoneMin time.Duration
+	for {
+		input := tm.readLine("Notify at percentages of work segments (e.g. 25,50,75, empty for none): ")
+		parsed, err := config.ParseMilestones(input)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		milestones = parsed
+		break
+	}
+	for len(milestones) > 0 {
+		input := tm.readLine("Skip segments shorter than (MM:SS, HH:MM:SS or minutes, empty for none): ")
+		if input == "" {
+			break
+		}
+		d, err := config.ParseDuration(input)
+		if err != nil {
+			fmt.Println(config.DurationProblem(err), "Try again.")
+			continue
+		}
+		milestoneMin = d
+		break
+	}
+
+	cycleType := tm.readLine("Enter cycle type (u for unlimited, number for fixed cycles): ")
+	maxCycles := -1
+	if cycleType != "u" {
+		cycles, err := strconv.Atoi(cycleType)
+		if err == nil && cycles > config.MaxCycleCount {
+			fmt.Printf("At most %d cycles, using %d.\n", config.MaxCycleCount, config.MaxCycleCount)
+			cycles = config.MaxCycleCount
+		}
+		if err == nil && cycles > 0 {
+			maxCycles = cycles
+		}
+	}
+
+	var protectedCycles []int
+	for {
+		parsed, err := config.ParseCycles(tm.readLine("Deep work cycles that can't be paused or skipped (e.g. 1-2, empty for none): "))
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		protectedCycles = parsed
+		break
+	}
+
+	var finalWork, finalBreak time.Duration
+	for maxCycles != -1 {
+		input := tm.readLine("Work time of each phase's final cycle (MM:SS, HH:MM:SS or minutes, empty for the usual): ")
+		if input == "" {
+			break
+		}
+		d, err := config.ParseDuration(input)
+		if err != nil || d <= 0 {
+			fmt.Println(config.DurationProblem(err), "Try again.")
+			continue
+		}
+		finalWork = d
+		break
+	}
+	for maxCycles != -1 {
+		input := tm.readLine("Break time of each phase's final cycle (MM:SS, HH:MM:SS or minutes, empty for the usual): ")
+		if input == "" {
+			break
+		}
+		d, err := config.ParseDuration(input)
+		if err != nil || d <= 0 {
+			fmt.Println(config.DurationProblem(err), "Try again.")
+			continue
+		}
+		finalBreak = d
+		break
+	}
+
+	// Work step, break step, shortest work and longest break of the taper
+	var tapering [4]time.Duration
+	prompts := []string{
+		"Shorten work by this much every cycle (MM:SS, HH:MM:SS or minutes, empty for never): ",
+		"Lengthen breaks by this much every cycle (empty for never): ",
+		"Shortest work (empty for none): ",
+		"Longest break (empty for none): ",
+	}
+	for i := 0; i < len(prompts) && (i < 2 || tapering[0] > 0 || tapering[1] > 0); i++ {
+		input := tm.readLine(prompts[i])
+		if input == "" {
+			continue
+		}
+		d, err := config.ParseDuration(input)
+		if err != nil || d < 0 {
+			fmt.Println(config.DurationProblem(err), "Try again.")
+			i--
+			continue
+		}
+		tapering[i] = d
+	}
+
+	var order string
+	for len(phases) > 1 {
+		order = tm.readLine("Order (phase-major runs all cycles of a phase first, cycle-major one cycle of each phase in turn, empty for phase-major): ")
+		if config.ValidOrder(order) {
+			break
+		}
+		fmt.Println("Unknown order. Try again.")
+	}
+
+	var startAt string
+	var days []string
+	for {
+		startAt = tm.readLine("Start automatically at (HH:MM, empty to start now): ")
+		if startAt == "" {
+			break
+		}
+		if _, err := time.Parse("15:04", startAt); err != nil {
+			fmt.Println("Invalid time, use HH:MM.")
+			continue
+		}
+		parsed, err := config.ParseWeekdays(tm.readLine("Days to start on (e.g. mon,tue,fri, empty for every day): "))
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		days = parsed
+		break
+	}
+
+	cfg := &config.TimerConfig{
+		Name:      name,
+		NotifText: notifText,
+		Phases:    phases,
+		MaxCycles: maxCycles,
+		StartAt:   startAt,
+		Days:      days,
+
+		WarmupDuration:   warmup,
+		CooldownDuration: cooldown,
+		ExclusiveGroup:   group,
+		LinkedGroup:      linked,
+		NextTimer:        next,
+		PausesOnWork:     pausesOnWork,
+		BaseDuration:     base,
+		StartSound:       startSound,
+		CompleteSound:    completeSound,
+		WorkSound:        workSound,
+		BreakSound:       breakSound,
+		EscalateDuration: escalate.After,
+		EscalateRepeats:  escalate.Repeats,
+
+		FinalCycleWorkDuration:  finalWork,
+		FinalCycleBreakDuration: finalBreak,
+
+		Milestones:           milestones,
+		MilestoneMinDuration: milestoneMin,
+
+		ProtectedCycles: protectedCycles,
+
+		ReminderText:     reminderText,
+		ReminderDuration: reminderEvery,
+
+		BreakActivities: activities,
+
+		Tags: tags,
+
+		TaperWorkDuration:     tapering[0],
+		TaperBreakDuration:    tapering[1],
+		TaperMinWorkDuration:  tapering[2],
+		TaperMaxBreakDuration: tapering[3],
+
+		Webhooks: webhooks,
+
+		Order: order,
+	}
+
+	return timer.TimerFromConfig(*cfg), cfg
+}
+
+// RunCommands shows the timers and carries out the commands typed at the
+// prompt until q or the end of the input
+func (tm *Terminal) RunCommands(in io.Reader) {
+	tm.input = readLines(in)
+	clearDisplay()
+	tm.displayTimers(false)
+	fmt.Print("\nEnter command: ")
+
+	for {
+		command, ok := tm.nextLine()
+		if !ok {
+			tm.Quit()
+			return
+		}
+		tm.Lock()
+		tm.NoteInput()
+		tm.Unlock()
+
+		if len(command) == 0 {
+			fmt.Print("Enter command: ")
+			continue
+		}
+
+		if isScrollKeys(command) {
+			tm.Lock()
+			tm.scrollPane(command)
+			tm.Unlock()
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+			continue
+		}
+
+		if isAdjustKeys(command) {
+			tm.Lock()
+			if tm.selected > 0 && tm.selected <= len(tm.ActiveTimers) && tm.ActiveTimers[tm.selected-1].Protected() {
+				fmt.Println("Deep work cycle, its time can't be changed.")
+			} else if tm.selected > 0 && tm.selected <= len(tm.ActiveTimers) {
+				for _, key := range command {
+					if d, ok := keyAdjustment(key, tm.adjustStep); ok {
+						tm.ActiveTimers[tm.selected-1].AddTime(d)
+					}
+				}
+			}
+			tm.Unlock()
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+			continue
+		}
+
+		fields := strings.Fields(command)
+		switch strings.ToLower(fields[0]) {
+		case "a":
+			timer, cfg := tm.createTimer()
+			tm.Lock()
+			if cfg.StartAt == "" {
+				tm.ActiveTimers = append(tm.ActiveTimers, timer)
+				tm.PauseGroup(timer)
+			}
+			tm.Configs = append(tm.Configs, *cfg)
+			conflicts := manager.ConflictingRules(tm.Configs)
+			tm.Unlock()
+			if err := tm.SaveConfigs(); err != nil {
+				fmt.Println("Error saving timer configurations:", err)
+			}
+			tm.displayTimers(false)
+			for _, rule := range conflicts {
+				fmt.Printf("\nIgnoring rule %s, it leads back to itself.", rule)
+			}
+			fmt.Print("\nEnter command: ")
+
+		case "e":
+			var num int
+			fmt.Sscanf(command, "e %d", &num)
+			if err := tm.editTimer(num); err != nil {
+				fmt.Println("Error editing timer:", err)
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+
+		case "p":
+			var num int
+			fmt.Sscanf(command, "p %d", &num)
+			if num > 0 && num <= len(tm.ActiveTimers) {
+				tm.Lock()
+				if !tm.TogglePause(tm.ActiveTimers[num-1]) {
+					tm.Unlock()
+					fmt.Println("Deep work cycle, it can't be paused.")
+					fmt.Print("\nEnter command: ")
+					continue
+				}
+				tm.Unlock()
+				tm.displayTimers(false)
+			}
+			fmt.Print("\nEnter command: ")
+
+		case "pa":
+			tm.Lock()
+			names, paused := tm.PauseAll()
+			tm.Unlock()
+			tm.displayTimers(false)
+			if len(names) == 0 {
+				fmt.Println("\nNo timers to pause or resume.")
+			} else if !paused {
+				fmt.Println("\nResumed", strings.Join(names, ", "))
+			}
+			fmt.Print("\nEnter command: ")
+
+		case "focus":
+			if len(fields) < 2 || (fields[1] != "on" && fields[1] != "off") {
+				fmt.Println("Usage: focus <on|off>")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			tm.Lock()
+			tm.Prefs.FocusMode = fields[1] == "on"
+			prefs := tm.Prefs
+			tm.Unlock()
+			if err := config.SavePreferences(prefs); err != nil {
+				fmt.Println("Error saving preferences:", err)
+			}
+			fmt.Print("\nEnter command: ")
+
+		case "auto-pause":
+			var num int
+			if len(fields) == 3 {
+				num, _ = strconv.Atoi(fields[1])
+			}
+			if num == 0 || (fields[2] != "on" && fields[2] != "off") {
+				fmt.Println("Usage: auto-pause <number> <on|off>")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			if err := tm.SetAutoPause(num, fields[2] == "on"); err != nil {
+				fmt.Println(err)
+			}
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+
+		case "r":
+			var num int
+			fmt.Sscanf(command, "r %d", &num)
+			if num > 0 && num <= len(tm.ActiveTimers) {
+				tm.Lock()
+				tm.ResetTimer(tm.ActiveTimers[num-1])
+				tm.Unlock()
+				tm.displayTimers(false)
+			}
+			fmt.Print("\nEnter command: ")
+
+		case "d":
+			var num int
+			fmt.Sscanf(command, "d %d", &num)
+			if num > 0 && num <= len(tm.ActiveTimers) {
+				tm.Lock()
+				if j := tm.PairConfigs()[num-1]; j >= 0 {
+					tm.Configs = append(tm.Configs[:j], tm.Configs[j+1:]...)
+				}
+				tm.WriteLaps(tm.ActiveTimers[num-1], true)
+				tm.LogInterrupted(tm.ActiveTimers[num-1])
+				tm.ActiveTimers = append(tm.ActiveTimers[:num-1], tm.ActiveTimers[num:]...)
+				tm.Unlock()
+				if err := tm.SaveConfigs(); err != nil {
+					fmt.Println("Error saving timer configurations:", err)
+				}
+				tm.displayTimers(false)
+			}
+			fmt.Print("\nEnter command: ")
+
+		case "sel":
+			var num int
+			fmt.Sscanf(command, "sel %d", &num)
+			tm.Lock()
+			if num >= 0 && num <= len(tm.ActiveTimers) {
+				tm.selected = num
+			}
+			tm.Unlock()
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+
+		case "keystep":
+			if len(fields) < 2 {
+				fmt.Println("Usage: keystep <duration>")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			step, err := config.ParseDuration(fields[1])
+			if err != nil || step <= 0 {
+				fmt.Println(config.DurationProblem(err))
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			tm.Lock()
+			tm.adjustStep = step
+			tm.Unlock()
+			fmt.Print("\nEnter command: ")
+
+		case "sw":
+			name := strings.TrimSpace(strings.TrimPrefix(command, fields[0]))
+			if name == "" {
+				name = "Stopwatch"
+			}
+			tm.Lock()
+			tm.ActiveTimers = append(tm.ActiveTimers, timer.NewStopwatch(name))
+			tm.Unlock()
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+
+		case "cd", "alarm":
+			if len(fields) < 2 {
+				fmt.Printf("Usage: %s\n", map[string]string{"cd": "cd <duration> [name]", "alarm": "alarm <HH:MM> [name]"}[fields[0]])
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			name := strings.Join(fields[2:], " ")
+			var cfg config.TimerConfig
+			if strings.EqualFold(fields[0], "cd") {
+				d, err := config.ParseDuration(fields[1])
+				if err != nil || d <= 0 {
+					fmt.Println(config.DurationProblem(err))
+					fmt.Print("\nEnter command: ")
+					continue
+				}
+				if name == "" {
+					name = "Countdown"
+				}
+				cfg = config.CountdownConfig(name, d)
+			} else {
+				if _, err := config.UntilAlarm(fields[1], tm.Now()); err != nil {
+					fmt.Println(err)
+					fmt.Print("\nEnter command: ")
+					continue
+				}
+				if name == "" {
+					name = "Alarm"
+				}
+				cfg = config.AlarmConfig(name, fields[1])
+			}
+			if err := tm.AddTimer(cfg); err != nil {
+				fmt.Println("Error saving timer configurations:", err)
+			}
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+
+		case "l":
+			var num int
+			fmt.Sscanf(command, "l %d", &num)
+			tm.Lock()
+			if num <= 0 || num > len(tm.ActiveTimers) || !tm.ActiveTimers[num-1].AddLap(tm.Now()) {
+				tm.Unlock()
+				fmt.Println("Not a stopwatch.")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			tm.Unlock()
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+
+		case "keep":
+			var num int
+			fmt.Sscanf(command, "keep %d", &num)
+			tm.Lock()
+			if num <= 0 || num > len(tm.ActiveTimers) || !tm.ActiveTimers[num-1].IsStopwatch() {
+				tm.Unlock()
+				fmt.Println("Not a stopwatch.")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			name := tm.ActiveTimers[num-1].State.Name
+			if len(fields) > 2 {
+				name = strings.Join(fields[2:], " ")
+			}
+			cfg := tm.ActiveTimers[num-1].CountdownConfig(name)
+			tm.Configs = append(tm.Configs, cfg)
+			tm.ActiveTimers = append(tm.ActiveTimers, timer.TimerFromConfig(cfg))
+			tm.Unlock()
+			if err := tm.SaveConfigs(); err != nil {
+				fmt.Println("Error saving timer configurations:", err)
+			}
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+
+		case "presets":
+			all, err := config.Presets()
+			if err != nil {
+				fmt.Println("Error reading presets:", err)
+			}
+			fmt.Println()
+			for _, preset := range all {
+				fmt.Println(PresetLine(preset))
+			}
+			fmt.Print("\nEnter command: ")
+
+		case "save-preset":
+			if len(fields) < 3 {
+				fmt.Println("Usage: save-preset <number> <name>")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			num, err := strconv.Atoi(fields[1])
+			tm.Lock()
+			if err != nil || num < 1 || num > len(tm.ActiveTimers) || tm.ActiveTimers[num-1].IsStopwatch() {
+				tm.Unlock()
+				fmt.Println("Usage: save-preset <number> <name> of a timer with phases")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			cfg := tm.ActiveTimers[num-1].Config()
+			tm.Unlock()
+			cfg.Name = strings.Join(fields[2:], " ")
+			if err := config.SavePreset(cfg); err != nil {
+				fmt.Println("Error saving preset:", err)
+			} else {
+				fmt.Println("Saved preset", cfg.Name)
+			}
+			fmt.Print("\nEnter command: ")
+
+		case "start":
+			if len(fields) < 2 {
+				fmt.Println("Usage: start <preset> [name]")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			cfg, err := config.FindPreset(fields[1])
+			if err != nil {
+				fmt.Println(err)
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			if len(fields) > 2 {
+				cfg.Name = strings.Join(fields[2:], " ")
+			}
+			if err := tm.AddTimer(cfg); err != nil {
+				fmt.Println("Error saving timer configurations:", err)
+			}
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+
+		case "msg":
+			if len(fields) < 3 {
+				fmt.Println("Usage: msg <number> <text>")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			num, err := strconv.Atoi(fields[1])
+			if err != nil {
+				fmt.Println("Invalid timer number.")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			text := strings.TrimPrefix(strings.TrimSpace(strings.TrimPrefix(command, fields[0])), fields[1])
+			text = strings.TrimSpace(text)
+			if err := tm.SetNotifText(num, text); err != nil {
+				fmt.Println("Error setting the notification text:", err)
+			}
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+
+		case "remind":
+			if len(fields) < 3 || (fields[2] != "off" && len(fields) < 4) {
+				fmt.Println("Usage: remind <number> <every> <text> or remind <number> off")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			num, err := strconv.Atoi(fields[1])
+			if err != nil {
+				fmt.Println("Invalid timer number.")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			var every time.Duration
+			var text string
+			if fields[2] != "off" {
+				every, err = config.ParseDuration(fields[2])
+				if err != nil || every <= 0 {
+					fmt.Println(config.DurationProblem(err))
+					fmt.Print("\nEnter command: ")
+					continue
+				}
+				text = strings.Join(fields[3:], " ")
+			}
+			tm.Lock()
+			if num > 0 && num <= len(tm.ActiveTimers) {
+				timer := tm.ActiveTimers[num-1]
+				timer.ReminderText, timer.ReminderEvery, timer.ReminderElapsed = text, every, 0
+				if j := tm.PairConfigs()[num-1]; j >= 0 {
+					tm.Configs[j].ReminderText = text
+					tm.Configs[j].ReminderDuration = every
+				}
+			}
+			tm.Unlock()
+			if err := tm.SaveConfigs(); err != nil {
+				fmt.Println("Error saving timer configurations:", err)
+			}
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+
+		case "set":
+			if len(fields) < 3 || len(fields) > 4 || (len(fields) == 4 && fields[3] != "over") {
+				fmt.Println("Usage: set <number> <duration> [over]")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			num, err := strconv.Atoi(fields[1])
+			if err != nil {
+				fmt.Println("Invalid timer number.")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			d, err := config.ParseDuration(fields[2])
+			if err != nil || d < 0 {
+				fmt.Println(config.DurationProblem(err))
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			tm.Lock()
+			if num > 0 && num <= len(tm.ActiveTimers) && tm.ActiveTimers[num-1].Protected() {
+				fmt.Println("Deep work cycle, its time can't be changed.")
+			} else if num > 0 && num <= len(tm.ActiveTimers) && !tm.ActiveTimers[num-1].SetRemaining(d, len(fields) == 4) {
+				fmt.Println("A break that waits for resume has no time to set.")
+			}
+			tm.Unlock()
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+
+		case "rebase":
+			if len(fields) != 3 {
+				fmt.Println("Usage: rebase <number> <duration>")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			num, err := strconv.Atoi(fields[1])
+			if err != nil {
+				fmt.Println("Invalid timer number.")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			base, err := config.ParseDuration(fields[2])
+			if err != nil || base <= 0 {
+				fmt.Println(config.DurationProblem(err))
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			tm.Lock()
+			if num < 1 || num > len(tm.ActiveTimers) || !tm.ActiveTimers[num-1].Rebase(base) {
+				tm.Unlock()
+				fmt.Println("Only timers with phases relative to a base can be rebased.")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			if j := tm.PairConfigs()[num-1]; j >= 0 {
+				timer := tm.ActiveTimers[num-1]
+				tm.Configs[j].BaseDuration = timer.Base
+				tm.Configs[j].Phases = timer.Phases
+			}
+			tm.Unlock()
+			if err := tm.SaveConfigs(); err != nil {
+				fmt.Println("Error saving timer configurations:", err)
+			}
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+
+		case "fork":
+			var num int
+			fmt.Sscanf(command, "fork %d", &num)
+			tm.Lock()
+			if num > 0 && num <= len(tm.ActiveTimers) {
+				tm.ActiveTimers = append(tm.ActiveTimers, tm.ActiveTimers[num-1].Fork())
+			}
+			tm.Unlock()
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+
+		case "flip":
+			var num int
+			fmt.Sscanf(command, "flip %d", &num)
+			tm.Lock()
+			if num > 0 && num <= len(tm.ActiveTimers) && !tm.ActiveTimers[num-1].ToggleDirection() {
+				fmt.Println("Only a running countdown can be flipped.")
+			}
+			tm.Unlock()
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+
+		case "n":
+			// N with a capital skips the whole cycle, n only the segment
+			var num int
+			if len(fields) == 2 {
+				num, _ = strconv.Atoi(fields[1])
+			}
+			if num == 0 {
+				fmt.Printf("Usage: %s <number>\n", fields[0])
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			tm.Lock()
+			err := tm.Skip(num, fields[0] == "N")
+			tm.Unlock()
+			if err != nil {
+				fmt.Println(err)
+			}
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+
+		case "resume":
+			var num int
+			fmt.Sscanf(command, "resume %d", &num)
+			tm.Lock()
+			if num > 0 && num <= len(tm.ActiveTimers) && tm.ActiveTimers[num-1].OnOpenBreak() {
+				tm.EndBreakEarly(num)
+			}
+			tm.Unlock()
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+
+		case "ack":
+			var num int
+			fmt.Sscanf(command, "ack %d", &num)
+			tm.Lock()
+			acknowledged := 0
+			for i, timer := range tm.ActiveTimers {
+				if (num == 0 || num == i+1) && timer.Acknowledge() {
+					acknowledged++
+				}
+			}
+			tm.Unlock()
+			if acknowledged == 0 {
+				fmt.Println("No notifications waiting for acknowledgement.")
+			}
+			fmt.Print("\nEnter command: ")
+
+		case "outcome":
+			if len(fields) < 2 || (fields[1] != "y" && fields[1] != "n") {
+				fmt.Println("Usage: outcome <y|n>")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			if err := tm.RecordOutcome(fields[1] == "y"); err != nil {
+				fmt.Println("Error recording outcome:", err)
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+
+		case "override":
+			arg := strings.TrimSpace(strings.TrimPrefix(command, fields[0]))
+			if arg == "off" {
+				tm.Lock()
+				tm.Override = nil
+				tm.Unlock()
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			work, rest, ok := strings.Cut(arg, "|")
+			work, rest = strings.TrimSpace(work), strings.TrimSpace(rest)
+			if !ok || work == "" || rest == "" {
+				fmt.Println("Usage: override <work text> | <break text>")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			tm.Lock()
+			tm.Override = &manager.TextOverride{Work: work, Rest: rest}
+			tm.Unlock()
+			fmt.Print("\nEnter command: ")
+
+		case "quiet":
+			tm.Lock()
+			switch {
+			case len(fields) == 1:
+				if len(tm.Prefs.QuietHours) == 0 {
+					fmt.Println("No quiet hours set.")
+				}
+				for _, q := range tm.Prefs.QuietHours {
+					fmt.Println("Quiet", q)
+				}
+				tm.Unlock()
+				fmt.Print("\nEnter command: ")
+				continue
+			case len(fields) == 2 && fields[1] == "off":
+				tm.Prefs.QuietHours = nil
+			default:
+				days := ""
+				if len(fields) > 2 {
+					days = strings.Join(fields[2:], "")
+				}
+				q, err := config.ParseQuietHours(fields[1], days)
+				if err != nil {
+					tm.Unlock()
+					fmt.Println(err)
+					fmt.Print("\nEnter command: ")
+					continue
+				}
+				tm.Prefs.QuietHours = append(tm.Prefs.QuietHours, q)
+			}
+			prefs := tm.Prefs
+			tm.Unlock()
+			if err := config.SavePreferences(prefs); err != nil {
+				fmt.Println("Error saving preferences:", err)
+			}
+			fmt.Print("\nEnter command: ")
+
+		case "braille":
+			if len(fields) < 2 || (fields[1] != "on" && fields[1] != "off") {
+				fmt.Println("Usage: braille <on|off>")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			tm.Lock()
+			tm.Prefs.Braille = fields[1] == "on"
+			prefs := tm.Prefs
+			tm.Unlock()
+			if err := config.SavePreferences(prefs); err != nil {
+				fmt.Println("Error saving preferences:", err)
+			}
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+
+		case "ascii-bars":
+			if len(fields) < 2 || (fields[1] != "on" && fields[1] != "off") {
+				fmt.Println("Usage: ascii-bars <on|off>")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			tm.Lock()
+			tm.Prefs.ASCIIBars = fields[1] == "on"
+			prefs := tm.Prefs
+			tm.Unlock()
+			if err := config.SavePreferences(prefs); err != nil {
+				fmt.Println("Error saving preferences:", err)
+			}
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+
+		case "reset-mode":
+			if len(fields) < 2 || (fields[1] != "full" && fields[1] != "segment") {
+				fmt.Println("Usage: reset-mode <full|segment>")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			tm.Lock()
+			tm.Prefs.ResetKeepsCycles = fields[1] == "segment"
+			prefs := tm.Prefs
+			tm.Unlock()
+			if err := config.SavePreferences(prefs); err != nil {
+				fmt.Println("Error saving preferences:", err)
+			}
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+
+		case "webhook":
+			var num int
+			if len(fields) > 1 {
+				num, _ = strconv.Atoi(fields[1])
+			}
+			if num != 0 {
+				if len(fields) != 2 && (len(fields) != 4 || (fields[2] != "add" && fields[2] != "remove")) {
+					fmt.Println("Usage: webhook <number> [add|remove <url>]")
+					fmt.Print("\nEnter command: ")
+					continue
+				}
+				hooks, err := tm.TimerWebhooks(num, fields[2:]...)
+				if err != nil {
+					fmt.Println(err)
+					fmt.Print("\nEnter command: ")
+					continue
+				}
+				fmt.Println()
+				if len(hooks) == 0 {
+					fmt.Println("No webhooks of its own.")
+				}
+				for _, url := range hooks {
+					fmt.Println(url)
+				}
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			if len(fields) != 1 && (len(fields) != 3 || (fields[1] != "add" && fields[1] != "remove")) {
+				fmt.Println("Usage: webhook [<number>] [add|remove <url>]")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			if len(fields) == 3 && fields[1] == "add" {
+				if err := manager.CheckWebhook(fields[2]); err != nil {
+					fmt.Println(err)
+					fmt.Print("\nEnter command: ")
+					continue
+				}
+			}
+			tm.Lock()
+			if len(fields) == 3 {
+				hooks := slices.DeleteFunc(slices.Clone(tm.Prefs.Webhooks), func(url string) bool { return url == fields[2] })
+				if fields[1] == "add" {
+					hooks = append(hooks, fields[2])
+				}
+				tm.Prefs.Webhooks = hooks
+			}
+			prefs := tm.Prefs
+			tm.Unlock()
+			if len(fields) == 3 {
+				if err := config.SavePreferences(prefs); err != nil {
+					fmt.Println("Error saving preferences:", err)
+				}
+			}
+			fmt.Println()
+			if len(prefs.Webhooks) == 0 {
+				fmt.Println("No webhooks.")
+			}
+			for _, url := range prefs.Webhooks {
+				fmt.Println(url)
+			}
+			fmt.Print("\nEnter command: ")
+
+		case "pin":
+			if len(fields) < 2 || (fields[1] != "on" && fields[1] != "off") {
+				fmt.Println("Usage: pin <on|off>")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			tm.Lock()
+			tm.Prefs.PinnedPane = fields[1] == "on"
+			tm.ScrollPane(-tm.PaneOffset(), paneRows) // back to the newest notifications
+			prefs := tm.Prefs
+			tm.Unlock()
+			if err := config.SavePreferences(prefs); err != nil {
+				fmt.Println("Error saving preferences:", err)
+			}
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+
+		case "theme":
+			tm.Lock()
+			_, builtin := themes[strings.ToLower(fields[len(fields)-1])]
+			custom, own := tm.Prefs.Themes[fields[len(fields)-1]]
+			names := themeNames(tm.Prefs)
+			tm.Unlock()
+			if len(fields) != 2 || (!builtin && !own && fields[1] != "off") {
+				fmt.Printf("Usage: theme <%s|off>\n", strings.ReplaceAll(names, ", ", "|"))
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			if err := themeProblem(custom); own && err != nil {
+				fmt.Printf("Theme %s: %v\n", fields[1], err)
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			tm.Lock()
+			tm.Prefs.Theme = fields[1]
+			if builtin {
+				tm.Prefs.Theme = strings.ToLower(fields[1])
+			}
+			prefs := tm.Prefs
+			tm.Unlock()
+			if err := config.SavePreferences(prefs); err != nil {
+				fmt.Println("Error saving preferences:", err)
+			}
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+
+		case "database":
+			if len(fields) != 2 {
+				fmt.Println("Usage: database <file|off>")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			path := fields[1]
+			if path == "off" {
+				path = ""
+			}
+			if err := tm.UseDatabase(path); err != nil {
+				fmt.Println("Error opening database:", err)
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			tm.Lock()
+			tm.Prefs.Database = path
+			prefs := tm.Prefs
+			tm.Unlock()
+			if err := config.SavePreferences(prefs); err != nil {
+				fmt.Println("Error saving preferences:", err)
+			}
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+
+		case "mute":
+			if len(fields) < 2 || (fields[1] != "on" && fields[1] != "off") {
+				fmt.Println("Usage: mute <on|off>")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			tm.Lock()
+			tm.Prefs.Muted = fields[1] == "on"
+			prefs := tm.Prefs
+			tm.Unlock()
+			if err := config.SavePreferences(prefs); err != nil {
+				fmt.Println("Error saving preferences:", err)
+			}
+			fmt.Print("\nEnter command: ")
+
+		case "spark":
+			if len(fields) < 2 || (fields[1] != "on" && fields[1] != "off") {
+				fmt.Println("Usage: spark <on|off>")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			tm.Lock()
+			tm.Prefs.Sparkline = fields[1] == "on"
+			prefs := tm.Prefs
+			tm.Unlock()
+			if err := config.SavePreferences(prefs); err != nil {
+				fmt.Println("Error saving preferences:", err)
+			}
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+
+		case "words":
+			if len(fields) < 2 || (fields[1] != "on" && fields[1] != "off") {
+				fmt.Println("Usage: words <on|off>")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			tm.Lock()
+			tm.Prefs.Words = fields[1] == "on"
+			prefs := tm.Prefs
+			tm.Unlock()
+			if err := config.SavePreferences(prefs); err != nil {
+				fmt.Println("Error saving preferences:", err)
+			}
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+
+		case "dots":
+			if len(fields) < 2 || (fields[1] != "on" && fields[1] != "off") {
+				fmt.Println("Usage: dots <on|off>")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			tm.Lock()
+			tm.Prefs.CycleDots = fields[1] == "on"
+			prefs := tm.Prefs
+			tm.Unlock()
+			if err := config.SavePreferences(prefs); err != nil {
+				fmt.Println("Error saving preferences:", err)
+			}
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+
+		case "total-breaks":
+			if len(fields) < 2 || (fields[1] != "on" && fields[1] != "off") {
+				fmt.Println("Usage: total-breaks <on|off>")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			tm.Lock()
+			tm.Prefs.IncludeBreaks = fields[1] == "on"
+			prefs := tm.Prefs
+			tm.Unlock()
+			if err := config.SavePreferences(prefs); err != nil {
+				fmt.Println("Error saving preferences:", err)
+			}
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+
+		case "unique-titles":
+			if len(fields) < 2 || (fields[1] != "on" && fields[1] != "off") {
+				fmt.Println("Usage: unique-titles <on|off>")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			tm.Lock()
+			tm.Prefs.UniqueTitles = fields[1] == "on"
+			prefs := tm.Prefs
+			tm.Unlock()
+			if err := config.SavePreferences(prefs); err != nil {
+				fmt.Println("Error saving preferences:", err)
+			}
+			fmt.Print("\nEnter command: ")
+
+		case "round":
+			secs := -1
+			if len(fields) > 1 {
+				if n, err := strconv.Atoi(fields[1]); err == nil {
+					secs = n
+				}
+			}
+			if secs < 0 {
+				fmt.Println("Usage: round <seconds>")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			tm.Lock()
+			tm.Prefs.DisplayRounding = time.Duration(secs) * time.Second
+			prefs := tm.Prefs
+			tm.Unlock()
+			if err := config.SavePreferences(prefs); err != nil {
+				fmt.Println("Error saving preferences:", err)
+			}
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+
+		case "idle-quit":
+			if len(fields) != 2 {
+				fmt.Println("Usage: idle-quit <duration|off>")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			var after time.Duration
+			if fields[1] != "off" {
+				d, err := config.ParseDuration(fields[1])
+				if err != nil || d <= 0 {
+					fmt.Println(config.DurationProblem(err))
+					fmt.Print("\nEnter command: ")
+					continue
+				}
+				after = d
+			}
+			tm.Lock()
+			tm.Prefs.IdleQuit = after
+			prefs := tm.Prefs
+			tm.Unlock()
+			if err := config.SavePreferences(prefs); err != nil {
+				fmt.Println("Error saving preferences:", err)
+			}
+			fmt.Print("\nEnter command: ")
+
+		case "refresh":
+			secs := 0
+			if len(fields) > 1 {
+				if n, err := strconv.Atoi(fields[1]); err == nil {
+					secs = n
+				}
+			}
+			if secs < 1 {
+				fmt.Println("Usage: refresh <seconds>")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			tm.Lock()
+			tm.Prefs.RefreshInterval = time.Duration(secs) * time.Second
+			prefs := tm.Prefs
+			tm.Unlock()
+			if err := config.SavePreferences(prefs); err != nil {
+				fmt.Println("Error saving preferences:", err)
+			}
+			fmt.Print("\nEnter command: ")
+
+		case "gantt":
+			var num int
+			fmt.Sscanf(command, "gantt %d", &num)
+			tm.Lock()
+			if num < 1 || num > len(tm.ActiveTimers) || tm.ActiveTimers[num-1].IsStopwatch() {
+				tm.Unlock()
+				fmt.Println("Usage: gantt <number> of a timer with phases")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			config := tm.ActiveTimers[num-1].Config()
+			tm.Unlock()
+			fmt.Println()
+			fmt.Print(renderGantt(config, terminalWidth()))
+			fmt.Print("\nEnter command: ")
+
+		case "energy":
+			if len(fields) > 2 {
+				fmt.Println("Usage: energy [HOUR=LEVEL,... | reset]")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			if len(fields) == 2 {
+				var curve []int
+				if fields[1] != "reset" {
+					var err error
+					if curve, err = manager.ParseEnergyCurve(fields[1], tm.Prefs.EnergyCurve); err != nil {
+						fmt.Println(err)
+						fmt.Print("\nEnter command: ")
+						continue
+					}
+				}
+				tm.Lock()
+				tm.Prefs.EnergyCurve = curve
+				prefs := tm.Prefs
+				tm.Unlock()
+				if err := config.SavePreferences(prefs); err != nil {
+					fmt.Println("Error saving preferences:", err)
+				}
+			}
+			tm.Lock()
+			now := tm.Now()
+			notes := manager.AnnotateEnergy(tm.UpcomingTransitions(now, manager.EnergyWindow), tm.Prefs.EnergyCurve)
+			tm.Unlock()
+			fmt.Println()
+			if len(notes) == 0 {
+				fmt.Println("No transitions within", manager.EnergyWindow)
+			}
+			for _, note := range notes {
+				dip := ""
+				if note.Dip {
+					dip = " - focus dip"
+				}
+				fmt.Printf("%s %d. %s: %s starts, energy %d%s\n",
+					note.At.Format("15:04"), note.Index, note.Name, ganttRow(note.Seg), note.Level, dip)
+			}
+			fmt.Print("\nEnter command: ")
+
+		case "report":
+			tm.FlushHistory()
+			entries, err := manager.LoadHistory(tm.HistoryPath)
+			if err != nil {
+				fmt.Println("Error reading history:", err)
+			}
+			var session []manager.HistoryEntry
+			for _, entry := range entries {
+				if !entry.Time.Before(tm.Launched()) {
+					session = append(session, entry)
+				}
+			}
+			tm.Lock()
+			rows := manager.SessionReport(tm.SessionPlans(), session)
+			tm.Unlock()
+			fmt.Println()
+			if len(rows) == 0 {
+				fmt.Println("No timers this session.")
+			}
+			fmt.Print(renderReport(rows))
+			fmt.Print("\nEnter command: ")
+
+		case "stats":
+			tm.FlushHistory()
+			entries, err := manager.LoadHistory(tm.HistoryPath)
+			if err != nil {
+				fmt.Println("Error reading history:", err)
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			fmt.Println()
+			fmt.Print(renderStats(entries, tm.Now()))
+			fmt.Print("\nEnter command: ")
+
+		case "timeline":
+			tm.FlushHistory()
+			entries, err := manager.LoadHistory(tm.HistoryPath)
+			if err != nil {
+				fmt.Println("Error reading history:", err)
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			fmt.Println()
+			fmt.Print(renderTimeline(entries, tm.Now(), timelineWidth))
+			fmt.Print("\nEnter command: ")
+
+		case "combine":
+			var indices []int
+			for _, field := range fields[1:] {
+				if num, err := strconv.Atoi(field); err == nil {
+					indices = append(indices, num)
+				}
+			}
+			tm.Lock()
+			total, names, err := tm.CombineStats(indices)
+			tm.Unlock()
+			if err != nil {
+				fmt.Println(err)
+			} else {
+				fmt.Printf("\n%s: %s worked, %d cycles completed\n",
+					strings.Join(names, " + "), total.WorkTime.Round(time.Second), total.CompletedCycles)
+			}
+			fmt.Print("\nEnter command: ")
+
+		case "ending":
+			if len(fields) < 2 {
+				fmt.Println("Usage: ending <duration>")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			window, err := config.ParseDuration(fields[1])
+			if err != nil {
+				fmt.Println(config.DurationProblem(err))
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			if window <= 0 {
+				fmt.Println("The window must be longer than zero.")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			tm.Lock()
+			ending := tm.EndingWithin(window)
+			tm.Unlock()
+			fmt.Println()
+			if len(ending) == 0 {
+				fmt.Println("No timers complete within", window)
+			}
+			for _, e := range ending {
+				fmt.Printf("%d. %s - completes in %s\n", e.Index, e.Name, timer.ClockTime(e.Remaining))
+			}
+			fmt.Print("\nEnter command: ")
+
+		case "export":
+			tm.FlushHistory()
+			if err := manager.ExportCommand(fields[1:], "", os.Stdout); err != nil {
+				fmt.Println("Error exporting sessions:", err)
+			}
+			fmt.Print("\nEnter command: ")
+
+		case "export-ics":
+			if len(fields) < 2 || len(fields) > 3 {
+				fmt.Println("Usage: export-ics <file.ics> [within]")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			var since time.Time
+			if len(fields) == 3 {
+				d, err := time.ParseDuration(fields[2])
+				if err != nil || d <= 0 {
+					fmt.Println("Invalid window, use a duration like 168h.")
+					fmt.Print("\nEnter command: ")
+					continue
+				}
+				since = tm.Now().Add(-d)
+			}
+			tm.FlushHistory()
+			entries, err := manager.LoadHistory(tm.HistoryPath)
+			if err != nil {
+				fmt.Println("Error reading history:", err)
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			if err := os.WriteFile(fields[1], []byte(manager.RenderICS(entries, since, tm.Now())), 0644); err != nil {
+				fmt.Println("Error exporting calendar:", err)
+			} else {
+				fmt.Println("Exported the work sessions to", fields[1])
+			}
+			fmt.Print("\nEnter command: ")
+
+		case "calendar":
+			if len(fields) < 2 || len(fields) > 3 {
+				fmt.Println("Usage: calendar <file.ics> [window]")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			window := manager.DefaultCalendarWindow
+			if len(fields) == 3 {
+				d, err := time.ParseDuration(fields[2])
+				if err != nil || d <= 0 {
+					fmt.Println("Invalid window, use a duration like 8h.")
+					fmt.Print("\nEnter command: ")
+					continue
+				}
+				window = d
+			}
+			countdowns, err := manager.CalendarCountdowns(manager.IcsFile(fields[1]), tm.Now(), window)
+			if err != nil {
+				fmt.Println("Error reading calendar:", err)
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			tm.Lock()
+			for _, config := range countdowns {
+				// Only good for this one event, so not saved
+				t := timer.TimerFromConfig(config)
+				t.Ephemeral = true
+				tm.ActiveTimers = append(tm.ActiveTimers, t)
+			}
+			tm.Unlock()
+			tm.displayTimers(false)
+			fmt.Printf("\nAdded %d countdowns.\n", len(countdowns))
+			fmt.Print("\nEnter command: ")
+
+		case "bundle":
+			if len(fields) < 3 || (fields[1] != "export" && fields[1] != "import") ||
+				(len(fields) == 4 && (fields[1] != "import" || fields[3] != "prefs")) || len(fields) > 4 {
+				fmt.Println("Usage: bundle export <file> or bundle import <file> [prefs]")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			if fields[1] == "export" {
+				tm.Lock()
+				err := tm.ExportBundle(fields[2])
+				tm.Unlock()
+				if err != nil {
+					fmt.Println("Error exporting bundle:", err)
+				} else {
+					fmt.Println("Exported to", fields[2])
+				}
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			bundle, err := manager.ReadBundle(fields[2])
+			if err != nil {
+				fmt.Println("Error reading bundle:", err)
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			tm.Lock()
+			database := tm.Prefs.Database
+			err = tm.ImportBundle(bundle, len(fields) == 4)
+			changed := tm.Prefs.Database != database
+			database = tm.Prefs.Database
+			tm.Unlock()
+			if err != nil {
+				fmt.Println("Error importing bundle:", err)
+			}
+			if changed {
+				if err := tm.UseDatabase(database); err != nil {
+					fmt.Println("Error opening database:", err)
+				}
+			}
+			if err := tm.SaveConfigs(); err != nil {
+				fmt.Println("Error saving timer configurations:", err)
+			}
+			tm.displayTimers(false)
+			fmt.Printf("\nImported %d timers and %d templates.\n", len(bundle.Configs), len(bundle.Templates))
+			fmt.Print("\nEnter command: ")
+
+		case "plan":
+			if len(fields) < 5 || len(fields) > 6 || fields[2] != "into" || fields[4] != "tasks" {
+				fmt.Println("Usage: plan <budget> into <n> tasks [weights]")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			budget, err := config.ParseDuration(fields[1])
+			if err != nil {
+				fmt.Println("Invalid budget:", err)
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			tasks, err := strconv.Atoi(fields[3])
+			if err != nil || tasks < 1 {
+				fmt.Println("Invalid task count.")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			var weights []int
+			if len(fields) == 6 {
+				weights, err = config.ParseWeights(fields[5], tasks)
+				if err != nil {
+					fmt.Println("Invalid weights:", err)
+					fmt.Print("\nEnter command: ")
+					continue
+				}
+			}
+			planned, err := config.PlanBudget(budget, tasks, weights)
+			if err != nil {
+				fmt.Println("Error planning timers:", err)
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			tm.Lock()
+			for _, config := range planned {
+				tm.Configs = append(tm.Configs, config)
+				t := timer.TimerFromConfig(config)
+				tm.ActiveTimers = append(tm.ActiveTimers, t)
+			}
+			tm.Unlock()
+			if err := tm.SaveConfigs(); err != nil {
+				fmt.Println("Error saving timer configurations:", err)
+			}
+			tm.displayTimers(false)
+			fmt.Printf("\nPlanned %d timers over %s.\n", len(planned), timer.HoursMinutes(budget))
+			fmt.Print("\nEnter command: ")
+
+		case "import-external":
+			if len(fields) < 2 {
+				fmt.Println("Usage: import-external <file>")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			data, err := os.ReadFile(strings.TrimSpace(strings.TrimPrefix(command, fields[0])))
+			if err != nil {
+				fmt.Println("Error reading import file:", err)
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			imported, unmapped, err := manager.ImportExternal(data)
+			if err != nil {
+				fmt.Println("Error importing timers:", err)
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			tm.Lock()
+			for _, config := range imported {
+				tm.Configs = append(tm.Configs, config)
+				tm.ActiveTimers = append(tm.ActiveTimers, timer.TimerFromConfig(config))
+			}
+			tm.Unlock()
+			if err := tm.SaveConfigs(); err != nil {
+				fmt.Println("Error saving timer configurations:", err)
+			}
+			tm.displayTimers(false)
+			fmt.Printf("\nImported %d timers.\n", len(imported))
+			if len(unmapped) > 0 {
+				fmt.Println("Ignored fields:", strings.Join(unmapped, ", "))
+			}
+			fmt.Print("\nEnter command: ")
+
+		case "sync":
+			tm.Lock()
+			report := tm.SyncConfigs()
+			tm.Unlock()
+			if len(report) > 0 {
+				if err := tm.SaveConfigs(); err != nil {
+					fmt.Println("Error saving timer configurations:", err)
+				}
+			}
+			tm.displayTimers(false)
+			if len(report) == 0 {
+				fmt.Println("\nTimers and configs are in sync.")
+			}
+			for _, line := range report {
+				fmt.Println(line)
+			}
+			fmt.Print("\nEnter command: ")
+
+		case "doctor":
+			tm.Lock()
+			report := diagnose(tm.Configs)
+			tm.Unlock()
+			tm.displayTimers(false)
+			if len(report) == 0 {
+				fmt.Println("\nNo problems found.")
+			} else {
+				fmt.Printf("\n%d problems found:\n", len(report))
+			}
+			for _, line := range report {
+				fmt.Println(line)
+			}
+			fmt.Print("\nEnter command: ")
+
+		case "debug":
+			var num int
+			fmt.Sscanf(command, "debug %d", &num)
+			tm.Lock()
+			if num > 0 && num <= len(tm.ActiveTimers) {
+				fmt.Fprintln(tm.Out)
+				tm.ActiveTimers[num-1].WriteDebug(tm.Out)
+			}
+			tm.Unlock()
+			fmt.Print("\nEnter command: ")
+
+		case "s":
+			if len(fields) < 2 || len(fields) > 3 {
+				fmt.Println("Usage: s <number> [minutes]")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			num, err := strconv.Atoi(fields[1])
+			if err != nil {
+				fmt.Println("Invalid timer number.")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			d := timer.DefaultSnooze
+			if len(fields) == 3 {
+				d, err = config.ParseDuration(fields[2])
+				if err != nil || d <= 0 {
+					fmt.Println(config.DurationProblem(err))
+					fmt.Print("\nEnter command: ")
+					continue
+				}
+			}
+			tm.Lock()
+			if num > 0 && num <= len(tm.ActiveTimers) && tm.ActiveTimers[num-1].Protected() {
+				fmt.Println("Deep work cycle, it can't be snoozed.")
+			} else if num > 0 && num <= len(tm.ActiveTimers) && !tm.ActiveTimers[num-1].Snooze(d) {
+				fmt.Println("Only a work or break that counts down can be snoozed.")
+			}
+			tm.Unlock()
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+
+		case "+", "-":
+			var num int
+			var d time.Duration
+			var err error
+			if len(fields) == 3 {
+				num, _ = strconv.Atoi(fields[1])
+				d, err = config.ParseDuration(fields[2])
+			}
+			if num == 0 {
+				fmt.Printf("Usage: %s <number> <duration>\n", fields[0])
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			if err != nil || d <= 0 {
+				fmt.Println(config.DurationProblem(err))
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			if fields[0] == "-" {
+				d = -d
+			}
+			if err := tm.Extend(num, d); err != nil {
+				fmt.Println(err)
+			}
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+
+		case "snooze-all":
+			if len(fields) != 2 {
+				fmt.Println("Usage: snooze-all <duration>")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			d, err := config.ParseDuration(fields[1])
+			if err != nil || d <= 0 {
+				fmt.Println(config.DurationProblem(err))
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			names := tm.SnoozeAll(d)
+			tm.displayTimers(false)
+			if len(names) == 0 {
+				fmt.Println("\nNo running timers to snooze.")
+			}
+			fmt.Print("\nEnter command: ")
+
+		case "filter":
+			if len(fields) > 2 {
+				fmt.Println("Usage: filter [tag]")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			tm.Lock()
+			tm.Filter = ""
+			if len(fields) == 2 {
+				tm.Filter = strings.TrimPrefix(fields[1], "#")
+			}
+			tm.Unlock()
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+
+		case "pause-tag", "resume-tag":
+			if len(fields) != 2 {
+				fmt.Printf("Usage: %s <tag>\n", fields[0])
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			tm.Lock()
+			names := tm.PauseTagged(fields[1], strings.EqualFold(fields[0], "pause-tag"))
+			tm.Unlock()
+			tm.displayTimers(false)
+			if len(names) == 0 {
+				fmt.Printf("\nNo timers tagged %s to %s.\n", fields[1], strings.TrimSuffix(strings.ToLower(fields[0]), "-tag"))
+			}
+			fmt.Print("\nEnter command: ")
+
+		case "break-all":
+			names := tm.BreakAll()
+			if len(names) > 0 {
+				tm.Lock()
+				tm.SendNotification("Break time", fmt.Sprintf("Break for %s", strings.Join(names, ", ")), manager.NormalUrgency)
+				tm.Unlock()
+			}
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+
+		case "profile":
+			if len(fields) < 2 {
+				fmt.Println("Usage: profile <name> or profile clone <src> <dst>")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			if len(fields) == 4 && fields[1] == "clone" {
+				if err := tm.CloneProfile(fields[2], fields[3]); err != nil {
+					fmt.Println("Error cloning profile:", err)
+				} else {
+					fmt.Printf("Cloned %s to %s.\n", fields[2], fields[3])
+				}
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			if err := tm.SwitchProfile(fields[1]); err != nil {
+				fmt.Println("Error switching profile:", err)
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+
+		case "speed":
+			factor := 1.0
+			if len(fields) > 1 {
+				f, err := config.ParseSpeed(fields[1])
+				if err != nil {
+					fmt.Println("Error:", err)
+					fmt.Print("\nEnter command: ")
+					continue
+				}
+				factor = f
+			}
+			tm.Lock()
+			tm.Speed = factor
+			tm.Unlock()
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+
+		case "step":
+			if !tm.Stepping {
+				fmt.Println("Stepping is only available when started with -step.")
+				fmt.Print("\nEnter command: ")
+				continue
+			}
+			count := 1
+			if len(fields) > 1 {
+				if n, err := strconv.Atoi(fields[1]); err == nil && n > 0 {
+					count = n
+				}
+			}
+			tm.Step(count)
+			tm.displayTimers(false)
+			fmt.Print("\nEnter command: ")
+
+		case "q":
+			tm.Quit()
+			return
+
+		default:
+			fmt.Print("Enter command: ")
+		}
+	}
+}
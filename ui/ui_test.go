@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"multi-timer/config"
+	"multi-timer/manager"
+	"multi-timer/timer"
+)
+
+// recordingNotifier keeps the notifications and sounds instead of showing
+// and playing them
+type recordingNotifier struct {
+	notifications []string
+	sounds        []string
+}
+
+func (n *recordingNotifier) Notify(title, message string, level manager.Urgency) error {
+	n.notifications = append(n.notifications, title+": "+message)
+	return nil
+}
+
+func (n *recordingNotifier) Sound(name string) error {
+	n.sounds = append(n.sounds, name)
+	return nil
+}
+
+// newTestTerminal shows a manager that saves to a directory of its own,
+// prints nothing and runs on a clock that only moves with the ticks
+func newTestTerminal(tb testing.TB) (*Terminal, *recordingNotifier) {
+	tb.Helper()
+	saved := config.DataDir
+	config.DataDir = tb.TempDir()
+	tm := manager.NewTimerManager()
+	tb.Cleanup(func() {
+		tm.FlushHistory()
+		config.DataDir = saved
+	})
+	notifier := &recordingNotifier{}
+	tm.Notifier = notifier
+	tm.Out = io.Discard
+	start := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	tm.Now = func() time.Time { return start.Add(tm.Clock()) }
+	tm.Exit = func(int) {}
+	return NewTerminal(tm), notifier
+}
+
+// addTimers starts timers for the configs the way the a command does
+func addTimers(tm *Terminal, configs ...config.TimerConfig) []*timer.Timer {
+	var timers []*timer.Timer
+	tm.Lock()
+	for _, cfg := range configs {
+		t := timer.TimerFromConfig(cfg)
+		tm.Configs = append(tm.Configs, cfg)
+		tm.ActiveTimers = append(tm.ActiveTimers, t)
+		timers = append(timers, t)
+	}
+	tm.Unlock()
+	return timers
+}
+
+// advance runs the timers for d, a whole number of ticks
+func advance(tm *Terminal, d time.Duration) {
+	tm.Step(int(d / timer.TickInterval))
+}
+
+// pomodoro is a config of cycles of work and break, -1 for unlimited
+func pomodoro(name string, work, rest time.Duration, cycles int) config.TimerConfig {
+	return config.TimerConfig{
+		Name:      name,
+		Phases:    []config.TimerPhase{{WorkDuration: work, BreakDuration: rest}},
+		MaxCycles: cycles,
+	}
+}